@@ -0,0 +1,49 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+	"github.com/onosproject/topo-discovery/pkg/northbound/admin"
+	"github.com/spf13/cobra"
+)
+
+const (
+	formatFlag = "format"
+)
+
+func getExportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Exports the discovered fabric topology as a YAML or JSON document",
+		Long: `Exports the discovered fabric topology as a YAML or JSON document.
+
+The pods, racks, switches and servers of the document use the inventory format, so the
+document can be imported as-is to re-seed the topology.`,
+		Args: cobra.NoArgs,
+		RunE: runExportCommand,
+	}
+	addClientFlags(cmd)
+	cmd.Flags().String(formatFlag, inventory.YAML, "format of the document; yaml or json")
+	return cmd
+}
+
+func runExportCommand(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString(formatFlag)
+	conn, err := connect(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	resp, err := admin.NewDiscoveryAdminServiceClient(conn).ExportTopology(context.Background(),
+		&admin.ExportTopologyRequest{Format: format})
+	if err != nil {
+		return err
+	}
+	_, err = cmd.OutOrStdout().Write(resp.Document)
+	return err
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-lib-go/pkg/certs"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+	"github.com/onosproject/topo-discovery/pkg/northbound/admin"
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"gopkg.in/yaml.v3"
+	"time"
+)
+
+const (
+	serviceAddressFlag    = "service-address"
+	defaultServiceAddress = "topo-discovery:5150"
+	caPathFlag            = "caPath"
+	keyPathFlag           = "keyPath"
+	certPathFlag          = "certPath"
+	dryRunFlag            = "dry-run"
+	fabricSimTemplateFlag = "fabric-sim-template"
+
+	connectTimeout = 10 * time.Second
+)
+
+func getImportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <inventory-file>",
+		Short: "Imports the fabric inventory described in a YAML or JSON file",
+		Long: `Imports the fabric inventory described in a YAML or JSON file.
+
+When a fabric simulator template is given, the file is expected to be a fabric simulator
+topology file and the inventory of its switches and IPUs is derived using the template.`,
+		Args: cobra.ExactArgs(1),
+		RunE: runImportCommand,
+	}
+	addClientFlags(cmd)
+	cmd.Flags().Bool(dryRunFlag, false, "only validate the inventory without importing it")
+	cmd.Flags().String(fabricSimTemplateFlag, "", "path to the template for deriving inventory from a fabric simulator topology file")
+	return cmd
+}
+
+// Adds flags for connecting to the topo-discovery gRPC service
+func addClientFlags(cmd *cobra.Command) {
+	cmd.Flags().String(serviceAddressFlag, defaultServiceAddress, "address:port of the topo-discovery gRPC service")
+	cmd.Flags().String(caPathFlag, "", "path to CA certificate")
+	cmd.Flags().String(keyPathFlag, "", "path to client private key")
+	cmd.Flags().String(certPathFlag, "", "path to client certificate")
+}
+
+func runImportCommand(cmd *cobra.Command, args []string) error {
+	inv, err := loadInventory(cmd, args[0])
+	if err != nil {
+		return err
+	}
+	if err = inv.Validate(); err != nil {
+		return err
+	}
+	if dryRun, _ := cmd.Flags().GetBool(dryRunFlag); dryRun {
+		fmt.Fprintf(cmd.OutOrStdout(), "Inventory %s is valid\n", args[0])
+		if templatePath, _ := cmd.Flags().GetString(fabricSimTemplateFlag); len(templatePath) > 0 {
+			encoder := yaml.NewEncoder(cmd.OutOrStdout())
+			defer encoder.Close()
+			return encoder.Encode(inv)
+		}
+		return nil
+	}
+
+	conn, err := connect(cmd)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	document, err := yaml.Marshal(inv)
+	if err != nil {
+		return err
+	}
+	resp, err := admin.NewDiscoveryAdminServiceClient(conn).ImportInventory(context.Background(),
+		&admin.ImportInventoryRequest{Document: document})
+	if err != nil {
+		return err
+	}
+	failures := 0
+	for _, result := range resp.Results {
+		fmt.Fprintf(cmd.OutOrStdout(), "%-8s %-32s %-8s %s\n", result.Kind, result.ID, result.Status, result.Message)
+		if result.Status != string(inventory.OK) {
+			failures++
+		}
+	}
+	if failures > 0 {
+		return fmt.Errorf("%d of %d inventory items were not imported", failures, len(resp.Results))
+	}
+	return nil
+}
+
+// Loads the inventory either directly or from a fabric simulator topology file, if a template is given
+func loadInventory(cmd *cobra.Command, path string) (*inventory.Inventory, error) {
+	templatePath, _ := cmd.Flags().GetString(fabricSimTemplateFlag)
+	if len(templatePath) > 0 {
+		return inventory.LoadFabricSim(path, templatePath)
+	}
+	return inventory.Load(path)
+}
+
+// Connects to the topo-discovery gRPC service using the command client flags
+func connect(cmd *cobra.Command) (*grpc.ClientConn, error) {
+	address, _ := cmd.Flags().GetString(serviceAddressFlag)
+	caPath, _ := cmd.Flags().GetString(caPathFlag)
+	keyPath, _ := cmd.Flags().GetString(keyPathFlag)
+	certPath, _ := cmd.Flags().GetString(certPathFlag)
+
+	opts, err := certs.HandleCertPaths(caPath, keyPath, certPath, true)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), connectTimeout)
+	defer cancel()
+	return grpc.DialContext(ctx, address, append(opts, grpc.WithBlock())...)
+}
@@ -9,8 +9,11 @@ import (
 	"github.com/onosproject/onos-lib-go/pkg/cli"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-net-lib/pkg/realm"
+	"github.com/onosproject/topo-discovery/pkg/controller"
 	"github.com/onosproject/topo-discovery/pkg/manager"
 	"github.com/spf13/cobra"
+	"os"
+	"time"
 )
 
 var log = logging.GetLogger()
@@ -20,6 +23,38 @@ const (
 	neighborRealmValueFlag = "neighbor-realm-value"
 	topoAddressFlag        = "topo-address"
 	defaultTopoAddress     = "onos-topo:5150"
+	cablingPlanFlag        = "cabling-plan"
+	purgeRemovedFlag       = "purge-removed-devices"
+	metricsAddressFlag     = "metrics-address"
+	configFlag             = "config"
+	leaderElectionFlag     = "leader-election"
+	leaseFileFlag          = "lease-file"
+	leaseDurationFlag      = "lease-duration"
+	defaultLeaseDuration   = 15 * time.Second
+	instanceIDFlag         = "instance-id"
+	shardingFlag           = "sharding"
+	membershipDirFlag      = "membership-dir"
+	membershipTTLFlag      = "membership-ttl"
+	defaultMembershipTTL   = 15 * time.Second
+
+	queueDepthFlag           = "queue-depth"
+	workerCountFlag          = "worker-count"
+	neighborWorkerCountFlag  = "neighbor-worker-count"
+	connectionRetryPauseFlag = "connection-retry-pause"
+	sweepIntervalFlag        = "sweep-interval"
+	stateCheckIntervalFlag   = "state-check-interval"
+	minDiscoveryIntervalFlag = "min-discovery-interval"
+	initialBackoffFlag       = "initial-backoff"
+	maxBackoffFlag           = "max-backoff"
+	hostAgingTimeoutFlag     = "host-aging-timeout"
+	linkRetentionFlag        = "link-retention"
+	linkGCDryRunFlag         = "link-gc-dry-run"
+	asymmetryThresholdFlag   = "asymmetry-threshold"
+	portCountersIntervalFlag = "port-counters-interval"
+	flapPenaltyFlag          = "flap-penalty"
+	flapHalfLifeFlag         = "flap-half-life"
+	flapSuppressFlag         = "flap-suppress-threshold"
+	flapReuseFlag            = "flap-reuse-threshold"
 )
 
 // The main entry point
@@ -32,12 +67,58 @@ func main() {
 	cmd.Flags().String(neighborRealmLabelFlag, "role", "label used to find devices in neighboring realms")
 	cmd.Flags().String(neighborRealmValueFlag, "", "value of the realm label of devices in the neighboring realms")
 	cmd.Flags().String(topoAddressFlag, defaultTopoAddress, "address:port or just :port of the onos-topo service")
+	cmd.Flags().String(cablingPlanFlag, "", "path to the expected cabling plan YAML or JSON file")
+	cmd.Flags().Bool(purgeRemovedFlag, false, "remove ports, links and hosts of devices removed from onos-topo")
+	cmd.Flags().String(metricsAddressFlag, "", "address:port or just :port of the metrics endpoint; disabled if not given")
+	cmd.Flags().String(configFlag, "", "path to the controller configuration YAML or JSON file; reloaded when changed")
+	cmd.Flags().String(leaderElectionFlag, manager.NoLeaderElection, "leader election mode for active/standby replicas: none, topo or file")
+	cmd.Flags().String(leaseFileFlag, "", "path to the leadership lease file used by file-based leader election")
+	cmd.Flags().Duration(leaseDurationFlag, defaultLeaseDuration, "duration of the leadership lease; standby replicas take over once it expires")
+	cmd.Flags().String(instanceIDFlag, "", "ID of this instance in leader election or sharding; defaults to the host name")
+	cmd.Flags().String(shardingFlag, manager.NoSharding, "sharding mode for spreading devices across instances: none, topo or file")
+	cmd.Flags().String(membershipDirFlag, "", "path to the directory of instance memberships used by file-based sharding")
+	cmd.Flags().Duration(membershipTTLFlag, defaultMembershipTTL, "time-to-live of instance memberships; devices of failed instances are taken over once it expires")
+	defaults := controller.DefaultConfig()
+	cmd.Flags().Int(queueDepthFlag, defaults.QueueDepth, "maximum number of devices waiting in each discovery queue")
+	cmd.Flags().Int(workerCountFlag, defaults.WorkerCount, "number of realm discovery workers")
+	cmd.Flags().Int(neighborWorkerCountFlag, defaults.NeighborWorkerCount, "number of neighbor realm discovery workers")
+	cmd.Flags().Duration(connectionRetryPauseFlag, defaults.ConnectionRetryPause, "pause between onos-topo connection attempts")
+	cmd.Flags().Duration(sweepIntervalFlag, defaults.SweepInterval, "interval between full discovery sweeps")
+	cmd.Flags().Duration(stateCheckIntervalFlag, defaults.StateCheckInterval, "interval between controller state checks")
+	cmd.Flags().Duration(minDiscoveryIntervalFlag, defaults.MinDiscoveryInterval, "minimum interval between discovery passes for the same device")
+	cmd.Flags().Duration(initialBackoffFlag, defaults.InitialBackoff, "initial retry backoff for devices whose discovery failed")
+	cmd.Flags().Duration(maxBackoffFlag, defaults.MaxBackoff, "maximum retry backoff for devices whose discovery failed")
+	cmd.Flags().Duration(hostAgingTimeoutFlag, defaults.HostAgingTimeout, "time after which hosts no longer reported by their host agents are removed; 0 to disable")
+	cmd.Flags().Duration(linkRetentionFlag, defaults.LinkRetention, "time after which DOWN links are removed, along with links whose ports no longer exist; 0 to disable")
+	cmd.Flags().Bool(linkGCDryRunFlag, defaults.LinkGCDryRun, "only log the links which would be removed as per the link retention")
+	cmd.Flags().Duration(asymmetryThresholdFlag, defaults.AsymmetryThreshold, "time after which links seen in one direction only are flagged as asymmetric; 0 to disable")
+	cmd.Flags().Duration(portCountersIntervalFlag, defaults.PortCountersInterval, "interval between collections of the interface counters of discovered ports; 0 to disable")
+	cmd.Flags().Float64(flapPenaltyFlag, defaults.FlapPenalty, "penalty added by each status change of a link or port; 0 disables flap dampening")
+	cmd.Flags().Duration(flapHalfLifeFlag, defaults.FlapHalfLife, "time in which the flap penalty of a link or port halves")
+	cmd.Flags().Float64(flapSuppressFlag, defaults.FlapSuppressThreshold, "flap penalty from which status changes of a link or port are suppressed")
+	cmd.Flags().Float64(flapReuseFlag, defaults.FlapReuseThreshold, "flap penalty below which status changes of a suppressed link or port are applied again")
 	cli.AddServiceEndpointFlags(cmd, "discovery gRPC")
+	cmd.AddCommand(getImportCommand())
+	cmd.AddCommand(getExportCommand())
 	cli.Run(cmd)
 }
 
 func runRootCommand(cmd *cobra.Command, args []string) error {
 	topoAddress, _ := cmd.Flags().GetString(topoAddressFlag)
+	cablingPlanPath, _ := cmd.Flags().GetString(cablingPlanFlag)
+	purgeRemovedDevices, _ := cmd.Flags().GetBool(purgeRemovedFlag)
+	metricsAddress, _ := cmd.Flags().GetString(metricsAddressFlag)
+	configPath, _ := cmd.Flags().GetString(configFlag)
+	leaderElection, _ := cmd.Flags().GetString(leaderElectionFlag)
+	leaseFile, _ := cmd.Flags().GetString(leaseFileFlag)
+	leaseDuration, _ := cmd.Flags().GetDuration(leaseDurationFlag)
+	instanceID, _ := cmd.Flags().GetString(instanceIDFlag)
+	shardingMode, _ := cmd.Flags().GetString(shardingFlag)
+	membershipDir, _ := cmd.Flags().GetString(membershipDirFlag)
+	membershipTTL, _ := cmd.Flags().GetDuration(membershipTTLFlag)
+	if len(instanceID) == 0 {
+		instanceID, _ = os.Hostname()
+	}
 	neighnorRealmLabel, _ := cmd.Flags().GetString(neighborRealmLabelFlag)
 	neighborRealmValue, _ := cmd.Flags().GetString(neighborRealmValueFlag)
 	neighborRealmOptions := &realm.Options{Label: neighnorRealmLabel, Value: neighborRealmValue}
@@ -53,8 +134,44 @@ func runRootCommand(cmd *cobra.Command, args []string) error {
 		RealmOptions:         realmOptions,
 		NeighborRealmOptions: neighborRealmOptions,
 		TopoAddress:          topoAddress,
+		CablingPlanPath:      cablingPlanPath,
+		PurgeRemovedDevices:  purgeRemovedDevices,
+		MetricsAddress:       metricsAddress,
+		ControllerConfig:     extractControllerConfig(cmd),
+		ConfigPath:           configPath,
+		LeaderElection:       leaderElection,
+		LeaseFile:            leaseFile,
+		LeaseDuration:        leaseDuration,
+		InstanceID:           instanceID,
+		Sharding:             shardingMode,
+		MembershipDir:        membershipDir,
+		MembershipTTL:        membershipTTL,
 		ServiceFlags:         flags,
 	}
 
 	return cli.RunDaemon(manager.NewManager(cfg))
 }
+
+// Extracts the controller configuration from the command flags
+func extractControllerConfig(cmd *cobra.Command) controller.Config {
+	cfg := controller.DefaultConfig()
+	cfg.QueueDepth, _ = cmd.Flags().GetInt(queueDepthFlag)
+	cfg.WorkerCount, _ = cmd.Flags().GetInt(workerCountFlag)
+	cfg.NeighborWorkerCount, _ = cmd.Flags().GetInt(neighborWorkerCountFlag)
+	cfg.ConnectionRetryPause, _ = cmd.Flags().GetDuration(connectionRetryPauseFlag)
+	cfg.SweepInterval, _ = cmd.Flags().GetDuration(sweepIntervalFlag)
+	cfg.StateCheckInterval, _ = cmd.Flags().GetDuration(stateCheckIntervalFlag)
+	cfg.MinDiscoveryInterval, _ = cmd.Flags().GetDuration(minDiscoveryIntervalFlag)
+	cfg.InitialBackoff, _ = cmd.Flags().GetDuration(initialBackoffFlag)
+	cfg.MaxBackoff, _ = cmd.Flags().GetDuration(maxBackoffFlag)
+	cfg.HostAgingTimeout, _ = cmd.Flags().GetDuration(hostAgingTimeoutFlag)
+	cfg.LinkRetention, _ = cmd.Flags().GetDuration(linkRetentionFlag)
+	cfg.LinkGCDryRun, _ = cmd.Flags().GetBool(linkGCDryRunFlag)
+	cfg.AsymmetryThreshold, _ = cmd.Flags().GetDuration(asymmetryThresholdFlag)
+	cfg.PortCountersInterval, _ = cmd.Flags().GetDuration(portCountersIntervalFlag)
+	cfg.FlapPenalty, _ = cmd.Flags().GetFloat64(flapPenaltyFlag)
+	cfg.FlapHalfLife, _ = cmd.Flags().GetDuration(flapHalfLifeFlag)
+	cfg.FlapSuppressThreshold, _ = cmd.Flags().GetFloat64(flapSuppressFlag)
+	cfg.FlapReuseThreshold, _ = cmd.Flags().GetFloat64(flapReuseFlag)
+	return cfg
+}
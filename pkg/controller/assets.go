@@ -6,72 +6,414 @@
 package controller
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/types"
 	api "github.com/onosproject/onos-api/go/onos/discovery"
 	"github.com/onosproject/onos-api/go/onos/provisioner"
 	topo "github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"strconv"
 	"strings"
+	"time"
 )
 
 const (
 	controllerNotReady = "Controller not ready yet"
+
+	// Bound on the time spent undoing the changes of a failed asset operation
+	rollbackTimeout = 10 * time.Second
 )
 
-// AddPod adds a new POD entity with the requisite aspects
+// AddPod adds a new POD entity with the requisite aspects; adding an identical POD again is a no-op
 func (c *Controller) AddPod(ctx context.Context, req *api.AddPodRequest) error {
 	if c.getState() != Monitoring {
 		return errors.NewUnavailable(controllerNotReady)
 	}
-	return c.createEntity(ctx, req.ID, topo.PodKind, nil, map[string]string{topo.PodKind: req.ID})
+	return c.createAsset(ctx, func(tx *assetTx) error {
+		return tx.ensureEntity(req.ID, topo.PodKind, nil, map[string]string{topo.PodKind: req.ID})
+	})
 }
 
-// AddRack adds a new rack entity with the requisite aspects as part of a POD
+// AddRack adds a new rack entity with the requisite aspects as part of a POD; adding an identical rack again is a no-op
 func (c *Controller) AddRack(ctx context.Context, req *api.AddRackRequest) error {
 	if c.getState() != Monitoring {
 		return errors.NewUnavailable(controllerNotReady)
 	}
-	if err := c.createEntity(ctx, req.ID, topo.RackKind, nil, labels(req.PodID, req.ID)); err != nil {
-		return err
-	}
-	return c.createRelation(ctx, req.PodID, req.ID, topo.CONTAINS)
+	return c.createAsset(ctx, func(tx *assetTx) error {
+		if err := tx.ensureEntity(req.ID, topo.RackKind, nil, labels(req.PodID, req.ID)); err != nil {
+			return err
+		}
+		return tx.ensureContainment(req.PodID, req.ID)
+	})
 }
 
-// AddSwitch adds a new switch entity with the requisite aspects into a rack
+// AddSwitch adds a new switch entity with the requisite aspects into a rack; adding an existing switch again
+// updates it to match the request
 func (c *Controller) AddSwitch(ctx context.Context, req *api.AddSwitchRequest) error {
 	if c.getState() != Monitoring {
 		return errors.NewUnavailable(controllerNotReady)
 	}
 	al := aspects(req.ManagementInfo)
 	topoLabels := allLabels(req.PodID, req.RackID, req.ManagementInfo)
-	if err := c.createEntity(ctx, req.ID, topo.SwitchKind, al, topoLabels); err != nil {
-		return err
-	}
-	return c.createRelation(ctx, req.RackID, req.ID, topo.CONTAINS)
+	return c.createAsset(ctx, func(tx *assetTx) error {
+		if err := tx.ensureEntity(req.ID, topo.SwitchKind, al, topoLabels); err != nil {
+			return err
+		}
+		return tx.ensureContainment(req.RackID, req.ID)
+	})
 }
 
-// AddServerIPU adds a new server entity and an associated IPU entity, both with the requisite aspects into a rack
+// AddServerIPU adds a new server entity and an associated IPU entity, both with the requisite aspects into a rack;
+// adding an existing server IPU again updates it to match the request
 func (c *Controller) AddServerIPU(ctx context.Context, req *api.AddServerIPURequest) error {
 	if c.getState() != Monitoring {
 		return errors.NewUnavailable(controllerNotReady)
 	}
 	topoLabels := allLabels(req.PodID, req.RackID, req.ManagementInfo)
-	if err := c.createEntity(ctx, req.ID, topo.ServerKind, nil, topoLabels); err != nil {
+	ipuID := fmt.Sprintf("%s-IPU", req.ID)
+	al := aspects(req.ManagementInfo)
+	return c.createAsset(ctx, func(tx *assetTx) error {
+		if err := tx.ensureEntity(req.ID, topo.ServerKind, nil, topoLabels); err != nil {
+			return err
+		}
+		if err := tx.ensureContainment(req.RackID, req.ID); err != nil {
+			return err
+		}
+		if err := tx.ensureEntity(ipuID, topo.IPUKind, al, topoLabels); err != nil {
+			return err
+		}
+		return tx.ensureContainment(req.ID, ipuID)
+	})
+}
+
+// UpdateSwitch updates the management info aspects and labels of an existing switch entity
+func (c *Controller) UpdateSwitch(ctx context.Context, id string, info *api.ManagementInfo) error {
+	if c.getState() != Monitoring {
+		return errors.NewUnavailable(controllerNotReady)
+	}
+	object, err := c.getEntity(ctx, id, topo.SwitchKind)
+	if err != nil {
 		return err
 	}
-	if err := c.createRelation(ctx, req.RackID, req.ID, topo.CONTAINS); err != nil {
+	return c.updateManagementInfo(ctx, object, info, true)
+}
+
+// UpdateServerIPU updates the management info labels of an existing server entity and the aspects and labels
+// of its associated IPU entity
+func (c *Controller) UpdateServerIPU(ctx context.Context, id string, info *api.ManagementInfo) error {
+	if c.getState() != Monitoring {
+		return errors.NewUnavailable(controllerNotReady)
+	}
+	server, err := c.getEntity(ctx, id, topo.ServerKind)
+	if err != nil {
 		return err
 	}
+	ipu, err := c.getEntity(ctx, fmt.Sprintf("%s-IPU", id), topo.IPUKind)
+	if err != nil {
+		return err
+	}
+	if err = c.updateManagementInfo(ctx, server, info, false); err != nil {
+		return err
+	}
+	return c.updateManagementInfo(ctx, ipu, info, true)
+}
 
-	ipuID := fmt.Sprintf("%s-IPU", req.ID)
-	al := aspects(req.ManagementInfo)
-	if err := c.createEntity(ctx, ipuID, topo.IPUKind, al, topoLabels); err != nil {
+// MoveToRack moves an existing switch or server entity into a different rack of the given POD, or of its current
+// POD if none is given
+func (c *Controller) MoveToRack(ctx context.Context, id string, podID string, rackID string) error {
+	if c.getState() != Monitoring {
+		return errors.NewUnavailable(controllerNotReady)
+	}
+	object, err := c.getObject(ctx, topo.ID(id))
+	if err != nil {
+		return err
+	}
+	if object == nil {
+		return errors.NewNotFound("%s not found", id)
+	}
+	entity := object.GetEntity()
+	if entity == nil || (entity.KindID != topo.SwitchKind && entity.KindID != topo.ServerKind) {
+		return errors.NewInvalid("%s is neither a switch nor a server entity", id)
+	}
+	rack, err := c.getEntity(ctx, rackID, topo.RackKind)
+	if err != nil {
+		return err
+	}
+	if len(podID) == 0 {
+		podID = object.Labels[topo.PodKind]
+	}
+	contained, err := c.contains(ctx, podID, rack)
+	if err != nil {
+		return err
+	}
+	if !contained {
+		return errors.NewInvalid("rack %s is not part of pod %s", rackID, podID)
+	}
+
+	// Re-point the rack containment relation to the new rack, and update the pod and rack labels of the entity
+	// and of its IPU, if it has one
+	return c.createAsset(ctx, func(tx *assetTx) error {
+		if err := tx.ensureContainment(rackID, id); err != nil {
+			return err
+		}
+		if err := tx.ensureLabels(id, labels(podID, rackID)); err != nil {
+			return err
+		}
+		if entity.KindID == topo.ServerKind {
+			return tx.ensureLabels(fmt.Sprintf("%s-IPU", id), labels(podID, rackID))
+		}
+		return nil
+	})
+}
+
+// Returns true if the entity with the given ID contains the given entity
+func (c *Controller) contains(ctx context.Context, src string, target *topo.Object) (bool, error) {
+	for _, relationID := range target.GetEntity().GetTgtRelationIDs() {
+		relation, err := c.getObject(ctx, relationID)
+		if err != nil {
+			return false, err
+		}
+		if relation != nil && relation.GetRelation().GetKindID() == topo.CONTAINS &&
+			relation.GetRelation().GetSrcEntityID() == topo.ID(src) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// Rewrites the management info labels and, if requested, the aspects of the given entity
+func (c *Controller) updateManagementInfo(ctx context.Context, object *topo.Object, info *api.ManagementInfo, withAspects bool) error {
+	changed := syncLabels(object, allLabels("", "", info), "realm", "role")
+	if withAspects {
+		aspectsChanged, err := syncAspects(object, aspects(info))
+		if err != nil {
+			return err
+		}
+		changed = changed || aspectsChanged
+	}
+	if !changed {
+		return nil
+	}
+	if _, err := c.topoClient.Update(ctx, &topo.UpdateRequest{Object: object}); err != nil {
+		return errors.FromGRPC(err)
+	}
+	log.Infof("Updated management info of %s", object.ID)
+	return nil
+}
+
+// Syncs the specified label keys of the object with the given labels, removing those not present;
+// returns true if any label changed
+func syncLabels(object *topo.Object, labels map[string]string, keys ...string) bool {
+	if object.Labels == nil {
+		object.Labels = make(map[string]string)
+	}
+	changed := false
+	for _, key := range keys {
+		if object.Labels[key] != labels[key] {
+			setOrDeleteLabel(object.Labels, key, labels[key])
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Sets the label to the given value, or removes it if the value is empty
+func setOrDeleteLabel(labels map[string]string, key string, value string) {
+	if len(value) > 0 {
+		labels[key] = value
+	} else {
+		delete(labels, key)
+	}
+}
+
+// Syncs the management aspects of the object with the given aspects, removing those not present;
+// returns true if any aspect changed
+func syncAspects(object *topo.Object, aspects []proto.Message) (bool, error) {
+	desired, err := topo.NewEntity(object.ID, "").WithAspects(aspects...)
+	if err != nil {
+		return false, err
+	}
+	changed := false
+	for _, aspect := range managementAspects {
+		aspectType := proto.MessageName(aspect)
+		value := desired.GetAspectBytes(aspectType)
+		if bytes.Equal(value, object.GetAspectBytes(aspectType)) {
+			continue
+		}
+		if value != nil {
+			object.SetAspectBytes(aspectType, value)
+		} else {
+			delete(object.Aspects, aspectType)
+		}
+		changed = true
+	}
+	return changed, nil
+}
+
+// RemovedObject identifies a topology object that was removed as part of an asset removal
+type RemovedObject struct {
+	ID   topo.ID
+	Kind topo.ID
+}
+
+// RemovalReport lists all topology objects that were removed as part of an asset removal
+type RemovalReport struct {
+	Objects []RemovedObject
+}
+
+// Relation kinds whose target entities are owned by their source entity and are removed along with it
+var cascadingKinds = map[topo.ID]bool{
+	topo.CONTAINS:       true,
+	topo.HasKind:        true,
+	topo.OriginatesKind: true,
+	topo.TerminatesKind: true,
+	topo.ConnectionKind: true,
+}
+
+// RemovePod removes a POD entity along with all its racks and everything they contain
+func (c *Controller) RemovePod(ctx context.Context, id string) (*RemovalReport, error) {
+	return c.removeAsset(ctx, id, topo.PodKind)
+}
+
+// RemoveRack removes a rack entity along with all switches and servers it contains
+func (c *Controller) RemoveRack(ctx context.Context, id string) (*RemovalReport, error) {
+	return c.removeAsset(ctx, id, topo.RackKind)
+}
+
+// RemoveSwitch removes a switch entity along with its ports and any links and hosts attached to them
+func (c *Controller) RemoveSwitch(ctx context.Context, id string) (*RemovalReport, error) {
+	return c.removeAsset(ctx, id, topo.SwitchKind)
+}
+
+// RemoveServerIPU removes a server entity and its associated IPU entity, along with the IPU ports, links and hosts
+func (c *Controller) RemoveServerIPU(ctx context.Context, id string) (*RemovalReport, error) {
+	return c.removeAsset(ctx, id, topo.ServerKind)
+}
+
+// Removes the specified entity of the given kind and its entire subtree of owned entities and relations
+func (c *Controller) removeAsset(ctx context.Context, id string, kindID string) (*RemovalReport, error) {
+	if c.getState() != Monitoring {
+		return nil, errors.NewUnavailable(controllerNotReady)
+	}
+	object, err := c.getEntity(ctx, id, kindID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &RemovalReport{}
+	err = c.removeEntity(ctx, object, report, make(map[topo.ID]bool))
+	return report, err
+}
+
+// Recursively removes the targets of the entity's cascading relations, then all its relations and the entity itself
+func (c *Controller) removeEntity(ctx context.Context, object *topo.Object, report *RemovalReport, visited map[topo.ID]bool) error {
+	visited[object.ID] = true
+	entity := object.GetEntity()
+
+	// Remove all entities owned by this one first
+	for _, relationID := range entity.SrcRelationIDs {
+		relation, err := c.getObject(ctx, relationID)
+		if err != nil {
+			return err
+		}
+		if relation == nil || relation.GetRelation() == nil || !cascadingKinds[relation.GetRelation().KindID] {
+			continue
+		}
+		targetID := relation.GetRelation().TgtEntityID
+		if visited[targetID] {
+			continue
+		}
+		target, err := c.getObject(ctx, targetID)
+		if err != nil {
+			return err
+		}
+		if target != nil {
+			if err = c.removeEntity(ctx, target, report, visited); err != nil {
+				return err
+			}
+		}
+	}
+
+	// Devices may have live gNMI contexts, which the discovery workers may be using right now; have a worker
+	// tear those down once it is done with the device
+	if entity.KindID == topo.SwitchKind || entity.KindID == topo.IPUKind {
+		c.deviceRemoved(object)
+	}
+
+	// Then remove any relations still referring to this entity and the entity itself
+	relationIDs := make([]topo.ID, 0, len(entity.SrcRelationIDs)+len(entity.TgtRelationIDs))
+	relationIDs = append(relationIDs, entity.SrcRelationIDs...)
+	relationIDs = append(relationIDs, entity.TgtRelationIDs...)
+	for _, relationID := range relationIDs {
+		relation, err := c.getObject(ctx, relationID)
+		if err != nil {
+			return err
+		}
+		if relation != nil {
+			if err = c.deleteObject(ctx, relation, report); err != nil {
+				return err
+			}
+		}
+	}
+	return c.deleteObject(ctx, object, report)
+}
+
+// Returns the entity with the given ID, failing if it does not exist or is not of the specified kind
+func (c *Controller) getEntity(ctx context.Context, id string, kindID string) (*topo.Object, error) {
+	object, err := c.getObject(ctx, topo.ID(id))
+	if err != nil {
+		return nil, err
+	}
+	if object == nil {
+		return nil, errors.NewNotFound("%s %s not found", kindID, id)
+	}
+	if entity := object.GetEntity(); entity == nil || entity.KindID != topo.ID(kindID) {
+		return nil, errors.NewInvalid("%s is not a %s entity", id, kindID)
+	}
+	return object, nil
+}
+
+// Returns the topology object with the given ID, or nil if no such object exists
+func (c *Controller) getObject(ctx context.Context, id topo.ID) (*topo.Object, error) {
+	resp, err := c.topoClient.Get(ctx, &topo.GetRequest{ID: id})
+	if err != nil {
+		if err = errors.FromGRPC(err); errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return resp.Object, nil
+}
+
+// Deletes the given topology object and records it in the removal report; objects already gone are ignored
+func (c *Controller) deleteObject(ctx context.Context, object *topo.Object, report *RemovalReport) error {
+	if _, err := c.topoClient.Delete(ctx, &topo.DeleteRequest{ID: object.ID}); err != nil {
+		if err = errors.FromGRPC(err); errors.IsNotFound(err) {
+			return nil
+		}
 		return err
 	}
-	return c.createRelation(ctx, req.ID, ipuID, topo.CONTAINS)
+	kindID := topo.ID("")
+	if entity := object.GetEntity(); entity != nil {
+		kindID = entity.KindID
+	} else if relation := object.GetRelation(); relation != nil {
+		kindID = relation.KindID
+	}
+	log.Infof("Removed %s %s", kindID, object.ID)
+	report.Objects = append(report.Objects, RemovedObject{ID: object.ID, Kind: kindID})
+	return nil
+}
+
+// Aspects produced from the management info; used to clear out stale aspects on update
+var managementAspects = []proto.Message{
+	&topo.StratumAgents{},
+	&topo.P4RuntimeServer{},
+	&topo.GNMIServer{},
+	&provisioner.DeviceConfig{},
+	&topo.LocalAgents{},
 }
 
 // Produces a set of aspects for Stratum switch/IPU entity
@@ -142,21 +484,204 @@ func allLabels(pod string, rack string, info *api.ManagementInfo) map[string]str
 	return labels
 }
 
-func (c *Controller) createEntity(ctx context.Context, id string, kindID string, aspects []proto.Message, labels map[string]string) error {
-	object, err := topo.NewEntity(topo.ID(id), topo.ID(kindID)).WithAspects(aspects...)
+// Tracks changes made to topology objects while adding an asset, so that they can be rolled back if a later step fails
+type assetTx struct {
+	c    *Controller
+	ctx  context.Context
+	undo []undoStep
+}
+
+// Reverts a single change made as part of an asset transaction
+type undoStep struct {
+	change string
+	revert func(ctx context.Context) error
+}
+
+// Runs the given asset creation steps; if any of them fails, all changes made by the prior steps are reverted
+func (c *Controller) createAsset(ctx context.Context, steps func(tx *assetTx) error) error {
+	tx := &assetTx{c: c, ctx: ctx}
+	if err := steps(tx); err != nil {
+		tx.rollback()
+		return err
+	}
+	return nil
+}
+
+// Reverts all changes made as part of this transaction in reverse order; uses its own context, as the one of the
+// transaction may well have been the reason for its failure
+func (tx *assetTx) rollback() {
+	ctx, cancel := context.WithTimeout(context.Background(), rollbackTimeout)
+	defer cancel()
+	for i := len(tx.undo) - 1; i >= 0; i-- {
+		step := tx.undo[i]
+		if err := step.revert(ctx); err != nil {
+			log.Warnf("Unable to roll back %s: %+v", step.change, err)
+			continue
+		}
+		log.Infof("Rolled back %s", step.change)
+	}
+}
+
+// Records how to revert a change made by this transaction
+func (tx *assetTx) record(change string, revert func(ctx context.Context) error) {
+	tx.undo = append(tx.undo, undoStep{change: change, revert: revert})
+}
+
+// Creates the given object and records it as created by this transaction
+func (tx *assetTx) create(object *topo.Object) error {
+	resp, err := tx.c.topoClient.Create(tx.ctx, &topo.CreateRequest{Object: object})
 	if err != nil {
+		return errors.FromGRPC(err)
+	}
+	id := resp.Object.ID
+	tx.record(fmt.Sprintf("creation of %s", id), func(ctx context.Context) error {
+		_, err := tx.c.topoClient.Delete(ctx, &topo.DeleteRequest{ID: id})
 		return err
+	})
+	return nil
+}
+
+// Updates the given object, recording its prior labels and aspects so that they can be restored
+func (tx *assetTx) update(object *topo.Object, priorLabels map[string]string, priorAspects map[string]*types.Any) error {
+	if _, err := tx.c.topoClient.Update(tx.ctx, &topo.UpdateRequest{Object: object}); err != nil {
+		return errors.FromGRPC(err)
 	}
-	object.Labels = labels
-	_, err = c.topoClient.Create(ctx, &topo.CreateRequest{Object: object})
-	return err
+	id := object.ID
+	tx.record(fmt.Sprintf("update of %s", id), func(ctx context.Context) error {
+		resp, err := tx.c.topoClient.Get(ctx, &topo.GetRequest{ID: id})
+		if err != nil {
+			return err
+		}
+		resp.Object.Labels = priorLabels
+		resp.Object.Aspects = priorAspects
+		_, err = tx.c.topoClient.Update(ctx, &topo.UpdateRequest{Object: resp.Object})
+		return err
+	})
+	return nil
 }
 
-func (c *Controller) createRelation(ctx context.Context, src string, tgt string, kindID string) error {
-	if len(src) > 0 {
-		relation := topo.NewRelation(topo.ID(src), topo.ID(tgt), topo.ID(kindID), nil)
-		_, err := c.topoClient.Create(ctx, &topo.CreateRequest{Object: relation})
+// Deletes the given object, recording it so that it can be recreated
+func (tx *assetTx) delete(object *topo.Object) error {
+	if _, err := tx.c.topoClient.Delete(tx.ctx, &topo.DeleteRequest{ID: object.ID}); err != nil {
+		return errors.FromGRPC(err)
+	}
+	tx.record(fmt.Sprintf("deletion of %s", object.ID), func(ctx context.Context) error {
+		object.Revision = 0
+		_, err := tx.c.topoClient.Create(ctx, &topo.CreateRequest{Object: object})
+		return err
+	})
+	return nil
+}
+
+// Ensures that the entity exists with the given aspects and labels; creates it if it does not exist,
+// updates it if it differs and does nothing if it already matches
+func (tx *assetTx) ensureEntity(id string, kindID string, aspects []proto.Message, labels map[string]string) error {
+	object, err := tx.c.getObject(tx.ctx, topo.ID(id))
+	if err != nil {
+		return err
+	}
+
+	if object == nil {
+		object, err = topo.NewEntity(topo.ID(id), topo.ID(kindID)).WithAspects(aspects...)
+		if err != nil {
+			return err
+		}
+		object.Labels = labels
+		return tx.create(object)
+	}
+
+	if entity := object.GetEntity(); entity == nil || entity.KindID != topo.ID(kindID) {
+		return errors.NewAlreadyExists("%s already exists, but is not a %s entity", id, kindID)
+	}
+
+	priorLabels, priorAspects := copyLabels(object.Labels), copyAspects(object.Aspects)
+	labelsChanged := syncLabels(object, labels, topo.PodKind, topo.RackKind, "realm", "role")
+	aspectsChanged, err := syncAspects(object, aspects)
+	if err != nil {
+		return err
+	}
+	if !labelsChanged && !aspectsChanged {
+		return nil
+	}
+	if err = tx.update(object, priorLabels, priorAspects); err != nil {
+		return err
+	}
+	log.Infof("Updated %s %s", kindID, id)
+	return nil
+}
+
+// Ensures that the pod and rack labels of the existing entity match the given labels
+func (tx *assetTx) ensureLabels(id string, labels map[string]string) error {
+	object, err := tx.c.getObject(tx.ctx, topo.ID(id))
+	if err != nil {
 		return err
 	}
+	if object == nil {
+		return errors.NewNotFound("%s not found", id)
+	}
+	priorLabels, priorAspects := copyLabels(object.Labels), copyAspects(object.Aspects)
+	if !syncLabels(object, labels, topo.PodKind, topo.RackKind) {
+		return nil
+	}
+	return tx.update(object, priorLabels, priorAspects)
+}
+
+// Ensures that the target entity is contained by the source entity and by no other; a containment relation from
+// another source is replaced
+func (tx *assetTx) ensureContainment(src string, tgt string) error {
+	if len(src) == 0 {
+		return nil
+	}
+	target, err := tx.c.getObject(tx.ctx, topo.ID(tgt))
+	if err != nil {
+		return err
+	}
+	if target == nil || target.GetEntity() == nil {
+		return errors.NewNotFound("entity %s not found", tgt)
+	}
+
+	found := false
+	stale := make([]*topo.Object, 0)
+	for _, relationID := range target.GetEntity().TgtRelationIDs {
+		relation, err := tx.c.getObject(tx.ctx, relationID)
+		if err != nil {
+			return err
+		}
+		if relation == nil || relation.GetRelation() == nil || relation.GetRelation().KindID != topo.CONTAINS {
+			continue
+		}
+		if relation.GetRelation().SrcEntityID == topo.ID(src) {
+			found = true
+		} else {
+			stale = append(stale, relation)
+		}
+	}
+
+	if !found {
+		if err = tx.create(topo.NewRelation(topo.ID(src), topo.ID(tgt), topo.CONTAINS)); err != nil {
+			return err
+		}
+	}
+	for _, relation := range stale {
+		if err = tx.delete(relation); err != nil {
+			return err
+		}
+	}
 	return nil
 }
+
+func copyLabels(labels map[string]string) map[string]string {
+	c := make(map[string]string, len(labels))
+	for k, v := range labels {
+		c[k] = v
+	}
+	return c
+}
+
+func copyAspects(aspects map[string]*types.Any) map[string]*types.Any {
+	c := make(map[string]*types.Any, len(aspects))
+	for k, v := range aspects {
+		c[k] = v
+	}
+	return c
+}
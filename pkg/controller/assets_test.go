@@ -0,0 +1,208 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	api "github.com/onosproject/onos-api/go/onos/discovery"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func addServerIPURequest(realm string) *api.AddServerIPURequest {
+	return &api.AddServerIPURequest{
+		ID:     "server1",
+		PodID:  "pod1",
+		RackID: "rack1",
+		ManagementInfo: &api.ManagementInfo{
+			P4RTEndpoint:      "server1-ipu:9559",
+			GNMIEndpoint:      "server1-ipu:9339",
+			LinkAgentEndpoint: "server1-ipu:30000",
+			Realm:             realm,
+		},
+	}
+}
+
+func TestAddServerIPURollback(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	ctx := context.TODO()
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+
+	// Fail creation of the IPU entity; the server and its rack relation must be rolled back
+	f.failCreateAfter = 3
+	assert.Error(t, c.AddServerIPU(ctx, addServerIPURequest("")))
+	assert.False(t, f.has("server1"))
+	assert.False(t, f.has("server1-IPU"))
+	assert.False(t, f.has("rack1-contains-server1"))
+
+	// Retry should now succeed
+	assert.NoError(t, c.AddServerIPU(ctx, addServerIPURequest("")))
+	assert.True(t, f.has("server1"))
+	assert.True(t, f.has("server1-IPU"))
+	assert.True(t, f.has("rack1-contains-server1"))
+	assert.True(t, f.has("server1-contains-server1-IPU"))
+}
+
+func TestRollbackRestoresChanges(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	ctx := context.TODO()
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack2", PodID: "pod1"}))
+	assert.NoError(t, c.AddServerIPU(ctx, addServerIPURequest("")))
+	_, err := f.Delete(ctx, &topo.DeleteRequest{ID: "server1-contains-server1-IPU"})
+	assert.NoError(t, err)
+	_, err = f.Delete(ctx, &topo.DeleteRequest{ID: "server1-IPU"})
+	assert.NoError(t, err)
+
+	// Move the server to another rack with a different realm, but fail re-creation of its IPU; the server must
+	// be left in its original rack with its original labels
+	request := addServerIPURequest("edge")
+	request.RackID = "rack2"
+	f.failCreateAfter = 2
+	assert.Error(t, c.AddServerIPU(ctx, request))
+	server := getTestObject(t, f, "server1")
+	assert.Empty(t, server.Labels["realm"])
+	assert.Equal(t, "rack1", server.Labels[topo.RackKind])
+	assert.True(t, f.has("rack1-contains-server1"))
+	assert.False(t, f.has("rack2-contains-server1"))
+	assert.False(t, f.has("server1-IPU"))
+	assert.Equal(t, []topo.ID{"rack1-contains-server1"}, server.GetEntity().TgtRelationIDs)
+}
+
+func TestAddIdempotent(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	ctx := context.TODO()
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+	assert.NoError(t, c.AddServerIPU(ctx, addServerIPURequest("")))
+	creates, updates := f.creates, f.updates
+
+	// Adding the same assets again should not result in any changes
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+	assert.NoError(t, c.AddServerIPU(ctx, addServerIPURequest("")))
+	assert.Equal(t, creates, f.creates)
+	assert.Equal(t, updates, f.updates)
+
+	// Adding with a different realm should update the existing entities
+	assert.NoError(t, c.AddServerIPU(ctx, addServerIPURequest("edge")))
+	assert.Equal(t, creates, f.creates)
+	assert.Equal(t, updates+2, f.updates)
+	resp, err := f.Get(ctx, &topo.GetRequest{ID: "server1-IPU"})
+	assert.NoError(t, err)
+	assert.Equal(t, "edge", resp.Object.Labels["realm"])
+
+	// Adding an asset of a different kind under an existing ID should fail
+	assert.Error(t, c.AddSwitch(ctx, &api.AddSwitchRequest{ID: "server1", ManagementInfo: &api.ManagementInfo{}}))
+}
+
+func TestMoveToRack(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	ctx := context.TODO()
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod2"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack2", PodID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack3", PodID: "pod2"}))
+	assert.NoError(t, c.AddServerIPU(ctx, addServerIPURequest("")))
+
+	// Without a pod, the server stays in its current pod
+	assert.NoError(t, c.MoveToRack(ctx, "server1", "", "rack2"))
+	for _, id := range []topo.ID{"server1", "server1-IPU"} {
+		object := getTestObject(t, f, id)
+		assert.Equal(t, "pod1", object.Labels[topo.PodKind])
+		assert.Equal(t, "rack2", object.Labels[topo.RackKind])
+	}
+	assert.True(t, f.has("rack2-contains-server1"))
+	assert.False(t, f.has("rack1-contains-server1"))
+
+	// The rack has to be part of the pod
+	assert.True(t, errors.IsInvalid(c.MoveToRack(ctx, "server1", "", "rack3")))
+	assert.True(t, errors.IsInvalid(c.MoveToRack(ctx, "server1", "pod1", "rack3")))
+	assert.NoError(t, c.MoveToRack(ctx, "server1", "pod2", "rack3"))
+	assert.Equal(t, "pod2", getTestObject(t, f, "server1-IPU").Labels[topo.PodKind])
+
+	// Failing to relabel the IPU rolls back the move of the server
+	_, err := f.Delete(ctx, &topo.DeleteRequest{ID: "server1-IPU"})
+	assert.NoError(t, err)
+	assert.True(t, errors.IsNotFound(c.MoveToRack(ctx, "server1", "pod1", "rack1")))
+	assert.Equal(t, "rack3", getTestObject(t, f, "server1").Labels[topo.RackKind])
+	assert.True(t, f.has("rack3-contains-server1"))
+	assert.False(t, f.has("rack1-contains-server1"))
+}
+
+func TestImportInventory(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	ctx := context.TODO()
+	info := &inventory.ManagementInfo{GNMIEndpoint: "leaf1:9339"}
+	inv := &inventory.Inventory{Pods: []*inventory.Pod{{ID: "pod1", Racks: []*inventory.Rack{
+		{ID: "rack1", Switches: []*inventory.Device{{ID: "leaf1", ManagementInfo: info}}}}}}}
+
+	report, err := c.ImportInventory(ctx, inv)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, report.Failures())
+	assert.Len(t, report.Results, 3)
+	assert.True(t, f.has("rack1-contains-leaf1"))
+
+	// Invalid inventories are rejected before anything is added
+	inv.Pods = append(inv.Pods, &inventory.Pod{ID: "pod2", Racks: []*inventory.Rack{{ID: "rack1"}}})
+	_, err = c.ImportInventory(ctx, inv)
+	assert.True(t, errors.IsInvalid(err))
+	assert.False(t, f.has("pod2"))
+}
+
+func TestRemoveSwitch(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	ctx := context.TODO()
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+	for _, id := range []string{"leaf1", "spine1"} {
+		assert.NoError(t, c.AddSwitch(ctx, &api.AddSwitchRequest{ID: id, PodID: "pod1", RackID: "rack1",
+			ManagementInfo: &api.ManagementInfo{GNMIEndpoint: id + ":9339"}}))
+	}
+
+	// Stitch a port on each switch, a link between them and a host on the leaf port
+	create := func(object *topo.Object) {
+		_, err := f.Create(ctx, &topo.CreateRequest{Object: object})
+		assert.NoError(t, err)
+	}
+	create(topo.NewEntity("leaf1/1", topo.PortKind))
+	create(topo.NewEntity("spine1/1", topo.PortKind))
+	create(topo.NewRelation("leaf1", "leaf1/1", topo.HasKind))
+	create(topo.NewRelation("spine1", "spine1/1", topo.HasKind))
+	create(topo.NewEntity("spine1/1-leaf1/1", topo.LinkKind))
+	create(topo.NewRelation("spine1/1", "spine1/1-leaf1/1", topo.OriginatesKind))
+	create(topo.NewRelation("leaf1/1", "spine1/1-leaf1/1", topo.TerminatesKind))
+	create(topo.NewEntity("host1", topo.HostKind))
+	create(topo.NewRelation("leaf1/1", "host1", topo.ConnectionKind))
+
+	report, err := c.RemoveSwitch(ctx, "leaf1")
+	assert.NoError(t, err)
+	assert.Len(t, report.Objects, 4+5) // switch, port, link, host and their relations
+
+	assert.False(t, f.has("leaf1"))
+	assert.False(t, f.has("leaf1/1"))
+	assert.False(t, f.has("spine1/1-leaf1/1"))
+	assert.False(t, f.has("spine1/1-originates-spine1/1-leaf1/1"))
+	assert.False(t, f.has("host1"))
+	assert.True(t, f.has("spine1"))
+	assert.True(t, f.has("spine1/1"))
+	assert.True(t, f.has("rack1"))
+
+	// Removing a switch that is not a switch should fail
+	_, err = c.RemoveSwitch(ctx, "rack1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+)
+
+// SetCablingPlan sets the expected cabling plan against which the discovered links are checked; nil disables
+// the checks. Links are labeled with their cabling status as they are reconciled.
+func (c *Controller) SetCablingPlan(plan *inventory.CablingPlan) {
+	c.lock.Lock()
+	c.cablingPlan = plan
+	linkReconciler := c.linkReconciler
+	c.lock.Unlock()
+	if linkReconciler != nil {
+		linkReconciler.SetCablingPlan(plan)
+	}
+}
+
+// Returns the current cabling plan
+func (c *Controller) getCablingPlan() *inventory.CablingPlan {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.cablingPlan
+}
+
+// CablingReport verifies the realm's discovered links against the cabling plan, reporting the missing, extra
+// and miswired links
+func (c *Controller) CablingReport(ctx context.Context) (*inventory.CablingReport, error) {
+	plan := c.getCablingPlan()
+	if plan == nil {
+		return nil, errors.NewNotFound("no cabling plan has been loaded")
+	}
+	topology, err := c.ExportTopology(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report := plan.Verify(topology.Links, statusUp)
+	log.Infof("Verified cabling: %d missing, %d extra and %d miswired links", report.Count(inventory.CablingMissing),
+		report.Count(inventory.CablingExtra), report.Count(inventory.CablingMiswired))
+	return report, nil
+}
@@ -0,0 +1,179 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config holds the tunable parameters of the controller. Queue depth and worker counts take effect only when the
+// controller is started; all timings can be changed while the controller is running.
+type Config struct {
+	QueueDepth          int `yaml:"queue_depth" json:"queue_depth"`
+	WorkerCount         int `yaml:"worker_count" json:"worker_count"`
+	NeighborWorkerCount int `yaml:"neighbor_worker_count" json:"neighbor_worker_count"`
+
+	ConnectionRetryPause time.Duration `yaml:"connection_retry_pause" json:"connection_retry_pause"`
+	SweepInterval        time.Duration `yaml:"sweep_interval" json:"sweep_interval"`
+	StateCheckInterval   time.Duration `yaml:"state_check_interval" json:"state_check_interval"`
+
+	// Rate limit and failure backoff of successive discovery passes for the same device
+	MinDiscoveryInterval time.Duration `yaml:"min_discovery_interval" json:"min_discovery_interval"`
+	InitialBackoff       time.Duration `yaml:"initial_backoff" json:"initial_backoff"`
+	MaxBackoff           time.Duration `yaml:"max_backoff" json:"max_backoff"`
+
+	// Hosts not reported by their host agents for longer than this are removed; zero disables aging
+	HostAgingTimeout time.Duration `yaml:"host_aging_timeout" json:"host_aging_timeout"`
+
+	// Links DOWN for longer than this, or whose ports no longer exist, are removed; zero disables link removal. In
+	// dry-run mode the links which would be removed are only logged.
+	LinkRetention time.Duration `yaml:"link_retention" json:"link_retention"`
+	LinkGCDryRun  bool          `yaml:"link_gc_dry_run" json:"link_gc_dry_run"`
+
+	// Links up without the link in the opposite direction for longer than this are flagged as asymmetric; zero
+	// disables the flagging
+	AsymmetryThreshold time.Duration `yaml:"asymmetry_threshold" json:"asymmetry_threshold"`
+
+	// Interval between collections of the interface counters of discovered ports, checked along with the controller
+	// state; zero disables the collection
+	PortCountersInterval time.Duration `yaml:"port_counters_interval" json:"port_counters_interval"`
+
+	// Dampening of flapping links and ports: each status change adds the penalty, which halves every half-life;
+	// status changes are suppressed from reaching the suppress threshold until dropping below the reuse threshold.
+	// Zero penalty disables dampening.
+	FlapPenalty           float64       `yaml:"flap_penalty" json:"flap_penalty"`
+	FlapHalfLife          time.Duration `yaml:"flap_half_life" json:"flap_half_life"`
+	FlapSuppressThreshold float64       `yaml:"flap_suppress_threshold" json:"flap_suppress_threshold"`
+	FlapReuseThreshold    float64       `yaml:"flap_reuse_threshold" json:"flap_reuse_threshold"`
+}
+
+// DefaultConfig returns the default controller configuration
+func DefaultConfig() Config {
+	return Config{
+		QueueDepth:           queueDepth,
+		WorkerCount:          workerCount,
+		NeighborWorkerCount:  workerCount,
+		ConnectionRetryPause: connectionRetryPause,
+		SweepInterval:        sweepInterval,
+		StateCheckInterval:   stateCheckInterval,
+		MinDiscoveryInterval: minDiscoveryInterval,
+		InitialBackoff:       initialBackoff,
+		MaxBackoff:           maxBackoff,
+		HostAgingTimeout:     hostAgingTimeout,
+		LinkRetention:        linkRetention,
+		AsymmetryThreshold:   asymmetryThreshold,
+
+		FlapPenalty:           flapPenalty,
+		FlapHalfLife:          flapHalfLife,
+		FlapSuppressThreshold: flapSuppressThreshold,
+		FlapReuseThreshold:    flapReuseThreshold,
+	}
+}
+
+// Validate checks that all parameters are within their permitted ranges
+func (cfg Config) Validate() error {
+	problems := make([]string, 0)
+	positive := func(name string, value int64) {
+		if value <= 0 {
+			problems = append(problems, fmt.Sprintf("%s must be positive", name))
+		}
+	}
+	positive("queue_depth", int64(cfg.QueueDepth))
+	positive("worker_count", int64(cfg.WorkerCount))
+	positive("neighbor_worker_count", int64(cfg.NeighborWorkerCount))
+	positive("connection_retry_pause", int64(cfg.ConnectionRetryPause))
+	positive("sweep_interval", int64(cfg.SweepInterval))
+	positive("state_check_interval", int64(cfg.StateCheckInterval))
+	positive("initial_backoff", int64(cfg.InitialBackoff))
+	if cfg.MinDiscoveryInterval < 0 {
+		problems = append(problems, "min_discovery_interval must not be negative")
+	}
+	if cfg.HostAgingTimeout < 0 {
+		problems = append(problems, "host_aging_timeout must not be negative")
+	}
+	if cfg.LinkRetention < 0 {
+		problems = append(problems, "link_retention must not be negative")
+	}
+	if cfg.AsymmetryThreshold < 0 {
+		problems = append(problems, "asymmetry_threshold must not be negative")
+	}
+	if cfg.PortCountersInterval < 0 {
+		problems = append(problems, "port_counters_interval must not be negative")
+	}
+	if cfg.FlapPenalty < 0 {
+		problems = append(problems, "flap_penalty must not be negative")
+	}
+	positive("flap_half_life", int64(cfg.FlapHalfLife))
+	if cfg.FlapReuseThreshold <= 0 || cfg.FlapReuseThreshold >= cfg.FlapSuppressThreshold {
+		problems = append(problems, "flap_reuse_threshold must be positive and less than flap_suppress_threshold")
+	}
+	if cfg.MaxBackoff < cfg.InitialBackoff {
+		problems = append(problems, "max_backoff must not be less than initial_backoff")
+	}
+	if len(problems) > 0 {
+		return errors.NewInvalid("invalid controller configuration: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// LoadConfig reads the controller configuration from the specified YAML or JSON file; parameters missing from the
+// file retain their values from the given base configuration
+func LoadConfig(path string, base Config) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return base, err
+	}
+	return ParseConfig(data, base)
+}
+
+// ParseConfig parses and validates the controller configuration from the given YAML or JSON document; parameters
+// missing from the document retain their values from the given base configuration
+func ParseConfig(data []byte, base Config) (Config, error) {
+	cfg := base
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return base, errors.NewInvalid("unable to parse controller configuration: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		return base, err
+	}
+	return cfg, nil
+}
+
+// Configure applies the given configuration. Changes to the queue depth or worker counts of a started controller
+// take effect only after it is restarted.
+func (c *Controller) Configure(cfg Config) error {
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+	c.lock.Lock()
+	if c.realmQueue != nil && (cfg.QueueDepth != c.config.QueueDepth || cfg.WorkerCount != c.config.WorkerCount ||
+		cfg.NeighborWorkerCount != c.config.NeighborWorkerCount) {
+		log.Warnf("Queue depth and worker count changes take effect only after restart")
+	}
+	c.config = cfg
+	realmQueue, neighborRealmQueue, linkReconciler := c.realmQueue, c.neighborRealmQueue, c.linkReconciler
+	c.lock.Unlock()
+
+	if linkReconciler != nil {
+		linkReconciler.SetAsymmetryThreshold(cfg.AsymmetryThreshold)
+	}
+	realmQueue.setTimings(cfg)
+	neighborRealmQueue.setTimings(cfg)
+	c.dampener.configure(cfg)
+	log.Infof("Applied configuration %+v", cfg)
+	return nil
+}
+
+// Returns the current configuration
+func (c *Controller) getConfig() Config {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.config
+}
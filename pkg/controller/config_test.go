@@ -0,0 +1,57 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadConfig(t *testing.T) {
+	base := DefaultConfig()
+	base.WorkerCount = 32
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte("sweep_interval: 2m\nmin_discovery_interval: 0s\nqueue_depth: 512\n"), 0644))
+	cfg, err := LoadConfig(path, base)
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Minute, cfg.SweepInterval)
+	assert.Equal(t, time.Duration(0), cfg.MinDiscoveryInterval)
+	assert.Equal(t, 512, cfg.QueueDepth)
+	assert.Equal(t, 32, cfg.WorkerCount)
+	assert.Equal(t, stateCheckInterval, cfg.StateCheckInterval)
+
+	cfg, err = ParseConfig([]byte(`{"worker_count": 0, "max_backoff": "1ms"}`), base)
+	assert.True(t, errors.IsInvalid(err))
+	assert.Contains(t, err.Error(), "worker_count must be positive")
+	assert.Contains(t, err.Error(), "max_backoff must not be less than initial_backoff")
+	assert.Equal(t, base, cfg)
+
+	_, err = ParseConfig([]byte("sweep_interval: soon"), base)
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestConfigure(t *testing.T) {
+	c := newTestController(newFakeTopo())
+	c.realmQueue = newWorkQueue(realmQueueName, queueDepth)
+	defer c.realmQueue.shutDown()
+
+	cfg := DefaultConfig()
+	cfg.MinDiscoveryInterval = 5 * time.Second
+	cfg.InitialBackoff = 3 * time.Second
+	cfg.MaxBackoff = 12 * time.Second
+	assert.NoError(t, c.Configure(cfg))
+	assert.Equal(t, cfg, c.getConfig())
+	assert.Equal(t, 5*time.Second, c.realmQueue.minInterval)
+	assert.Equal(t, 12*time.Second, c.realmQueue.backoff(3))
+
+	cfg.SweepInterval = 0
+	assert.Error(t, c.Configure(cfg))
+	assert.Equal(t, sweepInterval, c.getConfig().SweepInterval)
+}
@@ -10,8 +10,11 @@ import (
 	"github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-net-lib/pkg/realm"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+	"github.com/onosproject/topo-discovery/pkg/sharding"
 	"google.golang.org/grpc"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -33,8 +36,19 @@ const (
 	Stopped
 )
 
+// Configuration defaults
 const (
 	connectionRetryPause = 5 * time.Second
+	sweepInterval        = 30 * time.Second
+	stateCheckInterval   = 2 * time.Second
+	hostAgingTimeout     = 0 // disabled
+	linkRetention        = 0 // disabled
+	asymmetryThreshold   = 5 * time.Minute
+
+	flapPenalty           = 1000
+	flapHalfLife          = 1 * time.Minute
+	flapSuppressThreshold = 2000
+	flapReuseThreshold    = 750
 
 	queueDepth  = 128
 	workerCount = 16
@@ -45,7 +59,8 @@ type Controller struct {
 	realmOptions         *realm.Options
 	neighborRealmOptions *realm.Options
 
-	state State
+	state  State
+	config Config
 
 	lock        sync.RWMutex
 	topoAddress string
@@ -55,55 +70,127 @@ type Controller struct {
 	ctx         context.Context
 	ctxCancel   context.CancelFunc
 
-	realmQueue         chan *topo.Object
-	neighborRealmQueue chan *topo.Object
+	// Lifetime of a single start/stop cycle of the controller
+	runCtx       context.Context
+	runCtxCancel context.CancelFunc
+	runDone      chan struct{}
+	workers      sync.WaitGroup
+
+	realmQueue         *workQueue
+	neighborRealmQueue *workQueue
 
-	workingOn      map[topo.ID]*topo.Object
+	removed        map[topo.ID]bool
 	portReconciler *PortReconciler
 	linkReconciler *LinkReconciler
 	hostReconciler *HostReconciler
+
+	cablingPlan *inventory.CablingPlan
+	events      *EventBroker
+	status      *statusTracker
+	dampener    *dampener
+
+	purgeRemovedDevices bool
+
+	// Indicate whether the background housekeeping tasks are running
+	hostAgingRunning      atomic.Bool
+	linkCollectionRunning atomic.Bool
+
+	// Hash ring deciding which devices this instance discovers when sharding; nil if not sharding
+	ring *sharding.Ring
 }
 
 // NewController creates a new topology discovery controller
 func NewController(realmOptions *realm.Options, neighborRealmOptions *realm.Options, topoAddress string, topoOpts ...grpc.DialOption) *Controller {
-	return &Controller{
+	c := &Controller{
 		realmOptions:         realmOptions,
 		neighborRealmOptions: neighborRealmOptions,
 		topoAddress:          topoAddress,
 		topoOpts:             append(topoOpts, grpc.WithBlock()),
-		workingOn:            make(map[topo.ID]*topo.Object),
+		config:               DefaultConfig(),
+		removed:              make(map[topo.ID]bool),
+		events:               NewEventBroker(),
+		status:               newStatusTracker(),
+		dampener:             newDampener(DefaultConfig()),
 	}
+	c.registerMetrics()
+	return c
 }
 
-// Start starts the controller
+// Start starts the controller; a stopped controller can be started again, in which case it reconnects to
+// onos-topo and rediscovers its realm from scratch
 func (c *Controller) Start() {
 	log.Infof("Starting...")
 
+	cfg := c.getConfig()
+	c.lock.Lock()
+	c.state = Disconnected
+	c.removed = make(map[topo.ID]bool)
+	c.runCtx, c.runCtxCancel = context.WithCancel(context.Background())
+	c.runDone = make(chan struct{})
+	c.lock.Unlock()
+
 	// Crate realm discovery job queue and workers
-	c.realmQueue = make(chan *topo.Object, queueDepth)
-	for i := 0; i < workerCount; i++ {
-		go c.discover(i)
+	realmQueue := newWorkQueue(realmQueueName, cfg.QueueDepth)
+	realmQueue.setTimings(cfg)
+	c.lock.Lock()
+	c.realmQueue = realmQueue
+	c.lock.Unlock()
+	c.workers.Add(cfg.WorkerCount)
+	for i := 0; i < cfg.WorkerCount; i++ {
+		go c.discover(i, realmQueue)
 	}
 
 	if c.hasNeighborRealmOptions() {
 		// Crate neighbor realm queue and workers
-		c.neighborRealmQueue = make(chan *topo.Object, queueDepth)
-		for i := 0; i < workerCount; i++ {
-			go c.discoverNeighbor(i)
+		neighborRealmQueue := newWorkQueue(neighborQueueName, cfg.QueueDepth)
+		neighborRealmQueue.setTimings(cfg)
+		c.lock.Lock()
+		c.neighborRealmQueue = neighborRealmQueue
+		c.lock.Unlock()
+		c.workers.Add(cfg.NeighborWorkerCount)
+		for i := 0; i < cfg.NeighborWorkerCount; i++ {
+			go c.discoverNeighbor(i, neighborRealmQueue)
 		}
 	}
 
-	go c.run()
+	go c.run(c.runCtx, c.runDone)
 }
 
-// Stop stops the controller
+// Stop stops the controller, waiting for its event loop and workers to finish and releasing all southbound
+// discovery state
 func (c *Controller) Stop() {
 	log.Infof("Stopping...")
 	c.setState(Stopped)
-	close(c.realmQueue)
+
+	c.lock.RLock()
+	runCtxCancel, runDone := c.runCtxCancel, c.runDone
+	realmQueue, neighborRealmQueue := c.realmQueue, c.neighborRealmQueue
+	c.lock.RUnlock()
+	if runCtxCancel == nil {
+		return
+	}
+	runCtxCancel()
+	realmQueue.shutDown()
 	if c.hasNeighborRealmOptions() {
-		close(c.neighborRealmQueue)
+		neighborRealmQueue.shutDown()
+	}
+	<-runDone
+
+	// With the event loop finished, abort any onos-topo calls still made by the workers and wait for them
+	if c.ctxCancel != nil {
+		c.ctxCancel()
 	}
+	c.workers.Wait()
+	if c.portReconciler != nil {
+		c.portReconciler.portDiscovery.ReleaseAll()
+		c.linkReconciler.linkDiscovery.ReleaseAll()
+		c.hostReconciler.hostDiscovery.ReleaseAll()
+	}
+	if c.conn != nil {
+		_ = c.conn.Close()
+		c.conn = nil
+	}
+	log.Infof("Stopped")
 }
 
 // Get the current operational state
@@ -137,45 +224,70 @@ func (c *Controller) pauseIf(condition State, pause time.Duration) {
 	}
 }
 
-// Runs the main controller event loop
-func (c *Controller) run() {
+// Runs the main controller event loop until the controller is stopped; closes the done channel on exit
+func (c *Controller) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
 	log.Infof("Started")
 	for state := c.getState(); state != Stopped; state = c.getState() {
 		switch state {
 		case Disconnected:
-			c.waitForTopoConnection()
+			c.waitForTopoConnection(ctx)
 		case Connected:
 			c.runInitialDiscoverySweep()
 		case Initialized:
 			c.prepareForMonitoring()
 		case Monitoring:
-			c.monitorTopologyChanges()
+			c.monitorTopologyChanges(ctx)
 		}
 	}
-	log.Infof("Stopped")
 }
 
 // Handles processing for Disconnected state by attempting to establish connection to onos-topo
-func (c *Controller) waitForTopoConnection() {
+func (c *Controller) waitForTopoConnection(ctx context.Context) {
 	log.Infof("Connecting to onos-topo at %s...", c.topoAddress)
 	for c.getState() == Disconnected {
-		if conn, err := grpc.DialContext(context.Background(), c.topoAddress, c.topoOpts...); err == nil {
+		if conn, err := grpc.DialContext(ctx, c.topoAddress, c.topoOpts...); err == nil {
 			c.conn = conn
 			c.topoClient = topo.CreateTopoClient(conn)
 			c.ctx, c.ctxCancel = context.WithCancel(context.Background())
 			c.portReconciler = NewPortReconciler(c.ctx, c.topoClient)
 			c.linkReconciler = NewLinkReconciler(c.ctx, c.topoClient)
+			c.linkReconciler.SetCablingPlan(c.getCablingPlan())
+			c.linkReconciler.SetAsymmetryThreshold(c.getConfig().AsymmetryThreshold)
+			c.linkReconciler.SetAgentIDSharing(c.getRing() != nil)
 			c.hostReconciler = NewHostReconciler(c.ctx, c.topoClient)
+			c.portReconciler.events = c.events
+			c.linkReconciler.events = c.events
+			c.hostReconciler.events = c.events
+			c.hostReconciler.realm = c.realmOptions.Value
+			c.hostReconciler.linkReconciler = c.linkReconciler
+			c.portReconciler.dampener = c.dampener
+			c.linkReconciler.dampener = c.dampener
+			c.portReconciler.linkReconciler = c.linkReconciler
 			c.setState(Connected)
 			log.Infof("Connected")
 		} else {
 			log.Warnf("Unable to connect to onos-topo: %+v", err)
-			c.pauseIf(Disconnected, connectionRetryPause)
+			c.pauseIf(Disconnected, c.getConfig().ConnectionRetryPause)
 		}
 	}
 }
 
+// WatchEvents returns a channel of discovery events passing the given filter; the channel is closed when the
+// context is done
+func (c *Controller) WatchEvents(ctx context.Context, filter *EventFilter) <-chan *Event {
+	return c.events.Watch(ctx, filter)
+}
+
 // Returns true if the neighbor realm value has been specified
 func (c *Controller) hasNeighborRealmOptions() bool {
-	return c.neighborRealmOptions.Value != ""
+	return c.neighborRealmOptions != nil && c.neighborRealmOptions.Value != ""
+}
+
+// Releases all southbound discovery state held for the specified device
+func (c *Controller) releaseDevice(id topo.ID) {
+	c.portReconciler.ReleaseDevice(id)
+	c.linkReconciler.ReleaseDevice(id)
+	c.hostReconciler.ReleaseDevice(id)
+	c.status.remove(id)
 }
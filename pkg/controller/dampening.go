@@ -0,0 +1,183 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/metrics"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	// Label marking links and ports whose status changes are suppressed because they are flapping
+	flappingLabel = "flapping"
+
+	// The penalty is capped at this multiple of the suppress threshold, bounding how long suppression lasts
+	maxPenaltyFactor = 4
+)
+
+var flapsTotal = metrics.NewCounter("topo_discovery_flaps_total",
+	"Number of status changes of links and ports, by kind", "kind")
+
+// Flap history of a single link or port
+type flapState struct {
+	kind       string
+	status     string
+	penalty    float64
+	updated    time.Time
+	suppressed bool
+	flaps      uint64
+
+	// The port itself, or the ports at which the link originates and terminates, as far as known
+	ports []topo.ID
+}
+
+// Dampens status changes of flapping links and ports. Each change of the observed status adds a penalty which
+// decays exponentially with the configured half-life; once the penalty reaches the suppress threshold, further
+// status changes are not applied to onos-topo until it decays below the reuse threshold.
+type dampener struct {
+	lock     sync.Mutex
+	penalty  float64
+	halfLife time.Duration
+	suppress float64
+	reuse    float64
+	states   map[topo.ID]*flapState
+}
+
+// Creates a dampener with the thresholds of the given configuration
+func newDampener(cfg Config) *dampener {
+	d := &dampener{states: make(map[topo.ID]*flapState)}
+	d.configure(cfg)
+	return d
+}
+
+// Applies the thresholds of the given configuration
+func (d *dampener) configure(cfg Config) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.penalty = cfg.FlapPenalty
+	d.halfLife = cfg.FlapHalfLife
+	d.suppress = cfg.FlapSuppressThreshold
+	d.reuse = cfg.FlapReuseThreshold
+}
+
+// Records the observed status of the link or port of the given kind, along with its ports; returns true if its
+// status changes are suppressed
+func (d *dampener) observe(kind string, id topo.ID, status string, ports ...topo.ID) bool {
+	if d == nil {
+		return false
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	now := time.Now()
+	s, ok := d.states[id]
+	if !ok {
+		d.states[id] = &flapState{kind: kind, status: status, updated: now, ports: append([]topo.ID{}, ports...)}
+		return false
+	}
+	for _, port := range ports {
+		if !containsID(s.ports, port) {
+			s.ports = append(s.ports, port)
+		}
+	}
+	d.decay(s, now)
+	if s.status != status {
+		s.status = status
+		s.flaps++
+		flapsTotal.Inc(kind)
+		if d.penalty > 0 {
+			s.penalty = math.Min(s.penalty+d.penalty, maxPenaltyFactor*d.suppress)
+		}
+	}
+	if !s.suppressed && d.penalty > 0 && s.penalty >= d.suppress {
+		log.Warnf("Suppressing status changes of flapping %s %s after %d flaps", kind, id, s.flaps)
+		s.suppressed = true
+	} else if s.suppressed && (d.penalty == 0 || s.penalty < d.reuse) {
+		log.Infof("No longer suppressing status changes of %s %s", kind, id)
+		s.suppressed = false
+	}
+	return s.suppressed
+}
+
+// Decays the penalty as of the given time; must be called with the lock held
+func (d *dampener) decay(s *flapState, now time.Time) {
+	if d.halfLife > 0 && s.penalty > 0 {
+		s.penalty *= math.Exp2(-float64(now.Sub(s.updated)) / float64(d.halfLife))
+	}
+	s.updated = now
+}
+
+// Forgets the flap history of the ports of the given device and of the links originating or terminating at them
+func (d *dampener) forget(deviceID topo.ID) {
+	if d == nil {
+		return
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	for id, s := range d.states {
+		for _, port := range s.ports {
+			if portDeviceID(port) == deviceID {
+				delete(d.states, id)
+				break
+			}
+		}
+	}
+}
+
+// Returns the flap history of the links and ports which changed their status at least once, ordered by ID
+func (d *dampener) flapStatus() []*FlapStatus {
+	if d == nil {
+		return nil
+	}
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	now := time.Now()
+	flaps := make([]*FlapStatus, 0, len(d.states))
+	for id, s := range d.states {
+		if s.flaps == 0 {
+			continue
+		}
+		d.decay(s, now)
+		flaps = append(flaps, &FlapStatus{ID: id, Kind: s.kind, Status: s.status, Flaps: s.flaps,
+			Penalty: s.penalty, Suppressed: s.suppressed})
+	}
+	sort.Slice(flaps, func(i, j int) bool { return flaps[i].ID < flaps[j].ID })
+	return flaps
+}
+
+// Returns true if the list contains the given ID
+func containsID(ids []topo.ID, id topo.ID) bool {
+	for _, other := range ids {
+		if other == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Labels the link or port entity as flapping, along with syncing the given labels, while its status changes are
+// suppressed
+func markFlapping(ctx context.Context, topoClient topo.TopoClient, reconciler string, object *topo.Object,
+	labels map[string]string, keys ...string) {
+	flapping := map[string]string{flappingLabel: "true"}
+	for _, key := range keys {
+		flapping[key] = labels[key]
+	}
+	if !syncLabels(object, flapping, append(keys, flappingLabel)...) {
+		return
+	}
+	if _, err := topoClient.Update(ctx, &topo.UpdateRequest{Object: object}); err != nil {
+		log.Warnf("Unable to mark %s as flapping: %+v", object.ID, err)
+		return
+	}
+	countOperation(reconciler, updateOperation)
+}
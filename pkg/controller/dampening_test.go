@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// Returns the configuration suppressing status changes from the second flap on
+func testDampeningConfig() Config {
+	cfg := DefaultConfig()
+	cfg.FlapSuppressThreshold = 1500
+	return cfg
+}
+
+func TestDampener(t *testing.T) {
+	d := newDampener(testDampeningConfig())
+
+	// The first observation only records the status; each change adds the penalty
+	assert.False(t, d.observe(linkReconcilerName, "l1", statusUp))
+	assert.False(t, d.observe(linkReconcilerName, "l1", statusUp))
+	assert.False(t, d.observe(linkReconcilerName, "l1", statusDown))
+	assert.Equal(t, uint64(1), d.states["l1"].flaps)
+
+	// Reaching the suppress threshold suppresses further changes, while the penalty is capped
+	assert.True(t, d.observe(linkReconcilerName, "l1", statusUp))
+	for i := 0; i < 10; i++ {
+		assert.True(t, d.observe(linkReconcilerName, "l1", []string{statusDown, statusUp}[i%2]))
+	}
+	assert.Equal(t, uint64(12), d.states["l1"].flaps)
+	assert.LessOrEqual(t, d.states["l1"].penalty, maxPenaltyFactor*d.suppress)
+
+	// Suppression is lifted only once the penalty decays below the reuse threshold
+	d.states["l1"].updated = time.Now().Add(-2 * flapHalfLife)
+	assert.True(t, d.observe(linkReconcilerName, "l1", statusUp))
+	d.states["l1"].updated = time.Now().Add(-2 * flapHalfLife)
+	assert.False(t, d.observe(linkReconcilerName, "l1", statusUp))
+
+	// Other objects are not affected, and dampening can be disabled
+	assert.False(t, d.observe(portReconcilerName, "p1", statusUp, "d1/1"))
+	cfg := testDampeningConfig()
+	cfg.FlapPenalty = 0
+	d.configure(cfg)
+	for i := 0; i < 10; i++ {
+		assert.False(t, d.observe(portReconcilerName, "p1", []string{statusDown, statusUp}[i%2]))
+	}
+
+	// Only objects which changed their status are reported
+	flaps := d.flapStatus()
+	assert.Len(t, flaps, 2)
+	assert.Equal(t, &FlapStatus{ID: "l1", Kind: linkReconcilerName, Status: statusUp, Flaps: 12,
+		Penalty: flaps[0].Penalty}, flaps[0])
+	assert.Equal(t, topo.ID("p1"), flaps[1].ID)
+	assert.Equal(t, portReconcilerName, flaps[1].Kind)
+	assert.Equal(t, uint64(10), flaps[1].Flaps)
+
+	// Forgetting a device forgets the objects at its ports
+	d.forget("d1")
+	assert.Len(t, d.states, 1)
+
+	var none *dampener
+	assert.False(t, none.observe(linkReconcilerName, "l1", statusDown))
+	assert.Empty(t, none.flapStatus())
+}
+
+func TestLinkDampening(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	c.dampener.configure(testDampeningConfig())
+	r := c.linkReconciler
+	events := c.events.Watch(context.Background(), &EventFilter{Types: []EventType{LinkUp, LinkDown}})
+	r.registerReport(topo.NewEntity("ta", topo.SwitchKind), &southbound.LinkReport{AgentID: "a"})
+	r.registerReport(topo.NewEntity("tb", topo.SwitchKind), &southbound.LinkReport{AgentID: "b"})
+
+	report := func(status string) {
+		link := &southbound.Link{IngressDevice: "a", IngressPort: 1, EgressDevice: "b", EgressPort: 1,
+			CreateTime: uint64(time.Now().UnixNano())}
+		r.reconcileLink(link, status)
+	}
+	linkStatus := func() (string, string) {
+		link := getTestObject(t, f, "tb/1-ta/1")
+		linkAspect := &topo.Link{}
+		assert.NoError(t, link.GetAspect(linkAspect))
+		return linkAspect.Status, link.Labels[flappingLabel]
+	}
+
+	// The first changes are applied right away
+	report(statusUp)
+	report(statusDown)
+	assert.Equal(t, LinkUp, (<-events).Type)
+	assert.Equal(t, LinkDown, (<-events).Type)
+
+	// Once flapping, the link is labeled as such and further changes are held back
+	report(statusUp)
+	status, flapping := linkStatus()
+	assert.Equal(t, statusDown, status)
+	assert.Equal(t, "true", flapping)
+	updates := f.updates
+	report(statusDown)
+	report(statusUp)
+	assert.Equal(t, updates, f.updates)
+	assert.Len(t, events, 0)
+
+	// Once settled, the current status is applied and the label removed
+	c.dampener.lock.Lock()
+	c.dampener.states["tb/1-ta/1"].updated = time.Now().Add(-10 * flapHalfLife)
+	c.dampener.lock.Unlock()
+	report(statusUp)
+	status, flapping = linkStatus()
+	assert.Equal(t, statusUp, status)
+	assert.Empty(t, flapping)
+	assert.Equal(t, LinkUp, (<-events).Type)
+
+	// Releasing either device forgets the flap history of the link
+	c.portReconciler.ReleaseDevice("ta")
+	assert.Empty(t, c.dampener.states)
+}
+
+func TestPortDampening(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	c.dampener.configure(testDampeningConfig())
+	r := c.portReconciler
+	leaf1 := topo.NewEntity("leaf1", topo.SwitchKind)
+	r.createPort(leaf1, "leaf1/1", &topo.Port{Number: 1, Status: statusUp}, nil)
+
+	update := func(status string) {
+		r.updatePortIfNeeded(leaf1, getTestObject(t, f, "leaf1/1"), &topo.Port{Number: 1, Status: status}, nil)
+	}
+	portStatus := func() (string, string) {
+		port := getTestObject(t, f, "leaf1/1")
+		portAspect := &topo.Port{}
+		assert.NoError(t, port.GetAspect(portAspect))
+		return portAspect.Status, port.Labels[flappingLabel]
+	}
+
+	update(statusDown)
+	update(statusUp)
+	status, flapping := portStatus()
+	assert.Equal(t, statusDown, status)
+	assert.Equal(t, "true", flapping)
+
+	c.dampener.lock.Lock()
+	c.dampener.states["leaf1/1"].updated = time.Now().Add(-10 * flapHalfLife)
+	c.dampener.lock.Unlock()
+	update(statusUp)
+	status, flapping = portStatus()
+	assert.Equal(t, statusUp, status)
+	assert.Empty(t, flapping)
+
+	// Releasing the device forgets the flap history of its ports
+	r.ReleaseDevice("leaf1")
+	assert.Empty(t, c.dampener.states)
+}
@@ -5,9 +5,11 @@
 package controller
 
 import (
+	"context"
 	"github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-net-lib/pkg/realm"
 	"io"
+	"sync/atomic"
 	"time"
 )
 
@@ -17,18 +19,35 @@ func (c *Controller) runInitialDiscoverySweep() {
 			c.setState(Initialized)
 		} else {
 			log.Warnf("Unable to query onos-topo: %+v", err)
-			c.pauseIf(Disconnected, connectionRetryPause)
+			c.pauseIf(Disconnected, c.getConfig().ConnectionRetryPause)
 		}
 	}
 }
 
-// Runs discovery sweep for all objects in our realm
+// Runs discovery sweep for all objects in our realm and records its outcome
 func (c *Controller) runFullDiscoverySweep() error {
+	start := time.Now()
+	err := c.sweepRealm()
+	c.status.recordSweep(start, err)
+	sweepDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		sweepsTotal.Inc("error")
+	} else {
+		sweepsTotal.Inc("ok")
+	}
+	return err
+}
+
+// Enqueues all objects in our realm for discovery; devices removed in the meantime are skipped, as the query may
+// have started before their removal, and only a watch event announcing their creation clears their removal
+func (c *Controller) sweepRealm() error {
 	log.Info("Starting full discovery sweep...")
 	if entities, err := c.topoClient.Query(c.ctx, &topo.QueryRequest{Filters: queryFilter(c.realmOptions)}); err == nil {
 		for c.getState() != Stopped {
 			if entity, err := entities.Recv(); err == nil {
-				c.realmQueue <- entity.Object
+				if !c.isRemoved(entity.Object.ID) && c.shouldDiscover(entity.Object.ID) {
+					c.realmQueue.add(&discoveryJob{object: entity.Object})
+				}
 			} else {
 				if err == io.EOF {
 					log.Info("Completed full discovery sweep")
@@ -51,13 +70,27 @@ func queryFilter(realmOptions *realm.Options) *topo.Filters {
 
 // Setup watch for updates using onos-topo API
 func (c *Controller) prepareForMonitoring() {
-	c.monitorRealm(c.realmOptions, c.realmQueue)
+	c.monitorRealm(c.realmOptions, func(object *topo.Object, eventType topo.EventType) {
+		if eventType == topo.EventType_ADDED {
+			c.deviceExists(object.ID)
+		} else if c.isRemoved(object.ID) {
+			return
+		}
+		if c.shouldDiscover(object.ID) {
+			c.realmQueue.add(&discoveryJob{object: object})
+		}
+	}, c.deviceRemoved)
 	if c.getState() == Monitoring && c.hasNeighborRealmOptions() {
-		c.monitorRealm(c.neighborRealmOptions, c.neighborRealmQueue)
+		c.monitorRealm(c.neighborRealmOptions, func(object *topo.Object, eventType topo.EventType) {
+			c.neighborRealmQueue.add(&discoveryJob{object: object})
+		}, c.neighborRemoved)
 	}
 }
 
-func (c *Controller) monitorRealm(realmOptions *realm.Options, realmQueue chan<- *topo.Object) {
+// Watches the objects of the given realm, passing added and updated objects to the enqueue function, along with the
+// event type, and removed objects to the remove function
+func (c *Controller) monitorRealm(realmOptions *realm.Options, enqueue func(object *topo.Object, eventType topo.EventType),
+	remove func(object *topo.Object)) {
 	filter := queryFilter(realmOptions)
 	log.Infof("Starting to watch onos-topo via %+v", filter)
 	stream, err := c.topoClient.Watch(c.ctx, &topo.WatchRequest{Filters: filter})
@@ -68,9 +101,11 @@ func (c *Controller) monitorRealm(realmOptions *realm.Options, realmQueue chan<-
 		go func() {
 			for c.getState() == Monitoring {
 				resp, err := stream.Recv()
-				if err == nil && isRelevant(resp.Event) {
-					realmQueue <- &resp.Event.Object
-				} else if err != nil {
+				if err == nil && isRemoval(resp.Event) {
+					remove(&resp.Event.Object)
+				} else if err == nil {
+					enqueue(&resp.Event.Object, resp.Event.Type)
+				} else {
 					log.Warnf("Watch stream has been stopped: %+v", err)
 					c.setStateIf(Monitoring, Disconnected)
 				}
@@ -80,75 +115,169 @@ func (c *Controller) monitorRealm(realmOptions *realm.Options, realmQueue chan<-
 	}
 }
 
-// Returns true if the object is relevant to the controller
-func isRelevant(event topo.Event) bool {
-	return event.Type != topo.EventType_REMOVED
+// Returns true if the event signals removal of the object
+func isRemoval(event topo.Event) bool {
+	return event.Type == topo.EventType_REMOVED
 }
 
-func (c *Controller) monitorTopologyChanges() {
-	tPeriodic := time.NewTicker(30 * time.Second)
-	tCheckState := time.NewTicker(2 * time.Second)
+func (c *Controller) monitorTopologyChanges(ctx context.Context) {
+	cfg := c.getConfig()
+	tPeriodic := time.NewTicker(cfg.SweepInterval)
+	tCheckState := time.NewTicker(cfg.StateCheckInterval)
+	defer tPeriodic.Stop()
+	defer tCheckState.Stop()
+	var countersCollected time.Time
 
 	for c.getState() == Monitoring {
+		// Pick up any changes to the sweep and state check intervals
+		if latest := c.getConfig(); latest != cfg {
+			if latest.SweepInterval != cfg.SweepInterval {
+				log.Infof("Changing full discovery sweep interval to %s", latest.SweepInterval)
+				tPeriodic.Reset(latest.SweepInterval)
+			}
+			if latest.StateCheckInterval != cfg.StateCheckInterval {
+				tCheckState.Reset(latest.StateCheckInterval)
+			}
+			cfg = latest
+		}
+
 		select {
-		// Periodically run a full discovery sweep
+		// Periodically run a full discovery sweep and expire stale hosts and links
 		case <-tPeriodic.C:
 			_ = c.runFullDiscoverySweep()
+			if timeout := cfg.HostAgingTimeout; timeout > 0 {
+				c.runInBackground("host aging", &c.hostAgingRunning, func() {
+					c.hostReconciler.ExpireHosts(timeout, c.owns)
+				})
+			}
+			if gcCfg := cfg; gcCfg.LinkRetention > 0 {
+				c.runInBackground("link collection", &c.linkCollectionRunning, func() {
+					c.collectLinks(gcCfg)
+				})
+			}
 
-		// Periodically pop-out to check state
+		// Periodically pop-out to check state and collect port counters when due
 		case <-tCheckState.C:
+			if cfg.PortCountersInterval > 0 && time.Since(countersCollected) >= cfg.PortCountersInterval {
+				c.collectCounters()
+				countersCollected = time.Now()
+			}
+
+		// Stop right away when the controller is stopped
+		case <-ctx.Done():
 		}
 	}
 }
 
+// Runs the housekeeping task in the background, so that its onos-topo queries do not hold up the event loop; the
+// task is skipped while its previous run is still in progress. Must be called from the event loop, as the
+// controller waits for the task along with the workers when stopped.
+func (c *Controller) runInBackground(name string, running *atomic.Bool, task func()) {
+	if !running.CompareAndSwap(false, true) {
+		log.Infof("Skipping %s; its previous run is still in progress", name)
+		return
+	}
+	c.workers.Add(1)
+	go func() {
+		defer c.workers.Done()
+		defer running.Store(false)
+		task()
+	}()
+}
+
 // Discovery worker
-func (c *Controller) discover(workerID int) {
-	for object := range c.realmQueue {
-		c.lock.Lock()
-
-		// Is this object being worked on already?
-		_, busy := c.workingOn[object.ID]
-		if !busy {
-			// If not, mark it as being worked on.
-			c.workingOn[object.ID] = object
+func (c *Controller) discover(workerID int, queue *workQueue) {
+	defer c.workers.Done()
+	for {
+		job, ok := queue.get()
+		if !ok {
+			return
 		}
-		c.lock.Unlock()
-		if !busy {
-			log.Infof("%d: Working on %s", workerID, object.ID)
-			c.portReconciler.DiscoverPorts(object)
-			c.linkReconciler.DiscoverLinks(object)
-			c.hostReconciler.DiscoverHosts(object)
-			log.Infof("%d: Finished work on %s", workerID, object.ID)
-
-			// We're done working on this object
-			c.lock.Lock()
-			delete(c.workingOn, object.ID)
-			c.lock.Unlock()
+		object := job.object
+
+		if job.removal {
+			log.Infof("%d: Tearing down %s", workerID, object.ID)
+			c.tearDownDevice(object)
+			queue.done(object.ID, false)
+			queue.forget(object.ID)
+			job.reply(&DeviceResult{DeviceID: object.ID, Error: deviceHasBeenRemoved})
+			continue
+		}
+
+		// Skip any lingering jobs for devices that have been removed in the meantime
+		if c.isRemoved(object.ID) {
+			queue.done(object.ID, false)
+			job.reply(&DeviceResult{DeviceID: object.ID, Error: deviceHasBeenRemoved})
+			continue
 		}
+
+		// Release devices which have moved to another instance and skip those owned by other instances
+		if !c.owns(object.ID) {
+			if c.status.has(object.ID) {
+				log.Infof("%d: Releasing %s, now owned by another instance", workerID, object.ID)
+				c.releaseDevice(object.ID)
+			}
+			queue.done(object.ID, false)
+			queue.forget(object.ID)
+			job.reply(&DeviceResult{DeviceID: object.ID, Error: c.notOwned(object.ID)})
+			continue
+		}
+
+		// Counter jobs leave the failure history of the device be, so as not to cut its discovery backoff short
+		if job.counters {
+			_ = c.portReconciler.CollectCounters(object.ID)
+			queue.release(object.ID)
+			continue
+		}
+
+		log.Infof("%d: Working on %s", workerID, object.ID)
+		if job.reconnect {
+			log.Infof("%d: Dropping connections of %s", workerID, object.ID)
+			c.disconnectDevice(object.ID)
+		}
+		result := &DeviceResult{DeviceID: object.ID}
+		result.addPhase(PortsPhase, c.portReconciler.DiscoverPorts, object)
+		result.addPhase(LinksPhase, c.linkReconciler.DiscoverLinks, object)
+		result.addPhase(HostsPhase, c.hostReconciler.DiscoverHosts, object)
+		log.Infof("%d: Finished work on %s", workerID, object.ID)
+		c.status.recordResult(result)
+
+		// We're done working on this object; failures will be retried with backoff
+		queue.done(object.ID, result.Failed())
+		job.reply(result)
 	}
 }
 
 // Neighbor discovery worker
-func (c *Controller) discoverNeighbor(workerID int) {
-	for object := range c.neighborRealmQueue {
-		c.lock.Lock()
-
-		// Is this object being worked on already?
-		_, busy := c.workingOn[object.ID]
-		if !busy {
-			// If not, mark it as being worked on.
-			c.workingOn[object.ID] = object
+func (c *Controller) discoverNeighbor(workerID int, queue *workQueue) {
+	defer c.workers.Done()
+	for {
+		job, ok := queue.get()
+		if !ok {
+			return
 		}
-		c.lock.Unlock()
-		if !busy {
-			log.Infof("%d: Working on neighbor %s", workerID, object.ID)
-			c.linkReconciler.RegisterAgent(object)
-			log.Infof("%d: Finished work on neighbor %s", workerID, object.ID)
-
-			// We're done working on this object
-			c.lock.Lock()
-			delete(c.workingOn, object.ID)
-			c.lock.Unlock()
+		object := job.object
+		if job.removal {
+			log.Infof("%d: Releasing neighbor %s", workerID, object.ID)
+			c.linkReconciler.ReleaseDevice(object.ID)
+			queue.done(object.ID, false)
+			queue.forget(object.ID)
+			continue
 		}
+
+		// Only the owner of the neighbor registers its agent ID; other instances resolve it via onos-topo
+		if !c.owns(object.ID) {
+			c.linkReconciler.linkDiscovery.ReleaseDevice(object.ID)
+			queue.done(object.ID, false)
+			queue.forget(object.ID)
+			continue
+		}
+
+		log.Infof("%d: Working on neighbor %s", workerID, object.ID)
+		c.linkReconciler.RegisterAgent(object)
+		log.Infof("%d: Finished work on neighbor %s", workerID, object.ID)
+
+		// We're done working on this object
+		queue.done(object.ID, false)
 	}
 }
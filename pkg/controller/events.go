@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"sync"
+	"time"
+)
+
+// EventType identifies the type of discovery event
+type EventType string
+
+const (
+	// PortCreated indicates that a port entity was created for a newly discovered port
+	PortCreated EventType = "PORT_CREATED"
+	// PortUpdated indicates that the state of a port entity has changed
+	PortUpdated EventType = "PORT_UPDATED"
+	// PortDeleted indicates that a port entity was deleted because the port is no longer reported
+	PortDeleted EventType = "PORT_DELETED"
+	// LinkUp indicates that a link was discovered or came back up
+	LinkUp EventType = "LINK_UP"
+	// LinkDown indicates that a link went down
+	LinkDown EventType = "LINK_DOWN"
+	// LinkRemoved indicates that a stale link entity was removed
+	LinkRemoved EventType = "LINK_REMOVED"
+	// LinkAsymmetric indicates that a link has been up without the link in the opposite direction for too long
+	LinkAsymmetric EventType = "LINK_ASYMMETRIC"
+	// HostAdded indicates that a host entity was created for a newly discovered host
+	HostAdded EventType = "HOST_ADDED"
+	// HostMoved indicates that a host was discovered at a different location
+	HostMoved EventType = "HOST_MOVED"
+	// HostRemoved indicates that a host entity was removed
+	HostRemoved EventType = "HOST_REMOVED"
+	// DeviceUnreachable indicates that the device could not be reached via gNMI
+	DeviceUnreachable EventType = "DEVICE_UNREACHABLE"
+	// AgentRegistered indicates that a link agent ID was bound to a device
+	AgentRegistered EventType = "AGENT_REGISTERED"
+)
+
+// Event describes a change made, or a condition detected, by the discovery reconcilers
+type Event struct {
+	Type      EventType
+	Timestamp time.Time
+	// ID of the topology object the event pertains to, e.g. the port, link or host
+	ObjectID topo.ID
+	// ID of the device the object belongs to and, for links, of the device at the other end
+	DeviceID     topo.ID
+	PeerDeviceID topo.ID
+	// Agent ID, for events originating from link and host agents
+	AgentID string
+	Message string
+}
+
+// EventFilter selects the events delivered to a watcher; empty fields match all events
+type EventFilter struct {
+	DeviceIDs []topo.ID
+	Types     []EventType
+}
+
+// Returns true if the event passes the filter
+func (f *EventFilter) matches(event *Event) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.Types) > 0 {
+		found := false
+		for _, t := range f.Types {
+			found = found || t == event.Type
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(f.DeviceIDs) > 0 {
+		found := false
+		for _, id := range f.DeviceIDs {
+			found = found || id == event.DeviceID || (len(event.PeerDeviceID) > 0 && id == event.PeerDeviceID)
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+const eventBufferSize = 256
+
+// EventBroker distributes discovery events to all interested watchers
+type EventBroker struct {
+	lock     sync.RWMutex
+	watchers map[int]*eventWatcher
+	nextID   int
+}
+
+type eventWatcher struct {
+	ch     chan *Event
+	filter *EventFilter
+}
+
+// NewEventBroker creates a new discovery event broker
+func NewEventBroker() *EventBroker {
+	return &EventBroker{watchers: make(map[int]*eventWatcher)}
+}
+
+// Watch registers a watcher for events passing the given filter; the returned channel is closed when the
+// context is done
+func (b *EventBroker) Watch(ctx context.Context, filter *EventFilter) <-chan *Event {
+	w := &eventWatcher{ch: make(chan *Event, eventBufferSize), filter: filter}
+	b.lock.Lock()
+	id := b.nextID
+	b.nextID++
+	b.watchers[id] = w
+	b.lock.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.lock.Lock()
+		delete(b.watchers, id)
+		close(w.ch)
+		b.lock.Unlock()
+	}()
+	return w.ch
+}
+
+// Publish delivers the event to all matching watchers; events are dropped for watchers that do not keep up.
+// Publishing to a nil broker is a no-op.
+func (b *EventBroker) Publish(event *Event) {
+	if b == nil {
+		return
+	}
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	b.lock.RLock()
+	defer b.lock.RUnlock()
+	for id, w := range b.watchers {
+		if !w.filter.matches(event) {
+			continue
+		}
+		select {
+		case w.ch <- event:
+		default:
+			log.Warnf("Dropping %s event for %s; watcher %d is not keeping up", event.Type, event.ObjectID, id)
+		}
+	}
+}
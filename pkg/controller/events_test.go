@@ -0,0 +1,58 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEventFilter(t *testing.T) {
+	event := &Event{Type: LinkUp, DeviceID: "a", PeerDeviceID: "b"}
+	assert.True(t, (*EventFilter)(nil).matches(event))
+	assert.True(t, (&EventFilter{}).matches(event))
+	assert.True(t, (&EventFilter{DeviceIDs: []topo.ID{"b"}}).matches(event))
+	assert.False(t, (&EventFilter{DeviceIDs: []topo.ID{"c"}}).matches(event))
+	assert.True(t, (&EventFilter{Types: []EventType{LinkDown, LinkUp}}).matches(event))
+	assert.False(t, (&EventFilter{Types: []EventType{PortCreated}, DeviceIDs: []topo.ID{"a"}}).matches(event))
+}
+
+func TestLinkEvents(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	ctx, cancel := context.WithCancel(context.TODO())
+	all := c.WatchEvents(ctx, nil)
+	links := c.WatchEvents(ctx, &EventFilter{Types: []EventType{LinkUp, LinkDown}, DeviceIDs: []topo.ID{"tb"}})
+
+	r := c.linkReconciler
+	r.registerReport(topo.NewEntity("ta", topo.SwitchKind), &southbound.LinkReport{AgentID: "a"})
+	r.registerReport(topo.NewEntity("tb", topo.SwitchKind), &southbound.LinkReport{AgentID: "b"})
+	r.registerReport(topo.NewEntity("tb", topo.SwitchKind), &southbound.LinkReport{AgentID: "b"})
+	link := &southbound.Link{IngressDevice: "a", IngressPort: 1, EgressDevice: "b", EgressPort: 1, CreateTime: 1}
+	r.reconcileLink(link, statusUp)
+	r.reconcileLink(&southbound.Link{IngressDevice: "a", IngressPort: 1, EgressDevice: "b", EgressPort: 1, CreateTime: 2}, statusUp)
+	r.reconcileLink(&southbound.Link{IngressDevice: "a", IngressPort: 1, EgressDevice: "b", EgressPort: 1, CreateTime: 3}, statusDown)
+
+	event := <-all
+	assert.Equal(t, AgentRegistered, event.Type)
+	assert.Equal(t, "a", event.AgentID)
+	assert.Equal(t, AgentRegistered, (<-all).Type)
+	assert.Equal(t, LinkUp, (<-all).Type)
+	assert.Equal(t, LinkDown, (<-all).Type)
+
+	event = <-links
+	assert.Equal(t, LinkUp, event.Type)
+	assert.Equal(t, topo.ID("tb/1-ta/1"), event.ObjectID)
+	assert.Equal(t, topo.ID("ta"), event.DeviceID)
+	assert.Equal(t, topo.ID("tb"), event.PeerDeviceID)
+	assert.Equal(t, LinkDown, (<-links).Type)
+
+	cancel()
+	_, ok := <-all
+	assert.False(t, ok)
+}
@@ -0,0 +1,223 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/provisioner"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+	"io"
+)
+
+// ExportTopology produces a declarative snapshot of all pods and racks and of the realm's switches and server IPUs,
+// along with the ports, links and hosts discovered for them
+func (c *Controller) ExportTopology(ctx context.Context) (*inventory.Topology, error) {
+	if c.getState() != Monitoring {
+		return nil, errors.NewUnavailable(controllerNotReady)
+	}
+	objects, err := c.realmObjects(ctx)
+	if err != nil {
+		return nil, err
+	}
+	devices := make([]*topo.Object, 0, len(objects))
+	for _, object := range objects {
+		if entity := object.GetEntity(); entity != nil && (entity.KindID == topo.SwitchKind || entity.KindID == topo.IPUKind) {
+			devices = append(devices, object)
+		}
+	}
+
+	// Each kind of entity and relation is read with a single query and the topology is assembled from those
+	e := &exporter{topology: &inventory.Topology{}, entities: make(map[topo.ID]map[topo.ID]*topo.Object),
+		targets: make(map[topo.ID]map[topo.ID][]topo.ID), sources: make(map[topo.ID]map[topo.ID]topo.ID),
+		racks: make(map[topo.ID]*inventory.Rack)}
+	for _, kind := range []topo.ID{topo.PodKind, topo.RackKind, topo.PortKind, topo.LinkKind, topo.HostKind,
+		topo.CONTAINS, topo.HasKind, topo.OriginatesKind, topo.TerminatesKind, topo.ConnectionKind} {
+		if err = e.load(ctx, c.topoClient, kind); err != nil {
+			return nil, err
+		}
+	}
+
+	e.exportPods()
+	for _, device := range devices {
+		if err = e.exportDevice(device); err != nil {
+			return nil, err
+		}
+	}
+	e.topology.Sort()
+	log.Infof("Exported topology with %d devices, %d ports, %d links and %d hosts",
+		len(devices), len(e.topology.Ports), len(e.topology.Links), len(e.topology.Hosts))
+	return e.topology, nil
+}
+
+// Accumulates the exported topology from the entities and relations read from onos-topo
+type exporter struct {
+	topology *inventory.Topology
+	entities map[topo.ID]map[topo.ID]*topo.Object // entities by kind and ID
+	targets  map[topo.ID]map[topo.ID][]topo.ID    // relation targets by relation kind and source entity
+	sources  map[topo.ID]map[topo.ID]topo.ID      // relation sources by relation kind and target entity
+	racks    map[topo.ID]*inventory.Rack
+}
+
+// Reads all entities or relations of the given kind
+func (e *exporter) load(ctx context.Context, topoClient topo.TopoClient, kind topo.ID) error {
+	filters := &topo.Filters{
+		KindFilter: &topo.Filter{Filter: &topo.Filter_Equal_{Equal_: &topo.EqualFilter{Value: string(kind)}}},
+	}
+	stream, err := topoClient.Query(ctx, &topo.QueryRequest{Filters: filters})
+	if err != nil {
+		return errors.FromGRPC(err)
+	}
+	e.entities[kind] = make(map[topo.ID]*topo.Object)
+	e.targets[kind] = make(map[topo.ID][]topo.ID)
+	e.sources[kind] = make(map[topo.ID]topo.ID)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return errors.FromGRPC(err)
+		}
+		if relation := resp.Object.GetRelation(); relation != nil {
+			e.targets[kind][relation.SrcEntityID] = append(e.targets[kind][relation.SrcEntityID], relation.TgtEntityID)
+			e.sources[kind][relation.TgtEntityID] = relation.SrcEntityID
+		} else if resp.Object.GetEntity() != nil {
+			e.entities[kind][resp.Object.ID] = resp.Object
+		}
+	}
+}
+
+// Exports all pods along with their racks, including those without any devices
+func (e *exporter) exportPods() {
+	pods := make(map[topo.ID]*inventory.Pod)
+	for id := range e.entities[topo.PodKind] {
+		pod := &inventory.Pod{ID: string(id)}
+		pods[id] = pod
+		e.topology.Pods = append(e.topology.Pods, pod)
+	}
+	for id := range e.entities[topo.RackKind] {
+		pod, ok := pods[e.sources[topo.CONTAINS][id]]
+		if !ok {
+			log.Warnf("Rack %s is not contained in any pod; not exporting it", id)
+			continue
+		}
+		rack := &inventory.Rack{ID: string(id)}
+		e.racks[id] = rack
+		pod.Racks = append(pod.Racks, rack)
+	}
+}
+
+// Exports the device along with its rack placement, ports, and the links and hosts attached to them
+func (e *exporter) exportDevice(object *topo.Object) error {
+	device := &inventory.Device{ID: string(object.ID), ManagementInfo: managementInfo(object)}
+
+	// IPUs are exported as their servers, which are contained in the rack
+	placedID := object.ID
+	if object.GetEntity().KindID == topo.IPUKind {
+		serverID, ok := e.sources[topo.CONTAINS][object.ID]
+		if !ok {
+			return errors.NewInvalid("IPU %s is not contained in a server", object.ID)
+		}
+		placedID = serverID
+		device.ID = string(serverID)
+	}
+
+	rack, ok := e.racks[e.sources[topo.CONTAINS][placedID]]
+	if !ok {
+		log.Warnf("Device %s is not contained in any rack; exporting only its ports, links and hosts", object.ID)
+	} else if placedID == object.ID {
+		rack.Switches = append(rack.Switches, device)
+	} else {
+		rack.Servers = append(rack.Servers, device)
+	}
+
+	for _, port := range e.related(object.ID, topo.HasKind, topo.PortKind) {
+		e.exportPort(object, port)
+	}
+	return nil
+}
+
+// Exports the port along with the links originating from it and the hosts connected to it
+func (e *exporter) exportPort(device *topo.Object, object *topo.Object) {
+	port := &inventory.Port{ID: string(object.ID), Device: string(device.ID)}
+	portAspect := &topo.Port{}
+	if err := object.GetAspect(portAspect); err == nil {
+		port.Number = portAspect.Number
+		port.DisplayName = portAspect.DisplayName
+		port.Speed = portAspect.Speed
+		port.Enabled = portAspect.Enabled
+		port.Status = portAspect.Status
+	}
+	e.topology.Ports = append(e.topology.Ports, port)
+
+	for _, object := range e.related(object.ID, topo.OriginatesKind, topo.LinkKind) {
+		link := &inventory.Link{ID: string(object.ID), Source: port.ID,
+			Target: string(e.sources[topo.TerminatesKind][object.ID])}
+		linkAspect := &topo.Link{}
+		if err := object.GetAspect(linkAspect); err == nil {
+			link.Status = linkAspect.Status
+		}
+		e.topology.Links = append(e.topology.Links, link)
+	}
+
+	for _, object := range e.related(object.ID, topo.ConnectionKind, topo.HostKind) {
+		host := &inventory.Host{ID: string(object.ID), Port: port.ID}
+		hostAspect := &topo.NetworkInterface{}
+		if err := object.GetAspect(hostAspect); err == nil {
+			host.MAC = hostAspect.MAC
+			if hostAspect.IP != nil {
+				host.IP = hostAspect.IP.IP
+			}
+		}
+		for _, address := range getHostAddresses(object).Addresses {
+			host.IPs = append(host.IPs, address.IP)
+		}
+		e.topology.Hosts = append(e.topology.Hosts, host)
+	}
+}
+
+// Returns the target entities of the given kind of the given entity's relations of the specified kind
+func (e *exporter) related(id topo.ID, relationKind topo.ID, kind topo.ID) []*topo.Object {
+	targets := make([]*topo.Object, 0)
+	for _, targetID := range e.targets[relationKind][id] {
+		if target, ok := e.entities[kind][targetID]; ok {
+			targets = append(targets, target)
+		}
+	}
+	return targets
+}
+
+// Reconstructs the management info of a switch or IPU entity from its aspects and labels
+func managementInfo(object *topo.Object) *inventory.ManagementInfo {
+	info := &inventory.ManagementInfo{Realm: object.Labels["realm"], Role: object.Labels["role"]}
+	stratumAgents := &topo.StratumAgents{}
+	if err := object.GetAspect(stratumAgents); err == nil {
+		info.P4RTEndpoint = endpointString(stratumAgents.P4RTEndpoint)
+		info.GNMIEndpoint = endpointString(stratumAgents.GNMIEndpoint)
+		info.DeviceID = stratumAgents.DeviceID
+	}
+	deviceConfig := &provisioner.DeviceConfig{}
+	if err := object.GetAspect(deviceConfig); err == nil {
+		info.ChassisConfigID = string(deviceConfig.ChassisConfigID)
+		info.PipelineConfigID = string(deviceConfig.PipelineConfigID)
+	}
+	localAgents := &topo.LocalAgents{}
+	if err := object.GetAspect(localAgents); err == nil {
+		info.LinkAgentEndpoint = endpointString(localAgents.LinkAgentEndpoint)
+		info.HostAgentEndpoint = endpointString(localAgents.HostAgentEndpoint)
+		info.NATAgentEndpoint = endpointString(localAgents.NATAgentEndpoint)
+	}
+	return info
+}
+
+// Renders the endpoint in host:port form; the inverse of endpoint()
+func endpointString(ep *topo.Endpoint) string {
+	if ep == nil {
+		return ""
+	}
+	return fmt.Sprintf("%s:%d", ep.Address, ep.Port)
+}
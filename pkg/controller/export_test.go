@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	api "github.com/onosproject/onos-api/go/onos/discovery"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestExportTopology(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	ctx := context.TODO()
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+	assert.NoError(t, c.AddSwitch(ctx, &api.AddSwitchRequest{ID: "leaf1", PodID: "pod1", RackID: "rack1",
+		ManagementInfo: &api.ManagementInfo{GNMIEndpoint: "fabric-sim:20000", LinkAgentEndpoint: "agent-0:30000", Role: "leaf"}}))
+	assert.NoError(t, c.AddServerIPU(ctx, addServerIPURequest("edge")))
+
+	leaf1, _ := c.getObject(ctx, "leaf1")
+	ipu, _ := c.getObject(ctx, "server1-IPU")
+	c.portReconciler.createPort(leaf1, "leaf1/1", &topo.Port{Number: 1, Status: statusUp, Enabled: true}, nil)
+	c.portReconciler.createPort(ipu, "server1-IPU/1", &topo.Port{Number: 1, Status: statusUp}, nil)
+	c.linkReconciler.createLink("leaf1/1-server1-IPU/1", "leaf1/1", "server1-IPU/1", &southbound.Link{}, nil)
+	c.linkReconciler.createLink("server1-IPU/1-leaf1/1", "server1-IPU/1", "leaf1/1", &southbound.Link{}, nil)
+	c.hostReconciler.createHost("host1", &southbound.Host{MAC: "m1", IPs: testIPs("10.0.0.1", "fd00::1"), Port: 1},
+		leaf1, "agent-0")
+
+	// Pods and racks without devices are exported as well; the objects are read by queries alone
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod2"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack2", PodID: "pod1"}))
+	gets := f.gets
+	topology, err := c.ExportTopology(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, gets, f.gets)
+	assert.Len(t, topology.Pods, 2)
+	assert.Equal(t, "pod2", topology.Pods[1].ID)
+	assert.Empty(t, topology.Pods[1].Racks)
+	assert.Len(t, topology.Pods[0].Racks, 2)
+	assert.Equal(t, &inventory.Rack{ID: "rack2"}, topology.Pods[0].Racks[1])
+	rack := topology.Pods[0].Racks[0]
+	assert.Equal(t, "rack1", rack.ID)
+	assert.Equal(t, "leaf1", rack.Switches[0].ID)
+	assert.Equal(t, "fabric-sim:20000", rack.Switches[0].ManagementInfo.GNMIEndpoint)
+	assert.Equal(t, "agent-0:30000", rack.Switches[0].ManagementInfo.LinkAgentEndpoint)
+	assert.Equal(t, "leaf", rack.Switches[0].ManagementInfo.Role)
+	assert.Equal(t, "server1", rack.Servers[0].ID)
+	assert.Equal(t, "server1-ipu:9559", rack.Servers[0].ManagementInfo.P4RTEndpoint)
+	assert.Equal(t, "edge", rack.Servers[0].ManagementInfo.Realm)
+
+	assert.Len(t, topology.Ports, 2)
+	assert.Len(t, topology.Links, 2)
+	assert.Equal(t, &inventory.Link{ID: "leaf1/1-server1-IPU/1", Source: "leaf1/1", Target: "server1-IPU/1", Status: statusUp},
+		topology.Links[0])
+	assert.Equal(t, []*inventory.Host{{ID: "host1", Port: "leaf1/1", MAC: "m1", IP: "10.0.0.1",
+		IPs: []string{"10.0.0.1", "fd00::1"}}}, topology.Hosts)
+
+	// The exported document must be stable and importable as inventory
+	first, err := topology.Encode(inventory.YAML)
+	assert.NoError(t, err)
+	topology, err = c.ExportTopology(ctx)
+	assert.NoError(t, err)
+	second, err := topology.Encode(inventory.YAML)
+	assert.NoError(t, err)
+	assert.Equal(t, string(first), string(second))
+
+	inv, err := inventory.Parse(first)
+	assert.NoError(t, err)
+	assert.NoError(t, inv.Validate())
+	assert.Equal(t, "server1", inv.Pods[0].Racks[0].Servers[0].ID)
+}
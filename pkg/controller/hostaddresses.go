@@ -0,0 +1,115 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"encoding/json"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"strings"
+	"time"
+)
+
+const (
+	// Aspect of host entities carrying all addresses of the host along with its VLAN and when it was last seen
+	hostAddressesAspect = "onos.discovery.HostAddresses"
+
+	// Changes of the last-seen time alone are written back only once they exceed this resolution
+	lastSeenResolution = time.Minute
+)
+
+// HostAddress is an IP address of a host along with its address family
+type HostAddress struct {
+	IP     string `json:"ip"`
+	Family string `json:"family"`
+}
+
+// HostAddresses describes all addresses of a host, its VLAN and when it was last seen by its host agent
+type HostAddresses struct {
+	Addresses []HostAddress `json:"addresses,omitempty"`
+	VLAN      uint32        `json:"vlan,omitempty"`
+	LastSeen  time.Time     `json:"last_seen"`
+}
+
+// Returns the addresses of the southbound host; hosts whose agent does not report when it last saw them are
+// considered seen now
+func newHostAddresses(host *southbound.Host) *HostAddresses {
+	addresses := &HostAddresses{VLAN: host.VLAN, LastSeen: host.LastSeen}
+	if addresses.LastSeen.IsZero() {
+		addresses.LastSeen = time.Now()
+	}
+	for _, ip := range host.IPs {
+		family := "ipv4"
+		if ip.Type == topo.IPAddress_IPV6 {
+			family = "ipv6"
+		}
+		addresses.Addresses = append(addresses.Addresses, HostAddress{IP: ip.IP, Family: family})
+	}
+	return addresses
+}
+
+// Returns true if the addresses or VLAN differ, or if the last-seen time advanced by more than its resolution
+func (a *HostAddresses) differsFrom(other *HostAddresses) bool {
+	if a.VLAN != other.VLAN || len(a.Addresses) != len(other.Addresses) {
+		return true
+	}
+	for i := range a.Addresses {
+		if a.Addresses[i] != other.Addresses[i] {
+			return true
+		}
+	}
+	return a.LastSeen.Sub(other.LastSeen) >= lastSeenResolution
+}
+
+func (a *HostAddresses) String() string {
+	ips := make([]string, 0, len(a.Addresses))
+	for _, address := range a.Addresses {
+		ips = append(ips, address.IP)
+	}
+	return strings.Join(ips, ",")
+}
+
+// Returns the addresses of the host entity; empty if it has none
+func getHostAddresses(object *topo.Object) *HostAddresses {
+	addresses := &HostAddresses{}
+	if value := object.GetAspectBytes(hostAddressesAspect); value != nil {
+		if err := json.Unmarshal(value, addresses); err != nil {
+			log.Warnf("Unable to parse addresses of host %s: %+v", object.ID, err)
+			return &HostAddresses{}
+		}
+	}
+	return addresses
+}
+
+// Syncs the network interface and addresses aspects of the host entity with the southbound host; returns true if
+// either changed
+func syncHostAddresses(object *topo.Object, host *southbound.Host) (bool, error) {
+	addresses := newHostAddresses(host)
+	nic := &topo.NetworkInterface{MAC: host.MAC, IP: host.PrimaryIP()}
+	current := &topo.NetworkInterface{}
+	nicChanged := object.GetAspect(current) != nil || current.MAC != nic.MAC || !sameIP(current.IP, nic.IP)
+	if nicChanged {
+		if err := object.SetAspect(nic); err != nil {
+			return false, err
+		}
+	}
+	if !addresses.differsFrom(getHostAddresses(object)) {
+		return nicChanged, nil
+	}
+	value, err := json.Marshal(addresses)
+	if err != nil {
+		return false, err
+	}
+	object.SetAspectBytes(hostAddressesAspect, value)
+	return true, nil
+}
+
+// Returns true if both IP addresses are the same or both are missing
+func sameIP(a *topo.IPAddress, b *topo.IPAddress) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.IP == b.IP && a.Type == b.Type
+}
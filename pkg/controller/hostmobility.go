@@ -0,0 +1,112 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"time"
+)
+
+const (
+	// Aspect of host entities tracking where the host is attached and where it was attached before
+	hostLocationsAspect = "onos.discovery.HostLocations"
+
+	// Maximum number of previous locations retained for each host
+	maxLocationHistory = 10
+)
+
+// HostLocation describes the port at which a host was attached
+type HostLocation struct {
+	DeviceID topo.ID   `json:"device_id"`
+	Port     uint32    `json:"port"`
+	Since    time.Time `json:"since"`
+}
+
+// HostLocations describes the current location of a host along with its most recent previous locations, the
+// latest first
+type HostLocations struct {
+	Current *HostLocation   `json:"current,omitempty"`
+	History []*HostLocation `json:"history,omitempty"`
+}
+
+// Returns the location of the host at the given device
+func newHostLocation(deviceID topo.ID, host *southbound.Host) *HostLocation {
+	return &HostLocation{DeviceID: deviceID, Port: host.Port, Since: time.Now()}
+}
+
+// Returns true if both locations refer to the same port
+func (l *HostLocation) sameAs(other *HostLocation) bool {
+	return l.DeviceID == other.DeviceID && l.Port == other.Port
+}
+
+func (l *HostLocation) String() string {
+	return fmt.Sprintf("%s/%d", l.DeviceID, l.Port)
+}
+
+// Makes the given location current, pushing the current one into the bounded history
+func (ls *HostLocations) moveTo(location *HostLocation) {
+	if ls.Current != nil {
+		ls.History = append([]*HostLocation{ls.Current}, ls.History...)
+		if len(ls.History) > maxLocationHistory {
+			ls.History = ls.History[:maxLocationHistory]
+		}
+	}
+	ls.Current = location
+}
+
+// Returns the locations of the host entity; empty if it has none
+func getHostLocations(object *topo.Object) *HostLocations {
+	locations := &HostLocations{}
+	if value := object.GetAspectBytes(hostLocationsAspect); value != nil {
+		if err := json.Unmarshal(value, locations); err != nil {
+			log.Warnf("Unable to parse locations of host %s: %+v", object.ID, err)
+			return &HostLocations{}
+		}
+	}
+	return locations
+}
+
+// Sets the locations of the host entity
+func setHostLocations(object *topo.Object, locations *HostLocations) error {
+	value, err := json.Marshal(locations)
+	if err != nil {
+		return err
+	}
+	object.SetAspectBytes(hostLocationsAspect, value)
+	return nil
+}
+
+// Moves the host entity to its new location: updates its locations, takes on the labels of the new device and
+// replaces its connection relation with one to the new port
+func (r *HostReconciler) moveHost(object *topo.Object, locations *HostLocations, location *HostLocation, device *topo.Object, agentID string) {
+	previous := locations.Current
+	locations.moveTo(location)
+	if err := setHostLocations(object, locations); err != nil {
+		log.Warnf("Unable to set locations of host %s: %+v", object.ID, err)
+		return
+	}
+	object.Labels = hostLabels(device)
+	if _, err := r.topoClient.Update(r.ctx, &topo.UpdateRequest{Object: object}); err != nil {
+		log.Warnf("Unable to update host %s: %+v", object.ID, err)
+		return
+	}
+	countOperation(hostReconcilerName, updateOperation)
+
+	if !r.removeHostRelations(object) || !r.createHostRelation(object.ID, device.ID, location.Port) {
+		return
+	}
+
+	if previous == nil {
+		log.Infof("Located host %s at %s", object.ID, location)
+		return
+	}
+	message := fmt.Sprintf("moved from %s to %s", previous, location)
+	log.Infof("Host %s %s", object.ID, message)
+	r.events.Publish(&Event{Type: HostMoved, ObjectID: object.ID, DeviceID: device.ID, PeerDeviceID: previous.DeviceID,
+		AgentID: agentID, Message: message})
+}
@@ -8,17 +8,36 @@ import (
 	"context"
 	"fmt"
 	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/topo-discovery/pkg/southbound"
-	"strconv"
+	"io"
+	"sync"
 	"time"
 )
 
+// Label carrying the ID of the device to which the host is currently attached; scopes pruning and aging
+const hostDeviceLabel = "device"
+
 // HostReconciler provides state and context required for host discovery and reconciliation
 type HostReconciler struct {
 	southbound.HostListener
 	hostDiscovery southbound.HostDiscovery
 	topoClient    topo.TopoClient
 	ctx           context.Context
+	events        *EventBroker
+	lock          sync.RWMutex
+
+	// Realm of the hosts; hosts are identified by their MAC address and VLAN within the realm
+	realm string
+
+	// Map of agent-id to topo object required to resolve host reports to a device
+	agentDevices map[string]*topo.Object
+
+	// Link reconciler whose agent bindings resolve agent IDs not yet bound by host discovery
+	linkReconciler *LinkReconciler
+
+	// Map of host ID to the time the host was last reported by a host agent of one of our devices
+	lastSeen map[topo.ID]time.Time
 }
 
 // NewHostReconciler creates a new host reconciler context
@@ -27,22 +46,52 @@ func NewHostReconciler(ctx context.Context, topoClient topo.TopoClient) *HostRec
 		topoClient:    topoClient,
 		ctx:           ctx,
 		hostDiscovery: southbound.NewGNMIHostDiscovery(),
+		agentDevices:  make(map[string]*topo.Object),
+		lastSeen:      make(map[topo.ID]time.Time),
 	}
 }
 
-// DiscoverHosts discovers hosts and reconciles their topology entity counterparts
-func (r *HostReconciler) DiscoverHosts(object *topo.Object) {
+// DiscoverHosts discovers hosts and reconciles their topology entity counterparts; returns an error if the hosts
+// could not be discovered
+func (r *HostReconciler) DiscoverHosts(object *topo.Object) error {
 	// Connect to the host agent gNMI server and get its agent ID and a map of hosts
+	reportTime := time.Now()
 	hostReport, err := r.hostDiscovery.GetHosts(object, r)
 	if err != nil {
 		log.Warnf("Unable to get hosts from device host agent %s: %+v", object.ID, err)
-		return
+		return err
 	}
 
+	// (Re)create the agent ID to device entity binding
+	r.lock.Lock()
+	for agentID, device := range r.agentDevices {
+		if device.ID == object.ID && agentID != hostReport.AgentID {
+			delete(r.agentDevices, agentID)
+		}
+	}
+	r.agentDevices[hostReport.AgentID] = object
+	r.lock.Unlock()
+
 	// process all hosts from the report
 	for _, host := range hostReport.Hosts {
 		r.reconcileHost(host, hostReport.AgentID)
 	}
+
+	// The report is complete, so any hosts still attached to the device but missing from it are gone
+	return r.pruneHosts(object.ID, hostReport, reportTime)
+}
+
+// ReleaseDevice releases the host discovery context of the specified device
+func (r *HostReconciler) ReleaseDevice(id topo.ID) {
+	r.hostDiscovery.ReleaseDevice(id)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for agentID, device := range r.agentDevices {
+		if device.ID == id {
+			delete(r.agentDevices, agentID)
+		}
+	}
 }
 
 // HostAdded handles host addition event
@@ -50,53 +99,276 @@ func (r *HostReconciler) HostAdded(host *southbound.Host, agentID string) {
 	r.reconcileHost(host, agentID)
 }
 
-// HostDeleted handles host deletion event
+// HostDeleted handles host deletion event by removing the host entity along with its relations, unless the host
+// has moved to another device in the meantime
 func (r *HostReconciler) HostDeleted(host *southbound.Host, agentID string) {
-	host.CreateTime = uint64(time.Now().UnixNano())
-	r.reconcileHost(host, agentID)
+	device := r.resolveDevice(agentID)
+	if device == nil {
+		return
+	}
+	hostID := r.composeHostID(host)
+	resp, err := r.topoClient.Get(r.ctx, &topo.GetRequest{ID: hostID})
+	if err != nil {
+		if err = errors.FromGRPC(err); !errors.IsNotFound(err) {
+			log.Warnf("Unable to get host %s: %+v", hostID, err)
+		}
+		return
+	}
+	if resp.Object.Labels[hostDeviceLabel] != string(device.ID) {
+		log.Infof("Host %s deleted by agent %s is now attached to %s; retaining it", hostID, agentID, resp.Object.Labels[hostDeviceLabel])
+		return
+	}
+	r.removeHost(resp.Object, device.ID, "host deleted by agent")
+}
+
+// ExpireHosts removes the hosts attached to our devices which have not been reported by their host agents for
+// longer than the given timeout; hosts not yet seen by this instance are aged from the time they are first
+// encountered here. Only the devices passing the given ownership check are considered; all of them if nil.
+func (r *HostReconciler) ExpireHosts(timeout time.Duration, owns func(id topo.ID) bool) {
+	r.lock.RLock()
+	devices := make([]topo.ID, 0, len(r.agentDevices))
+	for _, device := range r.agentDevices {
+		if owns == nil || owns(device.ID) {
+			devices = append(devices, device.ID)
+		}
+	}
+	r.lock.RUnlock()
+
+	now := time.Now()
+	for _, deviceID := range devices {
+		hosts, err := r.deviceHosts(deviceID)
+		if err != nil {
+			log.Warnf("Unable to query hosts of %s: %+v", deviceID, err)
+			continue
+		}
+		for _, host := range hosts {
+			r.lock.Lock()
+			lastSeen, ok := r.lastSeen[host.ID]
+			if !ok {
+				lastSeen = now
+				r.lastSeen[host.ID] = now
+			}
+			r.lock.Unlock()
+			if now.Sub(lastSeen) > timeout {
+				r.removeHost(host, deviceID, fmt.Sprintf("not seen for %s", now.Sub(lastSeen).Round(time.Second)))
+			}
+		}
+	}
+}
+
+// Removes the hosts attached to the device which are missing from its report, unless they were seen after the
+// report was requested
+func (r *HostReconciler) pruneHosts(deviceID topo.ID, report *southbound.HostReport, reportTime time.Time) error {
+	hosts, err := r.deviceHosts(deviceID)
+	if err != nil {
+		log.Warnf("Unable to query hosts of %s: %+v", deviceID, err)
+		return err
+	}
+	reported := make(map[topo.ID]bool, len(report.Hosts))
+	for _, host := range report.Hosts {
+		reported[r.composeHostID(host)] = true
+	}
+	for _, host := range hosts {
+		r.lock.RLock()
+		lastSeen := r.lastSeen[host.ID]
+		r.lock.RUnlock()
+		if !reported[host.ID] && lastSeen.Before(reportTime) {
+			r.removeHost(host, deviceID, "no longer reported by agent")
+		}
+	}
+	return nil
+}
+
+// Returns the host entities currently attached to the specified device
+func (r *HostReconciler) deviceHosts(deviceID topo.ID) ([]*topo.Object, error) {
+	filters := &topo.Filters{
+		KindFilter: &topo.Filter{Filter: &topo.Filter_Equal_{Equal_: &topo.EqualFilter{Value: topo.HostKind}}},
+		LabelFilters: []*topo.Filter{{Key: hostDeviceLabel,
+			Filter: &topo.Filter_Equal_{Equal_: &topo.EqualFilter{Value: string(deviceID)}}}},
+	}
+	stream, err := r.topoClient.Query(r.ctx, &topo.QueryRequest{Filters: filters})
+	if err != nil {
+		return nil, errors.FromGRPC(err)
+	}
+	hosts := make([]*topo.Object, 0)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return hosts, nil
+		} else if err != nil {
+			return nil, errors.FromGRPC(err)
+		}
+		hosts = append(hosts, resp.Object)
+	}
+}
+
+// Removes the host entity along with its connection relations
+func (r *HostReconciler) removeHost(host *topo.Object, deviceID topo.ID, reason string) {
+	if !r.removeHostRelations(host) {
+		return
+	}
+	if _, err := r.topoClient.Delete(r.ctx, &topo.DeleteRequest{ID: host.ID}); err != nil {
+		if err = errors.FromGRPC(err); !errors.IsNotFound(err) {
+			log.Warnf("Unable to remove host %s: %+v", host.ID, err)
+		}
+		return
+	}
+
+	r.lock.Lock()
+	delete(r.lastSeen, host.ID)
+	r.lock.Unlock()
+	log.Infof("Removed host %s; %s", host.ID, reason)
+	countOperation(hostReconcilerName, deleteOperation)
+	r.events.Publish(&Event{Type: HostRemoved, ObjectID: host.ID, DeviceID: deviceID, Message: reason})
+}
+
+// Removes all relations of the host entity; returns false if any of them could not be removed
+func (r *HostReconciler) removeHostRelations(host *topo.Object) bool {
+	entity := host.GetEntity()
+	if entity == nil {
+		return true
+	}
+	relationIDs := append(append([]topo.ID{}, entity.TgtRelationIDs...), entity.SrcRelationIDs...)
+	for _, relationID := range relationIDs {
+		if _, err := r.topoClient.Delete(r.ctx, &topo.DeleteRequest{ID: relationID}); err != nil {
+			if err = errors.FromGRPC(err); !errors.IsNotFound(err) {
+				log.Warnf("Unable to remove relation %s of host %s: %+v", relationID, host.ID, err)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Returns the device whose host agent has the given agent ID, falling back to the link agent bindings as both
+// agents of a device report the same ID; returns nil if it is not known
+func (r *HostReconciler) resolveDevice(agentID string) *topo.Object {
+	r.lock.RLock()
+	device := r.agentDevices[agentID]
+	r.lock.RUnlock()
+	if device == nil && r.linkReconciler != nil {
+		device = r.linkReconciler.Device(agentID)
+	}
+	return device
+}
+
+// Returns the labels of host entities attached to the device: the labels of the device, other than its link
+// agent ID, along with the device label
+func hostLabels(device *topo.Object) map[string]string {
+	labels := make(map[string]string, len(device.Labels)+1)
+	for k, v := range device.Labels {
+		if k != agentIDLabel {
+			labels[k] = v
+		}
+	}
+	labels[hostDeviceLabel] = string(device.ID)
+	return labels
+}
+
+// Composes the ID of the host entity from the realm and the VLAN and MAC address of the host, so that the host
+// retains its identity as it moves between ports and devices
+func (r *HostReconciler) composeHostID(host *southbound.Host) topo.ID {
+	realm := r.realm
+	if len(realm) == 0 {
+		realm = "default"
+	}
+	return topo.ID(fmt.Sprintf("%s/%d/%s", realm, host.VLAN, host.MAC))
 }
 
 // Reconciles the specified southbound host against its topology entity counterpart
 func (r *HostReconciler) reconcileHost(host *southbound.Host, agentID string) {
-	// first, compose DeviceMAC/Port ID
-	hostID := topo.ID(fmt.Sprintf("%s/%d/%s", agentID, host.Port, host.MAC))
-
-	//composing IP address
-	ipAddr := topo.IPAddress{
-		IP:   host.IP,
-		Type: topo.IPAddress_IPV4,
+	device := r.resolveDevice(agentID)
+	if device == nil {
+		log.Warnf("Unable to resolve host agent %s to a device", agentID)
+		return
 	}
 
+	hostID := r.composeHostID(host)
+	r.lock.Lock()
+	r.lastSeen[hostID] = time.Now()
+	r.lock.Unlock()
+
 	// Try to get the host
-	_, err := r.topoClient.Get(r.ctx, &topo.GetRequest{ID: hostID})
+	resp, err := r.topoClient.Get(r.ctx, &topo.GetRequest{ID: hostID})
 	if err != nil {
 		// If it is not there, create it and its relation
-		r.createHost(hostID, ipAddr, host)
+		r.createHost(hostID, host, device, agentID)
+		return
+	}
+
+	// Otherwise, if it is attached elsewhere, it has moved; either way, update its addresses if they changed
+	changed, err := syncHostAddresses(resp.Object, host)
+	if err != nil {
+		log.Warnf("Unable to set addresses of host %s: %+v", hostID, err)
+		return
+	}
+	location := newHostLocation(device.ID, host)
+	locations := getHostLocations(resp.Object)
+	if locations.Current == nil || !locations.Current.sameAs(location) {
+		r.moveHost(resp.Object, locations, location, device, agentID)
+		return
+	}
+	if changed {
+		r.updateHost(resp.Object)
+	}
+
+	// Restore the relation if it could not be created when the host was last created or moved
+	if len(resp.Object.GetEntity().GetTgtRelationIDs()) == 0 && r.createHostRelation(hostID, device.ID, host.Port) {
+		log.Infof("Restored relation of host %s at %s", hostID, location)
+	}
+}
+
+// Updates the host entity following changes of its addresses
+func (r *HostReconciler) updateHost(object *topo.Object) {
+	if _, err := r.topoClient.Update(r.ctx, &topo.UpdateRequest{Object: object}); err != nil {
+		log.Warnf("Unable to update host %s: %+v", object.ID, err)
 		return
 	}
-	// ToDo - a placeholder for pruning hosts
+	log.Debugf("Updated addresses of host %s to %s", object.ID, getHostAddresses(object))
+	countOperation(hostReconcilerName, updateOperation)
 }
 
 // Creates host topo object and its relation
-func (r *HostReconciler) createHost(hostID topo.ID, ipAddr topo.IPAddress, host *southbound.Host) {
-	hostAspect := &topo.NetworkInterface{MAC: host.MAC, IP: &ipAddr}
-	object, err := topo.NewEntity(hostID, topo.HostKind).WithAspects(hostAspect)
-	if err != nil {
+func (r *HostReconciler) createHost(hostID topo.ID, host *southbound.Host, device *topo.Object, agentID string) {
+	object := topo.NewEntity(hostID, topo.HostKind)
+	if _, err := syncHostAddresses(object, host); err != nil {
 		log.Warnf("Unable to allocate host %s: %+v", hostID, err)
 		return
 	}
-	// ToDo - where/how can I obtain labels?? Do I need it at all?
-	//object.Labels = labels // This should be passed in and should come from the connected device entity labels
-	if _, err = r.topoClient.Create(r.ctx, &topo.CreateRequest{Object: object}); err != nil {
+	object.Labels = hostLabels(device)
+	locations := &HostLocations{}
+	locations.moveTo(newHostLocation(device.ID, host))
+	if err := setHostLocations(object, locations); err != nil {
+		log.Warnf("Unable to set locations of host %s: %+v", hostID, err)
+		return
+	}
+	if _, err := r.topoClient.Create(r.ctx, &topo.CreateRequest{Object: object}); err != nil {
 		log.Warnf("Unable to create host %s: %+v", hostID, err)
 		return
 	}
 
-	portID := topo.ID(strconv.FormatUint(uint64(host.Port), 10))
-	originates := topo.NewRelation(portID, hostID, topo.ConnectionKind)
-	if _, err = r.topoClient.Create(r.ctx, &topo.CreateRequest{Object: originates}); err != nil {
-		log.Warnf("Unable to create originates relation for host %s: %+v", hostID, err)
+	// Remove the host if its relation cannot be created, so that it is created afresh when next reported,
+	// rather than being left with a location that no relation backs
+	if !r.createHostRelation(hostID, device.ID, host.Port) {
+		if _, err := r.topoClient.Delete(r.ctx, &topo.DeleteRequest{ID: hostID}); err != nil {
+			log.Warnf("Unable to remove host %s: %+v", hostID, err)
+		}
 		return
 	}
 	log.Infof("Created host %s", hostID)
+	countOperation(hostReconcilerName, createOperation)
+	r.events.Publish(&Event{Type: HostAdded, ObjectID: hostID, DeviceID: device.ID, AgentID: agentID,
+		Message: newHostAddresses(host).String()})
+}
+
+// Creates the connection relation from the port entity of the device to the host; returns false if it could not
+// be created
+func (r *HostReconciler) createHostRelation(hostID topo.ID, deviceID topo.ID, port uint32) bool {
+	portID := topo.ID(fmt.Sprintf("%s/%d", deviceID, port))
+	originates := topo.NewRelation(portID, hostID, topo.ConnectionKind)
+	if _, err := r.topoClient.Create(r.ctx, &topo.CreateRequest{Object: originates}); err != nil {
+		log.Warnf("Unable to create originates relation for host %s: %+v", hostID, err)
+		return false
+	}
+	return true
 }
@@ -0,0 +1,283 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestHostPruning(t *testing.T) {
+	f := newFakeTopo()
+	sb := newFakeSouthbound()
+	c := newTestController(f)
+	r := c.hostReconciler
+	r.hostDiscovery = sb
+	events := c.events.Watch(context.Background(), &EventFilter{Types: []EventType{HostRemoved}})
+
+	leaf1 := topo.NewEntity("leaf1", topo.SwitchKind)
+	sb.hosts["leaf1"] = &southbound.HostReport{AgentID: "agent1", Hosts: map[string]*southbound.Host{
+		"m1": {MAC: "m1", IPs: testIPs("10.0.0.1"), Port: 1},
+		"m2": {MAC: "m2", IPs: testIPs("10.0.0.2"), Port: 2},
+	}}
+	assert.NoError(t, r.DiscoverHosts(leaf1))
+	assert.True(t, f.has("pod1/0/m1"))
+	assert.True(t, f.has("pod1/0/m2"))
+	assert.True(t, f.has("leaf1/2-connection-pod1/0/m2"))
+
+	// Hosts missing from a full report are pruned along with their relations
+	delete(sb.hosts["leaf1"].Hosts, "m2")
+	assert.NoError(t, r.DiscoverHosts(leaf1))
+	assert.True(t, f.has("pod1/0/m1"))
+	assert.False(t, f.has("pod1/0/m2"))
+	assert.False(t, f.has("leaf1/2-connection-pod1/0/m2"))
+	event := <-events
+	assert.Equal(t, topo.ID("pod1/0/m2"), event.ObjectID)
+	assert.Equal(t, topo.ID("leaf1"), event.DeviceID)
+
+	// Hosts deleted by the agent are removed right away
+	r.HostDeleted(&southbound.Host{MAC: "m1", IPs: testIPs("10.0.0.1"), Port: 1}, "agent1")
+	assert.False(t, f.has("pod1/0/m1"))
+	assert.False(t, f.has("leaf1/1-connection-pod1/0/m1"))
+	assert.Equal(t, topo.ID("pod1/0/m1"), (<-events).ObjectID)
+
+	// Deleting an unknown host is a no-op
+	r.HostDeleted(&southbound.Host{MAC: "m9", Port: 9}, "agent1")
+}
+
+func TestHostAging(t *testing.T) {
+	f := newFakeTopo()
+	sb := newFakeSouthbound()
+	c := newTestController(f)
+	r := c.hostReconciler
+	r.hostDiscovery = sb
+
+	leaf1 := topo.NewEntity("leaf1", topo.SwitchKind)
+	sb.hosts["leaf1"] = &southbound.HostReport{AgentID: "agent1", Hosts: map[string]*southbound.Host{
+		"m1": {MAC: "m1", IPs: testIPs("10.0.0.1"), Port: 1},
+	}}
+	assert.NoError(t, r.DiscoverHosts(leaf1))
+
+	// A host of the device left behind by a previous instance, and a host of another device
+	_, err := f.Create(context.TODO(), &topo.CreateRequest{Object: newTestHost("pod1/0/m3", "leaf1")})
+	assert.NoError(t, err)
+	_, err = f.Create(context.TODO(), &topo.CreateRequest{Object: newTestHost("pod1/0/m4", "leaf2")})
+	assert.NoError(t, err)
+
+	// Recently seen hosts are retained, and hosts not seen so far start aging once encountered
+	r.ExpireHosts(time.Hour, nil)
+	assert.True(t, f.has("pod1/0/m1"))
+	assert.True(t, f.has("pod1/0/m3"))
+
+	r.lock.Lock()
+	r.lastSeen["pod1/0/m1"] = time.Now().Add(-2 * time.Hour)
+	r.lastSeen["pod1/0/m3"] = time.Now().Add(-2 * time.Hour)
+	r.lock.Unlock()
+	r.ExpireHosts(time.Hour, nil)
+	assert.False(t, f.has("pod1/0/m1"))
+	assert.False(t, f.has("pod1/0/m3"))
+	assert.True(t, f.has("pod1/0/m4"))
+
+	// Hosts of devices not owned by this instance are not aged
+	_, err = f.Create(context.TODO(), &topo.CreateRequest{Object: newTestHost("pod1/0/m5", "leaf1")})
+	assert.NoError(t, err)
+	r.lock.Lock()
+	r.lastSeen["pod1/0/m5"] = time.Now().Add(-2 * time.Hour)
+	r.lock.Unlock()
+	r.ExpireHosts(time.Hour, func(id topo.ID) bool { return id != "leaf1" })
+	assert.True(t, f.has("pod1/0/m5"))
+
+	// Hosts of released devices are no longer aged by this instance
+	r.ReleaseDevice("leaf1")
+	assert.Empty(t, r.agentDevices)
+}
+
+func TestHostMobility(t *testing.T) {
+	f := newFakeTopo()
+	sb := newFakeSouthbound()
+	c := newTestController(f)
+	r := c.hostReconciler
+	r.hostDiscovery = sb
+	events := c.events.Watch(context.Background(), &EventFilter{Types: []EventType{HostMoved, HostRemoved}})
+
+	leaf1 := topo.NewEntity("leaf1", topo.SwitchKind)
+	leaf2 := topo.NewEntity("leaf2", topo.SwitchKind)
+	sb.hosts["leaf1"] = &southbound.HostReport{AgentID: "agent1", Hosts: map[string]*southbound.Host{
+		"m1": {MAC: "m1", IPs: testIPs("10.0.0.1"), Port: 1},
+		"m2": {MAC: "m2", IPs: testIPs("10.0.0.2"), Port: 2, VLAN: 100},
+	}}
+	sb.hosts["leaf2"] = &southbound.HostReport{AgentID: "agent2", Hosts: map[string]*southbound.Host{}}
+	assert.NoError(t, r.DiscoverHosts(leaf1))
+	assert.NoError(t, r.DiscoverHosts(leaf2))
+	assert.True(t, f.has("pod1/0/m1"))
+	assert.True(t, f.has("pod1/100/m2"))
+	assert.True(t, f.has("leaf1/1-connection-pod1/0/m1"))
+
+	// The host shows up behind another device and is moved rather than re-created
+	delete(sb.hosts["leaf1"].Hosts, "m1")
+	sb.hosts["leaf2"].Hosts["m1"] = &southbound.Host{MAC: "m1", IPs: testIPs("10.0.0.1"), Port: 3}
+	assert.NoError(t, r.DiscoverHosts(leaf2))
+	event := <-events
+	assert.Equal(t, HostMoved, event.Type)
+	assert.Equal(t, topo.ID("pod1/0/m1"), event.ObjectID)
+	assert.Equal(t, topo.ID("leaf2"), event.DeviceID)
+	assert.Equal(t, topo.ID("leaf1"), event.PeerDeviceID)
+
+	resp, err := f.Get(context.TODO(), &topo.GetRequest{ID: "pod1/0/m1"})
+	assert.NoError(t, err)
+	host := resp.Object
+	assert.Equal(t, "leaf2", host.Labels[hostDeviceLabel])
+	locations := getHostLocations(host)
+	assert.Equal(t, topo.ID("leaf2"), locations.Current.DeviceID)
+	assert.Equal(t, uint32(3), locations.Current.Port)
+	assert.Len(t, locations.History, 1)
+	assert.Equal(t, topo.ID("leaf1"), locations.History[0].DeviceID)
+	assert.False(t, f.has("leaf1/1-connection-pod1/0/m1"))
+	assert.True(t, f.has("leaf2/3-connection-pod1/0/m1"))
+
+	// The previous device no longer reporting the host, or deleting it, does not remove it
+	assert.NoError(t, r.DiscoverHosts(leaf1))
+	r.HostDeleted(&southbound.Host{MAC: "m1", Port: 1}, "agent1")
+	assert.True(t, f.has("pod1/0/m1"))
+	assert.Len(t, events, 0)
+
+	// The location history is bounded
+	for i := 0; i < 2*maxLocationHistory; i++ {
+		sb.hosts["leaf2"].Hosts["m1"].Port = uint32(10 + i)
+		assert.NoError(t, r.DiscoverHosts(leaf2))
+		<-events
+	}
+	resp, err = f.Get(context.TODO(), &topo.GetRequest{ID: "pod1/0/m1"})
+	assert.NoError(t, err)
+	assert.Len(t, getHostLocations(resp.Object).History, maxLocationHistory)
+}
+
+func TestHostPortRelation(t *testing.T) {
+	f := newFakeTopo()
+	sb := newFakeSouthbound()
+	c := newTestController(f)
+	r := c.hostReconciler
+	r.hostDiscovery = sb
+
+	leaf1 := topo.NewEntity("leaf1", topo.SwitchKind)
+	leaf1.Labels = map[string]string{"pod": "pod1", "rack": "r1", agentIDLabel: "agent1"}
+	sb.hosts["leaf1"] = &southbound.HostReport{AgentID: "agent1", Hosts: map[string]*southbound.Host{
+		"m1": {MAC: "m1", IPs: testIPs("10.0.0.1"), Port: 1},
+	}}
+	assert.NoError(t, r.DiscoverHosts(leaf1))
+
+	// The host is related to the port entity of the device and takes on the device labels but its agent ID
+	resp, err := f.Get(context.TODO(), &topo.GetRequest{ID: "leaf1/1-connection-pod1/0/m1"})
+	assert.NoError(t, err)
+	assert.Equal(t, topo.ID("leaf1/1"), resp.Object.GetRelation().SrcEntityID)
+	assert.Equal(t, topo.ID("pod1/0/m1"), resp.Object.GetRelation().TgtEntityID)
+
+	resp, err = f.Get(context.TODO(), &topo.GetRequest{ID: "pod1/0/m1"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"pod": "pod1", "rack": "r1", hostDeviceLabel: "leaf1"}, resp.Object.Labels)
+
+	// Hosts reported by agents not yet bound by host discovery are resolved via the link agent bindings
+	leaf2 := topo.NewEntity("leaf2", topo.SwitchKind)
+	leaf2.Labels = map[string]string{"pod": "pod1", "rack": "r2"}
+	c.linkReconciler.bindAgent("agent2", leaf2)
+	r.HostAdded(&southbound.Host{MAC: "m2", IPs: testIPs("10.0.0.2"), Port: 4}, "agent2")
+	assert.True(t, f.has("leaf2/4-connection-pod1/0/m2"))
+	resp, err = f.Get(context.TODO(), &topo.GetRequest{ID: "pod1/0/m2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "r2", resp.Object.Labels["rack"])
+
+	// Hosts whose relation cannot be created are removed, so that they are created afresh when next reported
+	f.failCreateAfter = 2
+	r.HostAdded(&southbound.Host{MAC: "m4", IPs: testIPs("10.0.0.4"), Port: 5}, "agent2")
+	assert.False(t, f.has("pod1/0/m4"))
+	r.HostAdded(&southbound.Host{MAC: "m4", IPs: testIPs("10.0.0.4"), Port: 5}, "agent2")
+	assert.True(t, f.has("leaf2/5-connection-pod1/0/m4"))
+
+	// Missing relations of hosts which stay put are restored
+	_, err = f.Delete(context.TODO(), &topo.DeleteRequest{ID: "leaf2/5-connection-pod1/0/m4"})
+	assert.NoError(t, err)
+	r.HostAdded(&southbound.Host{MAC: "m4", IPs: testIPs("10.0.0.4"), Port: 5}, "agent2")
+	assert.True(t, f.has("leaf2/5-connection-pod1/0/m4"))
+
+	// Hosts of unknown agents are ignored
+	r.HostAdded(&southbound.Host{MAC: "m3", IPs: testIPs("10.0.0.3"), Port: 1}, "agent3")
+	assert.False(t, f.has("pod1/0/m3"))
+}
+
+func TestHostAddresses(t *testing.T) {
+	f := newFakeTopo()
+	sb := newFakeSouthbound()
+	c := newTestController(f)
+	r := c.hostReconciler
+	r.hostDiscovery = sb
+
+	leaf1 := topo.NewEntity("leaf1", topo.SwitchKind)
+	seen := time.Now().Add(-time.Hour)
+	sb.hosts["leaf1"] = &southbound.HostReport{AgentID: "agent1", Hosts: map[string]*southbound.Host{
+		"m1": {MAC: "m1", IPs: testIPs("fe80::1", "10.0.0.1"), Port: 1, VLAN: 10, LastSeen: seen},
+		"m2": {MAC: "m2", IPs: testIPs("2001:db8::2"), Port: 2},
+	}}
+	assert.NoError(t, r.DiscoverHosts(leaf1))
+
+	// Dual-stack hosts carry their IPv4 address in the network interface aspect and all addresses alongside
+	host := getTestObject(t, f, "pod1/10/m1")
+	nic := &topo.NetworkInterface{}
+	assert.NoError(t, host.GetAspect(nic))
+	assert.Equal(t, "m1", nic.MAC)
+	assert.Equal(t, "10.0.0.1", nic.IP.IP)
+	assert.Equal(t, topo.IPAddress_IPV4, nic.IP.Type)
+	addresses := getHostAddresses(host)
+	assert.Equal(t, []HostAddress{{IP: "fe80::1", Family: "ipv6"}, {IP: "10.0.0.1", Family: "ipv4"}}, addresses.Addresses)
+	assert.Equal(t, uint32(10), addresses.VLAN)
+	assert.True(t, seen.Equal(addresses.LastSeen))
+
+	// IPv6-only hosts carry their first IPv6 address in the network interface aspect
+	assert.NoError(t, getTestObject(t, f, "pod1/0/m2").GetAspect(nic))
+	assert.Equal(t, "2001:db8::2", nic.IP.IP)
+	assert.Equal(t, topo.IPAddress_IPV6, nic.IP.Type)
+
+	// Address changes are reflected in the host entity
+	sb.hosts["leaf1"].Hosts["m1"].IPs = testIPs("10.0.0.9")
+	assert.NoError(t, r.DiscoverHosts(leaf1))
+	host = getTestObject(t, f, "pod1/10/m1")
+	assert.NoError(t, host.GetAspect(nic))
+	assert.Equal(t, "10.0.0.9", nic.IP.IP)
+	assert.Equal(t, []HostAddress{{IP: "10.0.0.9", Family: "ipv4"}}, getHostAddresses(host).Addresses)
+
+	// The last-seen time alone is written back only once it advances by more than its resolution
+	sb.hosts["leaf1"].Hosts["m1"].LastSeen = seen.Add(time.Second)
+	assert.NoError(t, r.DiscoverHosts(leaf1))
+	assert.True(t, seen.Equal(getHostAddresses(getTestObject(t, f, "pod1/10/m1")).LastSeen))
+	sb.hosts["leaf1"].Hosts["m1"].LastSeen = seen.Add(lastSeenResolution)
+	assert.NoError(t, r.DiscoverHosts(leaf1))
+	assert.True(t, seen.Add(lastSeenResolution).Equal(getHostAddresses(getTestObject(t, f, "pod1/10/m1")).LastSeen))
+}
+
+// Returns the addresses with their families detected
+func testIPs(ips ...string) []*topo.IPAddress {
+	addresses := make([]*topo.IPAddress, 0, len(ips))
+	for _, ip := range ips {
+		addresses = append(addresses, southbound.NewIPAddress(ip))
+	}
+	return addresses
+}
+
+// Returns the object with the given ID from the fake topo
+func getTestObject(t *testing.T, f *fakeTopo, id topo.ID) *topo.Object {
+	resp, err := f.Get(context.TODO(), &topo.GetRequest{ID: id})
+	assert.NoError(t, err)
+	return resp.Object
+}
+
+// Returns a host entity attached to the given device
+func newTestHost(id topo.ID, deviceID topo.ID) *topo.Object {
+	host := topo.NewEntity(id, topo.HostKind)
+	host.Labels = map[string]string{hostDeviceLabel: string(deviceID)}
+	return host
+}
@@ -0,0 +1,25 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+)
+
+// ImportInventory validates the given inventory in its entirety and then adds all its assets, reporting
+// the outcome for each one
+func (c *Controller) ImportInventory(ctx context.Context, inv *inventory.Inventory) (*inventory.Report, error) {
+	if c.getState() != Monitoring {
+		return nil, errors.NewUnavailable(controllerNotReady)
+	}
+	if err := inv.Validate(); err != nil {
+		return nil, err
+	}
+	report := inv.Apply(ctx, c)
+	log.Infof("Imported inventory with %d items; %d failed", len(report.Results), report.Failures())
+	return report, nil
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"io"
+	"strings"
+	"time"
+)
+
+// Time for which links are allowed to lack their originates/terminates relations, as they are created after the
+// link entity itself
+const linkRelationsGracePeriod = 2 * time.Minute
+
+// CollectLinks removes the links selected by the given filters which have been DOWN for longer than the retention
+// period or whose ports no longer exist, along with their originates/terminates relations. Only the links passing
+// the given ownership check are considered, all of them if nil; a link is owned along with the device at which it
+// terminates. In dry-run mode the candidates are only reported. Returns the IDs of the links removed, or which
+// would have been removed.
+func (r *LinkReconciler) CollectLinks(filters *topo.Filters, retention time.Duration, dryRun bool, owns func(id topo.ID) bool) ([]topo.ID, error) {
+	stream, err := r.topoClient.Query(r.ctx, &topo.QueryRequest{Filters: filters})
+	if err != nil {
+		return nil, errors.FromGRPC(err)
+	}
+	links := make([]*topo.Object, 0)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.FromGRPC(err)
+		}
+		links = append(links, resp.Object)
+	}
+
+	now := time.Now()
+	incompleteSince := make(map[topo.ID]time.Time)
+	candidates := make([]topo.ID, 0)
+	for _, link := range links {
+		egressPortID, ingressPortID, err := r.linkPorts(link)
+		if err != nil {
+			log.Warnf("Unable to check ports of link %s: %+v", link.ID, err)
+			continue
+		}
+
+		// Links lacking their terminates relation are owned as per their own ID
+		owner := link.ID
+		if len(ingressPortID) > 0 {
+			owner = portDeviceID(ingressPortID)
+		}
+		if owns != nil && !owns(owner) {
+			continue
+		}
+
+		if len(egressPortID) == 0 || len(ingressPortID) == 0 {
+			r.lock.RLock()
+			since, ok := r.incompleteSince[link.ID]
+			r.lock.RUnlock()
+			if !ok {
+				since = now
+			}
+			incompleteSince[link.ID] = since
+		}
+		reason := r.linkGarbageReason(link, egressPortID, ingressPortID, retention, incompleteSince[link.ID], now)
+		if len(reason) == 0 {
+			continue
+		}
+		if dryRun {
+			log.Infof("Link %s would be removed; %s", link.ID, reason)
+			candidates = append(candidates, link.ID)
+			continue
+		}
+		if r.removeLink(link, reason) {
+			candidates = append(candidates, link.ID)
+		}
+	}
+
+	r.lock.Lock()
+	r.incompleteSince = incompleteSince
+	r.lock.Unlock()
+	return candidates, nil
+}
+
+// Returns the IDs of the ports at which the link originates and terminates; either is empty if the respective
+// relation does not exist
+func (r *LinkReconciler) linkPorts(link *topo.Object) (topo.ID, topo.ID, error) {
+	var egressPortID, ingressPortID topo.ID
+	for _, relationID := range link.GetEntity().GetTgtRelationIDs() {
+		resp, err := r.topoClient.Get(r.ctx, &topo.GetRequest{ID: relationID})
+		if err != nil {
+			if err = errors.FromGRPC(err); errors.IsNotFound(err) {
+				continue
+			}
+			return "", "", err
+		}
+		relation := resp.Object.GetRelation()
+		switch relation.GetKindID() {
+		case topo.OriginatesKind:
+			egressPortID = relation.GetSrcEntityID()
+		case topo.TerminatesKind:
+			ingressPortID = relation.GetSrcEntityID()
+		}
+	}
+	return egressPortID, ingressPortID, nil
+}
+
+// Returns the ID of the device to which the port belongs, as port IDs are composed of the device ID and the port
+// number
+func portDeviceID(portID topo.ID) topo.ID {
+	if i := strings.LastIndex(string(portID), "/"); i > 0 {
+		return portID[:i]
+	}
+	return portID
+}
+
+// Returns why the link should be removed, or an empty string if it should be retained; links lacking relations
+// are retained for the grace period following their last status change, or since they were first found lacking
+func (r *LinkReconciler) linkGarbageReason(link *topo.Object, egressPortID topo.ID, ingressPortID topo.ID,
+	retention time.Duration, incompleteSince time.Time, now time.Time) string {
+	linkAspect := &topo.Link{}
+	if err := link.GetAspect(linkAspect); err == nil && linkAspect.Status == statusDown {
+		if down := now.Sub(time.Unix(0, int64(linkAspect.LastChange))); down > retention {
+			return fmt.Sprintf("down for %s", down.Round(time.Second))
+		}
+	}
+
+	// Each link originates at one port and terminates at another
+	if len(egressPortID) == 0 || len(ingressPortID) == 0 {
+		since := incompleteSince
+		if lastChange := time.Unix(0, int64(linkAspect.LastChange)); lastChange.After(since) {
+			since = lastChange
+		}
+		if now.Sub(since) > linkRelationsGracePeriod {
+			return "port relations missing"
+		}
+		return ""
+	}
+	for _, portID := range []topo.ID{egressPortID, ingressPortID} {
+		if _, err := r.topoClient.Get(r.ctx, &topo.GetRequest{ID: portID}); err != nil {
+			if err = errors.FromGRPC(err); errors.IsNotFound(err) {
+				return fmt.Sprintf("port %s no longer exists", portID)
+			}
+			log.Warnf("Unable to check ports of link %s: %+v", link.ID, err)
+			return ""
+		}
+	}
+	return ""
+}
+
+// Removes the link entity along with its relations; returns true if the link was removed
+func (r *LinkReconciler) removeLink(link *topo.Object, reason string) bool {
+	// The counterpart of a paired link has to be re-paired once the link is gone; its ports are known only
+	// while the relations exist
+	var egressPortID, ingressPortID topo.ID
+	if len(link.Labels[linkPairLabel]) > 0 {
+		egressPortID, ingressPortID, _ = r.linkPorts(link)
+	}
+	relationIDs := append(append([]topo.ID{}, link.GetEntity().GetTgtRelationIDs()...), link.GetEntity().GetSrcRelationIDs()...)
+	for _, relationID := range relationIDs {
+		if _, err := r.topoClient.Delete(r.ctx, &topo.DeleteRequest{ID: relationID}); err != nil {
+			if err = errors.FromGRPC(err); !errors.IsNotFound(err) {
+				log.Warnf("Unable to remove relation %s of link %s: %+v", relationID, link.ID, err)
+				return false
+			}
+		}
+	}
+	if _, err := r.topoClient.Delete(r.ctx, &topo.DeleteRequest{ID: link.ID}); err != nil {
+		if err = errors.FromGRPC(err); !errors.IsNotFound(err) {
+			log.Warnf("Unable to remove link %s: %+v", link.ID, err)
+		}
+		return false
+	}
+	r.lock.Lock()
+	delete(r.aloneSince, link.ID)
+	r.lock.Unlock()
+	log.Infof("Removed link %s; %s", link.ID, reason)
+	countOperation(linkReconcilerName, deleteOperation)
+	r.events.Publish(&Event{Type: LinkRemoved, ObjectID: link.ID, Message: reason})
+	if len(egressPortID) > 0 && len(ingressPortID) > 0 {
+		if reverse := r.getLink(topo.ID(fmt.Sprintf("%s-%s", ingressPortID, egressPortID))); reverse != nil {
+			r.pairLink(reverse, ingressPortID, egressPortID, true)
+		}
+	}
+	return true
+}
+
+// Removes stale links of the realm terminating at our devices as per the link retention policy
+func (c *Controller) collectLinks(cfg Config) {
+	if _, err := c.linkReconciler.CollectLinks(c.realmFilters(topo.LinkKind), cfg.LinkRetention, cfg.LinkGCDryRun, c.owns); err != nil {
+		log.Warnf("Unable to collect stale links: %+v", err)
+	}
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLinkCollection(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	r := c.linkReconciler
+	events := c.events.Watch(context.Background(), &EventFilter{Types: []EventType{LinkRemoved}})
+
+	create := func(object *topo.Object) {
+		_, err := f.Create(context.TODO(), &topo.CreateRequest{Object: object})
+		assert.NoError(t, err)
+	}
+	link := func(egressPortID topo.ID, ingressPortID topo.ID, status string, lastChange time.Time, realm string) {
+		linkID := egressPortID + "-" + ingressPortID
+		object, err := topo.NewEntity(linkID, topo.LinkKind).
+			WithAspects(&topo.Link{Status: status, LastChange: uint64(lastChange.UnixNano())})
+		assert.NoError(t, err)
+		object.Labels = map[string]string{"pod": realm}
+		create(object)
+		create(topo.NewRelation(egressPortID, linkID, topo.OriginatesKind))
+		create(topo.NewRelation(ingressPortID, linkID, topo.TerminatesKind))
+	}
+	for _, id := range []topo.ID{"spine1/1", "spine1/2", "spine1/3", "leaf1/1", "leaf2/1", "leaf3/1"} {
+		create(topo.NewEntity(id, topo.PortKind))
+	}
+	now := time.Now()
+	link("spine1/1", "leaf1/1", statusUp, now.Add(-48*time.Hour), "pod1")
+	link("spine1/2", "leaf2/1", statusDown, now.Add(-48*time.Hour), "pod1")
+	link("spine1/3", "leaf3/1", statusDown, now.Add(-time.Hour), "pod1")
+	link("spine1/4", "leaf4/1", statusUp, now, "pod1")
+	link("spine1/5", "leaf5/1", statusDown, now.Add(-48*time.Hour), "pod2")
+
+	// In dry-run mode the candidates are only reported
+	filters := c.realmFilters(topo.LinkKind)
+	candidates, err := r.CollectLinks(filters, 24*time.Hour, true, nil)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []topo.ID{"spine1/2-leaf2/1", "spine1/4-leaf4/1"}, candidates)
+	assert.True(t, f.has("spine1/2-leaf2/1"))
+	assert.True(t, f.has("spine1/4-leaf4/1"))
+	assert.Len(t, events, 0)
+
+	// Links down for too long and links whose ports are gone are removed along with their relations
+	candidates, err = r.CollectLinks(filters, 24*time.Hour, false, nil)
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []topo.ID{"spine1/2-leaf2/1", "spine1/4-leaf4/1"}, candidates)
+	assert.False(t, f.has("spine1/2-leaf2/1"))
+	assert.False(t, f.has("spine1/2-originates-spine1/2-leaf2/1"))
+	assert.False(t, f.has("leaf2/1-terminates-spine1/2-leaf2/1"))
+	assert.False(t, f.has("spine1/4-leaf4/1"))
+	assert.True(t, f.has("spine1/1-leaf1/1"))
+	assert.True(t, f.has("spine1/3-leaf3/1"))
+	assert.True(t, f.has("spine1/5-leaf5/1"))
+	assert.True(t, f.has("leaf2/1"))
+	removed := []topo.ID{(<-events).ObjectID, (<-events).ObjectID}
+	assert.ElementsMatch(t, []topo.ID{"spine1/2-leaf2/1", "spine1/4-leaf4/1"}, removed)
+
+	// Once a link loses a port, it is removed too
+	_, err = f.Delete(context.TODO(), &topo.DeleteRequest{ID: "leaf3/1"})
+	assert.NoError(t, err)
+	candidates, err = r.CollectLinks(filters, 24*time.Hour, false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []topo.ID{"spine1/3-leaf3/1"}, candidates)
+
+	// Links lacking relations are removed only once the grace period has passed
+	object, err := topo.NewEntity("spine1/6-leaf6/1", topo.LinkKind).
+		WithAspects(&topo.Link{Status: statusUp, LastChange: uint64(now.Add(-time.Hour).UnixNano())})
+	assert.NoError(t, err)
+	object.Labels = map[string]string{"pod": "pod1"}
+	create(object)
+	candidates, err = r.CollectLinks(filters, 24*time.Hour, false, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, candidates)
+	r.lock.Lock()
+	r.incompleteSince["spine1/6-leaf6/1"] = now.Add(-time.Hour)
+	r.lock.Unlock()
+	candidates, err = r.CollectLinks(filters, 24*time.Hour, false, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []topo.ID{"spine1/6-leaf6/1"}, candidates)
+
+	// Only the links terminating at owned devices are collected
+	link("spine1/7", "leaf7/1", statusDown, now.Add(-48*time.Hour), "pod1")
+	owns := func(id topo.ID) bool { return id != "leaf7" }
+	candidates, err = r.CollectLinks(filters, 24*time.Hour, false, owns)
+	assert.NoError(t, err)
+	assert.Empty(t, candidates)
+	assert.True(t, f.has("spine1/7-leaf7/1"))
+}
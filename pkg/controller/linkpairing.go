@@ -0,0 +1,135 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+	"time"
+)
+
+const (
+	// Label shared by the two links connecting the same pair of ports in opposite directions; its value is the
+	// lesser of the two link IDs
+	linkPairLabel = "link-pair"
+
+	// Label marking links which are up while the link in the opposite direction has not been seen up for longer
+	// than the asymmetry threshold
+	asymmetricLabel = "asymmetric"
+)
+
+// SetAsymmetryThreshold sets the time after which links seen in one direction only are flagged as asymmetric;
+// zero disables the flagging
+func (r *LinkReconciler) SetAsymmetryThreshold(threshold time.Duration) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.asymmetryThreshold = threshold
+}
+
+// Pairs the link with its counterpart in the opposite direction, if there is one, and flags the link as
+// asymmetric if it has been up without its counterpart for longer than the asymmetry threshold. Paired links are
+// re-checked only once created or their status changed, as their counterparts update them when changing status.
+func (r *LinkReconciler) pairLink(link *topo.Object, egressPortID topo.ID, ingressPortID topo.ID, changed bool) {
+	linkID := link.ID
+	r.lock.RLock()
+	_, alone := r.aloneSince[linkID]
+	r.lock.RUnlock()
+	if !changed && !alone && len(link.Labels[linkPairLabel]) > 0 {
+		return
+	}
+
+	reverseID := topo.ID(fmt.Sprintf("%s-%s", ingressPortID, egressPortID))
+	reverse := r.getLink(reverseID)
+
+	pairID := ""
+	if reverse != nil {
+		pairID = string(linkID)
+		if reverseID < linkID {
+			pairID = string(reverseID)
+		}
+	}
+	asymmetric := r.checkAsymmetry(linkID, linkStatus(link) == statusUp &&
+		linkStatus(reverse) != statusUp && r.expectsReverse(egressPortID, ingressPortID))
+
+	wasAsymmetric := link.Labels[asymmetricLabel] == "true"
+	if r.syncPairLabels(link, pairID, asymmetric) && asymmetric && !wasAsymmetric {
+		log.Warnf("Link %s is asymmetric; link %s has not been seen up", linkID, reverseID)
+		r.events.Publish(&Event{Type: LinkAsymmetric, ObjectID: linkID, Message: fmt.Sprintf("%s not seen up", reverseID)})
+	}
+	if reverse != nil {
+		// The counterpart is no longer asymmetric once this link is up, and is alone while this link is not
+		up := linkStatus(link) == statusUp
+		r.checkAsymmetry(reverseID, !up && linkStatus(reverse) == statusUp && r.expectsReverse(ingressPortID, egressPortID))
+		r.syncPairLabels(reverse, pairID, reverse.Labels[asymmetricLabel] == "true" && !up)
+	}
+}
+
+// Tracks since when the link has been up without its counterpart; returns true once that has lasted longer than
+// the asymmetry threshold
+func (r *LinkReconciler) checkAsymmetry(linkID topo.ID, alone bool) bool {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if !alone {
+		delete(r.aloneSince, linkID)
+		return false
+	}
+	since, ok := r.aloneSince[linkID]
+	if !ok {
+		since = time.Now()
+		r.aloneSince[linkID] = since
+	}
+	return r.asymmetryThreshold > 0 && time.Since(since) > r.asymmetryThreshold
+}
+
+// Returns false if the cabling plan expects the link to be unidirectional
+func (r *LinkReconciler) expectsReverse(egressPortID topo.ID, ingressPortID topo.ID) bool {
+	if cabling, _ := r.checkCabling(egressPortID, ingressPortID); cabling != inventory.CablingOK {
+		return true
+	}
+	cabling, _ := r.checkCabling(ingressPortID, egressPortID)
+	return cabling == inventory.CablingOK
+}
+
+// Syncs the pair and asymmetry labels of the link; returns true if they changed
+func (r *LinkReconciler) syncPairLabels(link *topo.Object, pairID string, asymmetric bool) bool {
+	labels := map[string]string{linkPairLabel: pairID}
+	if asymmetric {
+		labels[asymmetricLabel] = "true"
+	}
+	if !syncLabels(link, labels, linkPairLabel, asymmetricLabel) {
+		return false
+	}
+	resp, err := r.topoClient.Update(r.ctx, &topo.UpdateRequest{Object: link})
+	if err != nil {
+		log.Warnf("Unable to update pairing of link %s: %+v", link.ID, err)
+		return false
+	}
+	link.Revision = resp.Object.Revision
+	countOperation(linkReconcilerName, updateOperation)
+	return true
+}
+
+// Returns the link entity, or nil if it does not exist or could not be read
+func (r *LinkReconciler) getLink(linkID topo.ID) *topo.Object {
+	resp, err := r.topoClient.Get(r.ctx, &topo.GetRequest{ID: linkID})
+	if err != nil {
+		if err = errors.FromGRPC(err); !errors.IsNotFound(err) {
+			log.Warnf("Unable to get link %s: %+v", linkID, err)
+		}
+		return nil
+	}
+	return resp.Object
+}
+
+// Returns the status of the link entity, or an empty string if there is no link
+func linkStatus(link *topo.Object) string {
+	linkAspect := &topo.Link{}
+	if link == nil || link.GetAspect(linkAspect) != nil {
+		return ""
+	}
+	return linkAspect.Status
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestLinkPairing(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	r := c.linkReconciler
+	r.SetAsymmetryThreshold(time.Minute)
+	cfg := DefaultConfig()
+	cfg.FlapPenalty = 0
+	c.dampener.configure(cfg)
+	events := c.events.Watch(context.Background(), &EventFilter{Types: []EventType{LinkAsymmetric}})
+	r.registerReport(topo.NewEntity("spine1", topo.SwitchKind), &southbound.LinkReport{AgentID: "s1"})
+	r.registerReport(topo.NewEntity("leaf1", topo.SwitchKind), &southbound.LinkReport{AgentID: "l1"})
+	r.registerReport(topo.NewEntity("ipu1", topo.IPUKind), &southbound.LinkReport{AgentID: "i1"})
+
+	report := func(ingress string, ingressPort uint32, egress string, egressPort uint32, status string) {
+		r.reconcileLink(&southbound.Link{IngressDevice: ingress, IngressPort: ingressPort, EgressDevice: egress,
+			EgressPort: egressPort, CreateTime: uint64(time.Now().UnixNano())}, status)
+	}
+	labels := func(id topo.ID) map[string]string {
+		return getTestObject(t, f, id).Labels
+	}
+	backdate := func(id topo.ID) {
+		r.lock.Lock()
+		r.aloneSince[id] = time.Now().Add(-2 * time.Minute)
+		r.lock.Unlock()
+	}
+
+	// A link seen in one direction only is not paired, and is flagged as asymmetric once past the threshold
+	report("s1", 1, "l1", 1, statusUp)
+	assert.Empty(t, labels("leaf1/1-spine1/1")[linkPairLabel])
+	assert.Empty(t, labels("leaf1/1-spine1/1")[asymmetricLabel])
+	backdate("leaf1/1-spine1/1")
+	report("s1", 1, "l1", 1, statusUp)
+	assert.Equal(t, "true", labels("leaf1/1-spine1/1")[asymmetricLabel])
+	event := <-events
+	assert.Equal(t, topo.ID("leaf1/1-spine1/1"), event.ObjectID)
+	report("s1", 1, "l1", 1, statusUp)
+	assert.Len(t, events, 0)
+
+	// Once the opposite direction is seen, both links are paired and no longer asymmetric
+	report("l1", 1, "s1", 1, statusUp)
+	assert.Equal(t, "leaf1/1-spine1/1", labels("leaf1/1-spine1/1")[linkPairLabel])
+	assert.Equal(t, "leaf1/1-spine1/1", labels("spine1/1-leaf1/1")[linkPairLabel])
+	assert.Empty(t, labels("leaf1/1-spine1/1")[asymmetricLabel])
+	assert.Empty(t, labels("spine1/1-leaf1/1")[asymmetricLabel])
+
+	// Paired links are not re-checked while their status holds
+	gets := f.gets
+	report("l1", 1, "s1", 1, statusUp)
+	assert.Equal(t, gets+1, f.gets)
+
+	// Links going down in one direction remain paired, but the other direction becomes asymmetric
+	report("l1", 1, "s1", 1, statusDown)
+	r.lock.RLock()
+	assert.Contains(t, r.aloneSince, topo.ID("leaf1/1-spine1/1"))
+	r.lock.RUnlock()
+	backdate("leaf1/1-spine1/1")
+	report("s1", 1, "l1", 1, statusUp)
+	assert.Equal(t, "leaf1/1-spine1/1", labels("leaf1/1-spine1/1")[linkPairLabel])
+	assert.Equal(t, "true", labels("leaf1/1-spine1/1")[asymmetricLabel])
+	assert.Equal(t, topo.ID("leaf1/1-spine1/1"), (<-events).ObjectID)
+	report("l1", 1, "s1", 1, statusUp)
+	assert.Empty(t, labels("leaf1/1-spine1/1")[asymmetricLabel])
+
+	// The same applies when a link is taken down by its port going down
+	r.MarkPortLinksDown(topo.NewEntity("spine1", topo.SwitchKind), topo.NewEntity("spine1/1", topo.PortKind))
+	assert.Equal(t, statusDown, linkStatus(getTestObject(t, f, "spine1/1-leaf1/1")))
+	backdate("leaf1/1-spine1/1")
+	report("s1", 1, "l1", 1, statusUp)
+	assert.Equal(t, "true", labels("leaf1/1-spine1/1")[asymmetricLabel])
+	assert.Equal(t, topo.ID("leaf1/1-spine1/1"), (<-events).ObjectID)
+
+	// Removing a link unpairs its counterpart
+	assert.True(t, r.removeLink(getTestObject(t, f, "spine1/1-leaf1/1"), "test"))
+	assert.Empty(t, labels("leaf1/1-spine1/1")[linkPairLabel])
+	assert.Equal(t, "true", labels("leaf1/1-spine1/1")[asymmetricLabel])
+
+	// Links the cabling plan expects to be unidirectional are not flagged
+	plan, err := inventory.NewCablingPlan(&inventory.Cable{Source: "leaf1/10", Target: "ipu1/1", Unidirectional: true})
+	assert.NoError(t, err)
+	c.SetCablingPlan(plan)
+	report("i1", 1, "l1", 10, statusUp)
+	backdate("leaf1/10-ipu1/1")
+	report("i1", 1, "l1", 10, statusUp)
+	assert.Empty(t, labels("leaf1/10-ipu1/1")[asymmetricLabel])
+	assert.Len(t, events, 0)
+}
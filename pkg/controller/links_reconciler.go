@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
 	"github.com/onosproject/topo-discovery/pkg/southbound"
 	"io"
 	"sync"
@@ -17,6 +18,17 @@ import (
 const (
 	statusUp   = "UP"
 	statusDown = "DOWN"
+
+	// Label indicating how the link relates to the expected cabling plan, if one is loaded
+	cablingLabel = "cabling"
+
+	// Label marking links taken DOWN because one of their ports went down, rather than as reported by the link
+	// agent; such links are restored once reported again while both of their ports are up
+	portDownLabel = "port-down"
+
+	// Label carrying the link agent ID of a device, allowing instances discovering other shards of the realm to
+	// resolve links to the device
+	agentIDLabel = "link-agent-id"
 )
 
 // LinkReconciler provides state and context required for link discovery and reconciliation
@@ -25,6 +37,7 @@ type LinkReconciler struct {
 	linkDiscovery southbound.IngressLinkDiscovery
 	topoClient    topo.TopoClient
 	ctx           context.Context
+	events        *EventBroker
 	lock          sync.RWMutex
 
 	// Map of agent-id to topo object required to resolve link reports to a device
@@ -33,34 +46,65 @@ type LinkReconciler struct {
 	// Map of agent-id to a list of links that reference that agent ID, but cannot be
 	// resolved yet because that agent-id has not yet been registered
 	pendingLinks map[string][]*southbound.Link
+
+	// Expected cabling plan, if any, against which the links are checked
+	cablingPlan *inventory.CablingPlan
+
+	// Indicates whether agent IDs are shared with the other instances via onos-topo
+	agentIDSharing bool
+
+	// Dampens status changes of flapping links
+	dampener *dampener
+
+	// Time after which links seen in one direction only are flagged as asymmetric, and since when each link has
+	// been up without its counterpart
+	asymmetryThreshold time.Duration
+	aloneSince         map[topo.ID]time.Time
+
+	// Since when each link has been found lacking its originates/terminates relations by link collection
+	incompleteSince map[topo.ID]time.Time
 }
 
 // NewLinkReconciler creates a new link reconciler context
 func NewLinkReconciler(ctx context.Context, topoClient topo.TopoClient) *LinkReconciler {
 	return &LinkReconciler{
-		topoClient:    topoClient,
-		ctx:           ctx,
-		agentDevices:  make(map[string]*topo.Object),
-		pendingLinks:  make(map[string][]*southbound.Link),
-		linkDiscovery: southbound.NewGNMILinkDiscovery(),
+		topoClient:      topoClient,
+		ctx:             ctx,
+		agentDevices:    make(map[string]*topo.Object),
+		pendingLinks:    make(map[string][]*southbound.Link),
+		aloneSince:      make(map[topo.ID]time.Time),
+		incompleteSince: make(map[topo.ID]time.Time),
+		linkDiscovery:   southbound.NewGNMILinkDiscovery(),
 	}
 }
 
-// DiscoverLinks discovers links and reconciles their topology entity counterparts
-func (r *LinkReconciler) DiscoverLinks(object *topo.Object) {
+// DiscoverLinks discovers links and reconciles their topology entity counterparts; returns an error if the links
+// could not be discovered
+func (r *LinkReconciler) DiscoverLinks(object *topo.Object) error {
 	// Connect to the link agent gNMI server and get its agent ID and a map of ingress links
 	linkReport, err := r.linkDiscovery.GetIngressLinks(object, r)
 	if err != nil {
 		log.Warnf("Unable to get links from device link agent %s: %+v", object.ID, err)
-		return
+		return err
 	}
 
-	// Register the report and agent ID
-	linksToProcess := r.registerReport(object, linkReport)
+	// Resolve any agents bound by other instances, then register the report and agent ID
+	r.publishAgentID(object, linkReport.AgentID)
+	linksToProcess := r.resolveSharedAgents(linkReport)
+	linksToProcess = append(linksToProcess, r.registerReport(object, linkReport)...)
 	for _, link := range linksToProcess {
 		r.reconcileLink(link, statusUp)
 	}
 	r.updateDownedLinks(object, linkReport)
+	return nil
+}
+
+// Binds the agent ID to the device; emits an event if the binding is new. Must be called with the lock held.
+func (r *LinkReconciler) bindAgent(agentID string, object *topo.Object) {
+	if existing, ok := r.agentDevices[agentID]; !ok || existing.ID != object.ID {
+		r.events.Publish(&Event{Type: AgentRegistered, ObjectID: object.ID, DeviceID: object.ID, AgentID: agentID})
+	}
+	r.agentDevices[agentID] = object
 }
 
 // RegisterAgent discovers agentID and binds it to the specified object ID
@@ -72,9 +116,176 @@ func (r *LinkReconciler) RegisterAgent(object *topo.Object) {
 	}
 
 	// (Re)create the agent ID to device entity ID binding
+	r.lock.Lock()
+	r.bindAgent(report.AgentID, object)
+	r.lock.Unlock()
+	r.publishAgentID(object, report.AgentID)
+}
+
+// SetAgentIDSharing enables or disables sharing of agent IDs with other instances via onos-topo; when enabled, the
+// agent IDs of the devices discovered by this instance are recorded as device labels, and agent IDs unknown to this
+// instance are resolved by looking up those labels
+func (r *LinkReconciler) SetAgentIDSharing(enabled bool) {
 	r.lock.Lock()
 	defer r.lock.Unlock()
-	r.agentDevices[report.AgentID] = object
+	r.agentIDSharing = enabled
+}
+
+// Returns true if agent IDs are shared via onos-topo
+func (r *LinkReconciler) sharingAgentIDs() bool {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.agentIDSharing
+}
+
+// Records the agent ID as a label of the device entity, unless it is already recorded there
+func (r *LinkReconciler) publishAgentID(object *topo.Object, agentID string) {
+	if !r.sharingAgentIDs() || object.Labels[agentIDLabel] == agentID {
+		return
+	}
+	resp, err := r.topoClient.Get(r.ctx, &topo.GetRequest{ID: object.ID})
+	if err != nil {
+		log.Warnf("Unable to get device %s: %+v", object.ID, err)
+		return
+	}
+	device := resp.Object
+	if device.Labels[agentIDLabel] == agentID {
+		return
+	}
+	if device.Labels == nil {
+		device.Labels = make(map[string]string)
+	}
+	device.Labels[agentIDLabel] = agentID
+	if _, err = r.topoClient.Update(r.ctx, &topo.UpdateRequest{Object: device}); err != nil {
+		log.Warnf("Unable to record agent ID %s of device %s: %+v", agentID, object.ID, err)
+	}
+}
+
+// Resolves the egress agents of the reported links which are not known to this instance by looking up the agent
+// IDs recorded by other instances; returns any pending links which can now be processed
+func (r *LinkReconciler) resolveSharedAgents(report *southbound.LinkReport) []*southbound.Link {
+	if !r.sharingAgentIDs() {
+		return nil
+	}
+	links := make([]*southbound.Link, 0)
+	resolved := make(map[string]bool)
+	for _, link := range report.Links {
+		if resolved[link.EgressDevice] {
+			continue
+		}
+		resolved[link.EgressDevice] = true
+		r.lock.RLock()
+		_, ok := r.agentDevices[link.EgressDevice]
+		r.lock.RUnlock()
+		if !ok {
+			links = append(links, r.resolveSharedAgent(link.EgressDevice)...)
+		}
+	}
+	return links
+}
+
+// Looks up the device whose entity carries the given agent ID label and binds the agent ID to it; returns any
+// pending links of the agent, which can now be processed
+func (r *LinkReconciler) resolveSharedAgent(agentID string) []*southbound.Link {
+	filters := &topo.Filters{LabelFilters: []*topo.Filter{{Key: agentIDLabel,
+		Filter: &topo.Filter_Equal_{Equal_: &topo.EqualFilter{Value: agentID}}}}}
+	stream, err := r.topoClient.Query(r.ctx, &topo.QueryRequest{Filters: filters})
+	if err != nil {
+		log.Warnf("Unable to look up agent %s: %+v", agentID, err)
+		return nil
+	}
+	var device *topo.Object
+	for device == nil {
+		resp, err := stream.Recv()
+		if err != nil {
+			if err != io.EOF {
+				log.Warnf("Unable to look up agent %s: %+v", agentID, err)
+			}
+			return nil
+		}
+		// Ports and links inherit the device labels; only the device itself has the agents aspects
+		if resp.Object.GetAspectBytes("onos.topo.StratumAgents") != nil || resp.Object.GetAspectBytes("onos.topo.LocalAgents") != nil {
+			device = resp.Object
+		}
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	log.Infof("Resolved agent %s to device %s discovered by another instance", agentID, device.ID)
+	r.agentDevices[agentID] = device
+	pending := r.pendingLinks[agentID]
+	delete(r.pendingLinks, agentID)
+	return pending
+}
+
+// ReleaseDevice releases the link discovery context of the specified device and purges its agent ID bindings,
+// along with any pending links from or to the device
+func (r *LinkReconciler) ReleaseDevice(id topo.ID) {
+	r.linkDiscovery.ReleaseDevice(id)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for agentID, object := range r.agentDevices {
+		if object.ID == id {
+			delete(r.agentDevices, agentID)
+			delete(r.pendingLinks, agentID)
+			r.purgePendingIngressLinks(agentID)
+		}
+	}
+}
+
+// PendingLinkCount returns the number of links waiting for their egress device to be resolved
+func (r *LinkReconciler) PendingLinkCount() int {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	count := 0
+	for _, pending := range r.pendingLinks {
+		count += len(pending)
+	}
+	return count
+}
+
+// Removes the pending links reported by the specified ingress agent
+func (r *LinkReconciler) purgePendingIngressLinks(agentID string) {
+	for egressAgentID, pending := range r.pendingLinks {
+		retained := make([]*southbound.Link, 0, len(pending))
+		for _, link := range pending {
+			if link.IngressDevice != agentID {
+				retained = append(retained, link)
+			}
+		}
+		if len(retained) == 0 {
+			delete(r.pendingLinks, egressAgentID)
+		} else {
+			r.pendingLinks[egressAgentID] = retained
+		}
+	}
+}
+
+// AgentID returns the link agent ID bound to the specified device, or an empty string if there is none
+func (r *LinkReconciler) AgentID(id topo.ID) string {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	for agentID, object := range r.agentDevices {
+		if object.ID == id {
+			return agentID
+		}
+	}
+	return ""
+}
+
+// Device returns the device to which the specified link agent ID is bound, or nil if it is not known
+func (r *LinkReconciler) Device(agentID string) *topo.Object {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	return r.agentDevices[agentID]
+}
+
+// SetCablingPlan sets the expected cabling plan against which the links are checked; nil disables the checks
+func (r *LinkReconciler) SetCablingPlan(plan *inventory.CablingPlan) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.cablingPlan = plan
 }
 
 // LinkAdded handles link addition event
@@ -96,6 +307,16 @@ func (r *LinkReconciler) reconcileLink(link *southbound.Link, status string) {
 		return
 	}
 
+	if egressDevice == nil && r.sharingAgentIDs() {
+		// The egress device may be discovered by another instance
+		pending := r.resolveSharedAgent(link.EgressDevice)
+		if _, egressDevice = r.resolveDevices(link); egressDevice != nil {
+			for _, pendingLink := range pending {
+				r.reconcileLink(pendingLink, statusUp)
+			}
+		}
+	}
+
 	if egressDevice == nil {
 		// If the egress device is now yet resolved, add the link to its pending links
 		r.lock.Lock()
@@ -111,16 +332,75 @@ func (r *LinkReconciler) reconcileLink(link *southbound.Link, status string) {
 	log.Infof("Reconciling link %s", linkID)
 	log.Debugf("... using discovered link %+v", link)
 
+	// Check the link against the cabling plan
+	cabling, expected := r.checkCabling(egressPortID, ingressPortID)
+
 	// Try to get the link
 	gr, err := r.topoClient.Get(r.ctx, &topo.GetRequest{ID: linkID})
+	if err != nil || gr.Object.Labels[cablingLabel] != string(cabling) {
+		reportCabling(linkID, egressPortID, cabling, expected)
+	}
 	if err != nil {
 		// If it is not there, create it and its originates/terminates relations
-		r.createLink(linkID, egressPortID, ingressPortID, link, egressDevice.Labels)
+		labels := make(map[string]string, len(egressDevice.Labels)+1)
+		for k, v := range egressDevice.Labels {
+			labels[k] = v
+		}
+		setOrDeleteLabel(labels, cablingLabel, string(cabling))
+		r.dampener.observe(linkReconcilerName, linkID, status, egressPortID, ingressPortID)
+		if created := r.createLink(linkID, egressPortID, ingressPortID, link, labels); created != nil {
+			if status == statusUp {
+				r.publishLinkEvent(LinkUp, linkID, ingressDevice.ID, egressDevice.ID)
+			}
+			r.pairLink(created, egressPortID, ingressPortID, true)
+		}
 		return
 	}
 
-	// Otherwise, if it needs an update, update it
-	r.updateLinkIfNeeded(gr.Object, link, status)
+	// Otherwise, unless it is flapping, update it if needed
+	if r.dampener.observe(linkReconcilerName, linkID, status, egressPortID, ingressPortID) {
+		markFlapping(r.ctx, r.topoClient, linkReconcilerName, gr.Object, map[string]string{cablingLabel: string(cabling)}, cablingLabel)
+		return
+	}
+	changed := r.updateLinkIfNeeded(gr.Object, link, status, cabling, egressPortID, ingressPortID)
+	if changed {
+		eventType := LinkUp
+		if status == statusDown {
+			eventType = LinkDown
+		}
+		r.publishLinkEvent(eventType, linkID, ingressDevice.ID, egressDevice.ID)
+	}
+	r.pairLink(gr.Object, egressPortID, ingressPortID, changed)
+}
+
+// Emits a link event on behalf of the ingress device
+func (r *LinkReconciler) publishLinkEvent(eventType EventType, linkID topo.ID, ingressDeviceID topo.ID, egressDeviceID topo.ID) {
+	r.events.Publish(&Event{Type: eventType, ObjectID: linkID, DeviceID: ingressDeviceID, PeerDeviceID: egressDeviceID})
+}
+
+// Returns the cabling status of the link and its expected ingress port, or an empty status if there is no cabling plan
+func (r *LinkReconciler) checkCabling(egressPortID topo.ID, ingressPortID topo.ID) (inventory.CablingStatus, string) {
+	r.lock.RLock()
+	plan := r.cablingPlan
+	r.lock.RUnlock()
+	if plan == nil {
+		return "", ""
+	}
+	return plan.Check(string(egressPortID), string(ingressPortID))
+}
+
+// Logs a warning if the link does not match the cabling plan
+func reportCabling(linkID topo.ID, egressPortID topo.ID, cabling inventory.CablingStatus, expected string) {
+	switch cabling {
+	case inventory.CablingMiswired:
+		if len(expected) > 0 {
+			log.Warnf("Link %s is miswired; port %s is expected to connect to %s", linkID, egressPortID, expected)
+		} else {
+			log.Warnf("Link %s is miswired", linkID)
+		}
+	case inventory.CablingExtra:
+		log.Warnf("Link %s is not part of the cabling plan", linkID)
+	}
 }
 
 // Register the given report, the agent ID and return....
@@ -129,7 +409,7 @@ func (r *LinkReconciler) registerReport(object *topo.Object, report *southbound.
 	defer r.lock.Unlock()
 
 	// (Re)create the agent ID to device entity ID binding
-	r.agentDevices[report.AgentID] = object
+	r.bindAgent(report.AgentID, object)
 
 	// See if all links in the report can be processed, if not, register them in the pending links
 	// Otherwise, add them to the links to be processed now
@@ -171,53 +451,101 @@ func (r *LinkReconciler) resolveDevices(link *southbound.Link) (*topo.Object, *t
 	return r.agentDevices[link.IngressDevice], r.agentDevices[link.EgressDevice]
 }
 
-// Creates link topo object and its originates/terminates relations
+// Creates link topo object and its originates/terminates relations; returns the link entity as created, or nil if
+// the link was not created
 func (r *LinkReconciler) createLink(linkID topo.ID, egressPortID topo.ID, ingressPortID topo.ID,
-	link *southbound.Link, labels map[string]string) {
+	link *southbound.Link, labels map[string]string) *topo.Object {
 	linkAspect := &topo.Link{Status: "UP", LastChange: link.CreateTime}
 	object, err := topo.NewEntity(linkID, topo.LinkKind).WithAspects(linkAspect)
 	if err != nil {
 		log.Warnf("Unable to allocate link %s: %+v", linkID, err)
-		return
+		return nil
 	}
 	object.Labels = labels
-	if _, err = r.topoClient.Create(r.ctx, &topo.CreateRequest{Object: object}); err != nil {
+	resp, err := r.topoClient.Create(r.ctx, &topo.CreateRequest{Object: object})
+	if err != nil {
 		log.Warnf("Unable to create link %s: %+v", linkID, err)
-		return
+		return nil
 	}
 
 	originates := topo.NewRelation(egressPortID, linkID, topo.OriginatesKind)
 	if _, err = r.topoClient.Create(r.ctx, &topo.CreateRequest{Object: originates}); err != nil {
 		log.Warnf("Unable to create originates relation for link %s: %+v", linkID, err)
-		return
+		return nil
 	}
 
 	terminates := topo.NewRelation(ingressPortID, linkID, topo.TerminatesKind)
 	if _, err = r.topoClient.Create(r.ctx, &topo.CreateRequest{Object: terminates}); err != nil {
 		log.Warnf("Unable to create terminates relation for link %s: %+v", linkID, err)
-		return
+		return nil
 	}
 	log.Infof("Created link %s", linkID)
+	countOperation(linkReconcilerName, createOperation)
+	return resp.Object
 }
 
-// Updates link if the link aspect update time differs from the southbound link create time
-func (r *LinkReconciler) updateLinkIfNeeded(linkObject *topo.Object, link *southbound.Link, status string) {
+// Updates link if the link aspect update time differs from the southbound link create time, if it is to be restored
+// following its ports coming back up, or if its cabling status has changed; returns true if the link status was
+// changed
+func (r *LinkReconciler) updateLinkIfNeeded(linkObject *topo.Object, link *southbound.Link, status string,
+	cabling inventory.CablingStatus, egressPortID topo.ID, ingressPortID topo.ID) bool {
 	linkAspect := &topo.Link{}
-	if err := linkObject.GetAspect(linkAspect); err != nil || linkAspect.LastChange < link.CreateTime {
+	statusChanged := false
+	updated := false
+	labels := map[string]string{cablingLabel: string(cabling)}
+	keys := []string{cablingLabel, flappingLabel}
+	if err := linkObject.GetAspect(linkAspect); err != nil || linkAspect.LastChange < link.CreateTime ||
+		r.portsRestored(linkObject, status, egressPortID, ingressPortID) {
+		statusChanged = linkAspect.Status != status
 		linkAspect.Status = status
-		linkAspect.LastChange = link.CreateTime
+		if link.CreateTime > linkAspect.LastChange {
+			linkAspect.LastChange = link.CreateTime
+		} else {
+			linkAspect.LastChange = uint64(time.Now().UnixNano())
+		}
+
+		// The status is now as reported by the link agent
+		keys = append(keys, portDownLabel)
 
 		if err = linkObject.SetAspect(linkAspect); err != nil {
 			log.Warnf("Unable to set link %s aspect %+v: %+v", linkObject.ID, linkAspect, err)
-			return
+			return false
 		}
+		updated = true
+	}
+	labelsChanged := syncLabels(linkObject, labels, keys...)
 
-		if _, err = r.topoClient.Update(r.ctx, &topo.UpdateRequest{Object: linkObject}); err != nil {
+	if updated || labelsChanged {
+		resp, err := r.topoClient.Update(r.ctx, &topo.UpdateRequest{Object: linkObject})
+		if err != nil {
 			log.Warnf("Unable to update link %s with %+v: %+v", linkObject.ID, linkAspect, err)
-			return
+			return false
 		}
-		log.Infof("Updated status of link %s: %+v", linkObject.ID, linkAspect)
+		linkObject.Revision = resp.Object.Revision
+		log.Infof("Updated status of link %s: %+v; cabling: %s", linkObject.ID, linkAspect, cabling)
+		countOperation(linkReconcilerName, updateOperation)
 	}
+	return statusChanged
+}
+
+// Returns true if the link, having been taken DOWN because one of its ports went down, is reported UP again while
+// both of its ports are up
+func (r *LinkReconciler) portsRestored(linkObject *topo.Object, status string, egressPortID topo.ID, ingressPortID topo.ID) bool {
+	if status != statusUp || linkObject.Labels[portDownLabel] != "true" {
+		return false
+	}
+	for _, portID := range []topo.ID{egressPortID, ingressPortID} {
+		resp, err := r.topoClient.Get(r.ctx, &topo.GetRequest{ID: portID})
+		if err != nil {
+			log.Warnf("Unable to get port %s of link %s: %+v", portID, linkObject.ID, err)
+			return false
+		}
+		portAspect := &topo.Port{}
+		if err = resp.Object.GetAspect(portAspect); err != nil || portAspect.Status != statusUp {
+			return false
+		}
+	}
+	return true
 }
 
 // Updates any topology link entities to down state if they don't have a counterpart in the southbound links report
@@ -256,11 +584,11 @@ func (r *LinkReconciler) updateDownedLinks(object *topo.Object, report *southbou
 		}
 
 		// Otherwise, get the link that terminates at this port and mark it as DOWN
-		r.updateDownedIngressLink(resp.Object)
+		r.updateDownedIngressLink(object, resp.Object)
 	}
 }
 
-func (r *LinkReconciler) updateDownedIngressLink(portObject *topo.Object) {
+func (r *LinkReconciler) updateDownedIngressLink(object *topo.Object, portObject *topo.Object) {
 	linkFilter := &topo.RelationFilter{SrcId: string(portObject.ID), RelationKind: topo.TerminatesKind, TargetKind: topo.LinkKind}
 	stream, err := r.topoClient.Query(r.ctx, &topo.QueryRequest{Filters: &topo.Filters{RelationFilter: linkFilter}})
 	if err != nil {
@@ -276,24 +604,77 @@ func (r *LinkReconciler) updateDownedIngressLink(portObject *topo.Object) {
 		return
 	}
 
+	r.markLinkDown(object, resp.Object, portObject.ID, false, "")
+}
+
+// MarkPortLinksDown marks the links originating or terminating at the port of the device as DOWN right away,
+// rather than waiting for the link agent to notice; the links come back UP once the link agent reports them while
+// both of their ports are up
+func (r *LinkReconciler) MarkPortLinksDown(object *topo.Object, portObject *topo.Object) {
+	for _, kind := range []string{topo.OriginatesKind, topo.TerminatesKind} {
+		linkFilter := &topo.RelationFilter{SrcId: string(portObject.ID), RelationKind: kind, TargetKind: topo.LinkKind}
+		stream, err := r.topoClient.Query(r.ctx, &topo.QueryRequest{Filters: &topo.Filters{RelationFilter: linkFilter}})
+		if err != nil {
+			log.Warnf("Unable to query links of port %s: %+v", portObject.ID, err)
+			return
+		}
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					log.Warnf("Unable to read links of port %s: %+v", portObject.ID, err)
+				}
+				break
+			}
+			r.markLinkDown(object, resp.Object, portObject.ID, true, fmt.Sprintf("port %s is down", portObject.ID))
+		}
+	}
+}
+
+// Marks the link at the given port as DOWN, unless it is already down or flapping; links taken down because the
+// port went down are labeled as such
+func (r *LinkReconciler) markLinkDown(object *topo.Object, link *topo.Object, portID topo.ID, portDown bool, message string) {
 	linkAspect := &topo.Link{}
-	if err = resp.Object.GetAspect(linkAspect); err != nil {
-		log.Warnf("Unable to get ingress link aspect for %s: %v", resp.Object.ID, err)
+	if err := link.GetAspect(linkAspect); err != nil {
+		log.Warnf("Unable to get link aspect for %s: %v", link.ID, err)
 		return
 	}
 
+	if r.dampener.observe(linkReconcilerName, link.ID, statusDown, portID) {
+		markFlapping(r.ctx, r.topoClient, linkReconcilerName, link, nil)
+		return
+	}
 	if linkAspect.Status != "DOWN" {
 		// If the link is not already marked as down, mark it as such
 		linkAspect = &topo.Link{Status: "DOWN", LastChange: uint64(time.Now().UnixNano())}
-		if err = resp.Object.SetAspect(linkAspect); err != nil {
-			log.Warnf("Unable to set ingress link aspect for %s: %v", resp.Object.ID, err)
+		if err := link.SetAspect(linkAspect); err != nil {
+			log.Warnf("Unable to set link aspect for %s: %v", link.ID, err)
 			return
 		}
+		labels := make(map[string]string)
+		if portDown {
+			labels[portDownLabel] = "true"
+		}
+		syncLabels(link, labels, portDownLabel)
 
-		if _, err = r.topoClient.Update(r.ctx, &topo.UpdateRequest{Object: resp.Object}); err != nil {
-			log.Warnf("Unable to update ingress link aspect for %s: %v", resp.Object.ID, err)
+		resp, err := r.topoClient.Update(r.ctx, &topo.UpdateRequest{Object: link})
+		if err != nil {
+			log.Warnf("Unable to update link aspect for %s: %v", link.ID, err)
 			return
 		}
-		log.Infof("Updated status of link %s: %+v", resp.Object.ID, linkAspect)
+		link.Revision = resp.Object.Revision
+		log.Infof("Updated status of link %s: %+v", link.ID, linkAspect)
+		countOperation(linkReconcilerName, updateOperation)
+		r.events.Publish(&Event{Type: LinkDown, ObjectID: link.ID, DeviceID: object.ID, Message: message})
+
+		// The counterpart is now up alone, so it has to be tracked for asymmetry
+		egressPortID, ingressPortID, err := r.linkPorts(link)
+		if err != nil {
+			log.Warnf("Unable to get ports of link %s: %+v", link.ID, err)
+			return
+		}
+		if len(egressPortID) > 0 && len(ingressPortID) > 0 {
+			r.pairLink(link, egressPortID, ingressPortID, true)
+		}
 	}
 }
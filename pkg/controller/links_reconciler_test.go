@@ -7,9 +7,11 @@ package controller
 import (
 	"context"
 	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
 	"github.com/onosproject/topo-discovery/pkg/southbound"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 )
 
 func TestAddToPending(t *testing.T) {
@@ -65,3 +67,88 @@ func TestRegisterReport(t *testing.T) {
 	assert.Len(t, r.pendingLinks, 0)
 	assert.Len(t, r.agentDevices, 3)
 }
+
+func TestCablingLabel(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	r := c.linkReconciler
+	ta := topo.NewEntity(topo.ID("ta"), topo.SwitchKind)
+	ta.Labels = map[string]string{"pod": "pod1"}
+	tb := topo.NewEntity(topo.ID("tb"), topo.SwitchKind)
+	r.registerReport(ta, &southbound.LinkReport{AgentID: "a"})
+	r.registerReport(tb, &southbound.LinkReport{AgentID: "b"})
+
+	// Without a cabling plan, links are not labeled
+	r.reconcileLink(&southbound.Link{IngressDevice: "a", IngressPort: 1, EgressDevice: "b", EgressPort: 1, CreateTime: 1}, statusUp)
+	link, _ := c.getObject(context.TODO(), "tb/1-ta/1")
+	_, ok := link.Labels[cablingLabel]
+	assert.False(t, ok)
+	assert.Equal(t, "pod1", ta.Labels["pod"])
+	assert.Len(t, ta.Labels, 1)
+
+	plan, err := inventory.NewCablingPlan(&inventory.Cable{Source: "tb/1", Target: "ta/2"})
+	assert.NoError(t, err)
+	c.SetCablingPlan(plan)
+	r.reconcileLink(&southbound.Link{IngressDevice: "a", IngressPort: 1, EgressDevice: "b", EgressPort: 1, CreateTime: 1}, statusUp)
+	link, _ = c.getObject(context.TODO(), "tb/1-ta/1")
+	assert.Equal(t, string(inventory.CablingMiswired), link.Labels[cablingLabel])
+
+	r.reconcileLink(&southbound.Link{IngressDevice: "b", IngressPort: 1, EgressDevice: "a", EgressPort: 2, CreateTime: 1}, statusUp)
+	link, _ = c.getObject(context.TODO(), "ta/2-tb/1")
+	assert.Equal(t, string(inventory.CablingOK), link.Labels[cablingLabel])
+	assert.Equal(t, "pod1", link.Labels["pod"])
+}
+
+func TestPortDownMarksLinksDown(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	cfg := DefaultConfig()
+	cfg.FlapPenalty = 0
+	c.dampener.configure(cfg)
+	r := c.linkReconciler
+	events := c.events.Watch(context.Background(), &EventFilter{Types: []EventType{LinkUp, LinkDown, PortUpdated}})
+
+	leaf1 := topo.NewEntity("leaf1", topo.SwitchKind)
+	spine1 := topo.NewEntity("spine1", topo.SwitchKind)
+	c.portReconciler.createPort(leaf1, "leaf1/1", &topo.Port{Number: 1, Status: statusUp}, nil)
+	c.portReconciler.createPort(spine1, "spine1/1", &topo.Port{Number: 1, Status: statusUp}, nil)
+	r.registerReport(leaf1, &southbound.LinkReport{AgentID: "l1"})
+	r.registerReport(spine1, &southbound.LinkReport{AgentID: "s1"})
+	created := uint64(time.Now().UnixNano())
+	r.reconcileLink(&southbound.Link{IngressDevice: "l1", IngressPort: 1, EgressDevice: "s1", EgressPort: 1, CreateTime: created}, statusUp)
+	r.reconcileLink(&southbound.Link{IngressDevice: "s1", IngressPort: 1, EgressDevice: "l1", EgressPort: 1, CreateTime: created}, statusUp)
+	<-events
+	<-events
+	status := func(id topo.ID) string {
+		return linkStatus(getTestObject(t, f, id))
+	}
+
+	// The port going down takes both of its links down before the port change is announced
+	c.portReconciler.updatePortIfNeeded(leaf1, getTestObject(t, f, "leaf1/1"), &topo.Port{Number: 1, Status: statusDown}, nil)
+	assert.Equal(t, statusDown, status("spine1/1-leaf1/1"))
+	assert.Equal(t, statusDown, status("leaf1/1-spine1/1"))
+	assert.Equal(t, LinkDown, (<-events).Type)
+	assert.Equal(t, LinkDown, (<-events).Type)
+	assert.Equal(t, PortUpdated, (<-events).Type)
+
+	assert.Equal(t, "true", getTestObject(t, f, "spine1/1-leaf1/1").Labels[portDownLabel])
+
+	// The links stay down while the port is down, even if the link agent still reports them
+	r.reconcileLink(&southbound.Link{IngressDevice: "l1", IngressPort: 1, EgressDevice: "s1", EgressPort: 1, CreateTime: created}, statusUp)
+	assert.Equal(t, statusDown, status("spine1/1-leaf1/1"))
+
+	// The port coming back up does not bring the links up; the link agent has to report them first
+	c.portReconciler.updatePortIfNeeded(leaf1, getTestObject(t, f, "leaf1/1"), &topo.Port{Number: 1, Status: statusUp}, nil)
+	assert.Equal(t, PortUpdated, (<-events).Type)
+	assert.Equal(t, statusDown, status("spine1/1-leaf1/1"))
+
+	// Once reported again, the links are restored, even though the link agent still reports them as created before
+	r.reconcileLink(&southbound.Link{IngressDevice: "l1", IngressPort: 1, EgressDevice: "s1", EgressPort: 1, CreateTime: created}, statusUp)
+	assert.Equal(t, statusUp, status("spine1/1-leaf1/1"))
+	assert.Empty(t, getTestObject(t, f, "spine1/1-leaf1/1").Labels[portDownLabel])
+	assert.Equal(t, LinkUp, (<-events).Type)
+	r.reconcileLink(&southbound.Link{IngressDevice: "s1", IngressPort: 1, EgressDevice: "l1", EgressPort: 1, CreateTime: created}, statusUp)
+	assert.Equal(t, statusUp, status("leaf1/1-spine1/1"))
+	assert.Equal(t, LinkUp, (<-events).Type)
+	assert.Len(t, events, 0)
+}
@@ -0,0 +1,69 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"github.com/onosproject/topo-discovery/pkg/metrics"
+)
+
+const (
+	portReconcilerName = "port"
+	linkReconcilerName = "link"
+	hostReconcilerName = "host"
+
+	createOperation = "create"
+	updateOperation = "update"
+	deleteOperation = "delete"
+
+	realmQueueName    = "realm"
+	neighborQueueName = "neighbor"
+)
+
+var (
+	sweepsTotal = metrics.NewCounter("topo_discovery_sweeps_total",
+		"Number of full discovery sweeps, by result", "result")
+	sweepDuration = metrics.NewHistogram("topo_discovery_sweep_duration_seconds",
+		"Duration of full discovery sweeps", metrics.DefaultBuckets)
+	reconcilerOperations = metrics.NewCounter("topo_discovery_reconciler_operations_total",
+		"Number of topology objects created, updated or deleted by the reconcilers", "reconciler", "operation")
+	coalesced = metrics.NewCounter("topo_discovery_coalesced_total",
+		"Number of jobs coalesced with pending or ongoing work on the same object", "queue")
+)
+
+// Counts a topology object operation made by the given reconciler
+func countOperation(reconciler string, operation string) {
+	reconcilerOperations.Inc(reconciler, operation)
+}
+
+// Registers the gauges reflecting the state of the controller queues and of the link reconciler
+func (c *Controller) registerMetrics() {
+	metrics.NewGaugeFunc("topo_discovery_realm_queue_depth", "Number of realm objects waiting for discovery",
+		func() float64 { return float64(c.queueDepth(realmQueueName)) })
+	metrics.NewGaugeFunc("topo_discovery_neighbor_realm_queue_depth", "Number of neighbor realm objects waiting for agent registration",
+		func() float64 { return float64(c.queueDepth(neighborQueueName)) })
+	metrics.NewGaugeFunc("topo_discovery_pending_links", "Number of discovered links waiting for their egress device to be resolved",
+		func() float64 { return float64(c.pendingLinkCount()) })
+}
+
+// Returns the number of objects waiting in the given queue
+func (c *Controller) queueDepth(queue string) int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	if queue == realmQueueName {
+		return c.realmQueue.len()
+	}
+	return c.neighborRealmQueue.len()
+}
+
+// Returns the number of links pending resolution of their egress device
+func (c *Controller) pendingLinkCount() int {
+	c.lock.RLock()
+	linkReconciler := c.linkReconciler
+	c.lock.RUnlock()
+	if linkReconciler == nil {
+		return 0
+	}
+	return linkReconciler.PendingLinkCount()
+}
@@ -0,0 +1,50 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	api "github.com/onosproject/onos-api/go/onos/discovery"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/metrics"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestMetrics(t *testing.T) {
+	f := newFakeTopo()
+	sb := newFakeSouthbound()
+	c := newRunningTestController(f, sb)
+	defer c.realmQueue.shutDown()
+	c.registerMetrics()
+	ctx := context.TODO()
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+	assert.NoError(t, c.AddSwitch(ctx, &api.AddSwitchRequest{ID: "leaf1", PodID: "pod1", RackID: "rack1",
+		ManagementInfo: &api.ManagementInfo{GNMIEndpoint: "leaf1:9339", Realm: "pod1"}}))
+	sb.ports["leaf1"] = map[string]*topo.Port{"1": {Number: 1, Status: statusUp}, "2": {Number: 2, Status: statusUp}}
+
+	created := reconcilerOperations.Value(portReconcilerName, createOperation)
+	sweeps := sweepsTotal.Value("ok")
+	observations := sweepDuration.Count()
+	assert.NoError(t, c.runFullDiscoverySweep())
+	assert.Equal(t, sweeps+1, sweepsTotal.Value("ok"))
+	assert.Equal(t, observations+1, sweepDuration.Count())
+
+	results, err := c.Rediscover(ctx, "leaf1", false)
+	assert.NoError(t, err)
+	for range results {
+	}
+	assert.Equal(t, created+2, reconcilerOperations.Value(portReconcilerName, createOperation))
+
+	c.linkReconciler.addToPendingLinks(&southbound.Link{IngressDevice: "leaf1", EgressDevice: "spine1"})
+	buf := &bytes.Buffer{}
+	assert.NoError(t, metrics.DefaultRegistry.WriteText(buf))
+	assert.Contains(t, buf.String(), "\ntopo_discovery_pending_links 1\n")
+	assert.Contains(t, buf.String(), "\n# TYPE topo_discovery_realm_queue_depth gauge\n")
+	assert.Contains(t, buf.String(), "\n# TYPE topo_discovery_coalesced_total counter\n")
+}
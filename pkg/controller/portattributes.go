@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"time"
+)
+
+const (
+	// Aspect of port entities carrying the port attributes which do not fit the onos.topo.Port aspect
+	portAttributesAspect = "onos.discovery.PortAttributes"
+
+	// Aspect of port entities carrying the interface counters of the port along with when they were collected
+	portStatisticsAspect = "onos.discovery.PortStatistics"
+
+	// Time after which the statistics of a port are recorded again, even if its counters have not changed
+	portStatisticsRefreshInterval = 10 * time.Minute
+)
+
+// PortStatistics describes the interface counters of a port as of when they were last collected
+type PortStatistics struct {
+	southbound.PortCounters
+	Updated time.Time `json:"updated"`
+}
+
+// Syncs the attributes aspect of the port entity with the southbound port attributes; returns true if it changed.
+// Missing attributes leave the aspect as is.
+func syncPortAttributes(object *topo.Object, attrs *southbound.PortAttributes) (bool, error) {
+	if attrs == nil {
+		return false, nil
+	}
+	value, err := json.Marshal(attrs)
+	if err != nil {
+		return false, err
+	}
+	if bytes.Equal(value, object.GetAspectBytes(portAttributesAspect)) {
+		return false, nil
+	}
+	object.SetAspectBytes(portAttributesAspect, value)
+	return true, nil
+}
+
+// Queues the collection of the interface counters of the devices owned by this instance whose ports have been
+// discovered, so that the discovery workers collect them
+func (c *Controller) collectCounters() {
+	for _, id := range c.portReconciler.discoveredDevices() {
+		if c.owns(id) {
+			c.realmQueue.add(&discoveryJob{object: &topo.Object{ID: id}, counters: true})
+		}
+	}
+}
+
+// CollectCounters collects the interface counters of the ports of the specified device, provided its ports have
+// been discovered, and records them in the statistics aspect of their port entities. The statistics are recorded
+// only when the counters change, and otherwise refreshed once the refresh interval passes. Returns an error if the
+// counters could not be collected.
+func (r *PortReconciler) CollectCounters(id topo.ID) error {
+	r.lock.Lock()
+	discovered := r.discovered[id]
+	r.lock.Unlock()
+	if !discovered {
+		return nil
+	}
+
+	counters, err := r.portDiscovery.GetPortCounters(id)
+	if err != nil {
+		log.Warnf("Unable to get port counters of device %s: %+v", id, err)
+		return err
+	}
+	now := time.Now()
+	for number, c := range counters {
+		portID := topo.ID(fmt.Sprintf("%s/%d", id, number))
+		r.lock.Lock()
+		recorded, ok := r.statistics[portID]
+		r.lock.Unlock()
+		if ok && recorded.PortCounters == *c && now.Sub(recorded.Updated) < portStatisticsRefreshInterval {
+			continue
+		}
+
+		statistics := &PortStatistics{PortCounters: *c, Updated: now}
+		if r.updatePortStatistics(portID, statistics) {
+			r.lock.Lock()
+			r.statistics[portID] = statistics
+			r.lock.Unlock()
+		}
+	}
+	return nil
+}
+
+// Returns the IDs of the devices whose ports have been discovered
+func (r *PortReconciler) discoveredDevices() []topo.ID {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	devices := make([]topo.ID, 0, len(r.discovered))
+	for id := range r.discovered {
+		devices = append(devices, id)
+	}
+	return devices
+}
+
+// Records the statistics in the statistics aspect of the port entity; returns true if they were recorded
+func (r *PortReconciler) updatePortStatistics(portID topo.ID, statistics *PortStatistics) bool {
+	resp, err := r.topoClient.Get(r.ctx, &topo.GetRequest{ID: portID})
+	if err != nil {
+		log.Warnf("Unable to get port %s: %+v", portID, err)
+		return false
+	}
+	value, err := json.Marshal(statistics)
+	if err != nil {
+		log.Warnf("Unable to encode statistics of port %s: %+v", portID, err)
+		return false
+	}
+	resp.Object.SetAspectBytes(portStatisticsAspect, value)
+	if _, err = r.topoClient.Update(r.ctx, &topo.UpdateRequest{Object: resp.Object}); err != nil {
+		log.Warnf("Unable to update statistics of port %s: %+v", portID, err)
+		return false
+	}
+	countOperation(portReconcilerName, updateOperation)
+	return true
+}
@@ -0,0 +1,83 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"encoding/json"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestPortAttributes(t *testing.T) {
+	f := newFakeTopo()
+	c := newTestController(f)
+	sb := newFakeSouthbound()
+	r := c.portReconciler
+	r.portDiscovery = sb
+	leaf1 := topo.NewEntity("leaf1", topo.SwitchKind)
+	sb.ports["leaf1"] = map[string]*topo.Port{"1": {Number: 1, Status: statusUp}}
+	sb.attrs["leaf1"] = map[string]*southbound.PortAttributes{"1": {MTU: 1500, MAC: "00:00:00:00:00:01",
+		Description: "to spine1", AdminStatus: statusUp, NegotiatedSpeed: "SPEED_100GB", Duplex: "FULL"}}
+
+	attributes := func() *southbound.PortAttributes {
+		attrs := &southbound.PortAttributes{}
+		assert.NoError(t, json.Unmarshal(getTestObject(t, f, "leaf1/1").GetAspectBytes(portAttributesAspect), attrs))
+		return attrs
+	}
+
+	// Attributes are recorded when the port is created and updated only when they change
+	assert.NoError(t, r.DiscoverPorts(leaf1))
+	assert.Equal(t, southbound.PortAttributes{MTU: 1500, MAC: "00:00:00:00:00:01", Description: "to spine1",
+		AdminStatus: statusUp, NegotiatedSpeed: "SPEED_100GB", Duplex: "FULL"}, *attributes())
+	updates := f.updates
+	assert.NoError(t, r.DiscoverPorts(leaf1))
+	assert.Equal(t, updates, f.updates)
+	sb.attrs["leaf1"]["1"].MTU = 9000
+	assert.NoError(t, r.DiscoverPorts(leaf1))
+	assert.Equal(t, uint32(9000), attributes().MTU)
+	assert.Equal(t, updates+1, f.updates)
+
+	// Status updates alone leave the attributes be
+	r.HandlePortStatus(leaf1, &topo.Port{Number: 1, Status: statusDown})
+	assert.Equal(t, uint32(9000), attributes().MTU)
+
+	// Counters are recorded for the ports of discovered devices
+	sb.counters["leaf1"] = map[uint32]*southbound.PortCounters{1: {InOctets: 1024, OutOctets: 2048, InErrors: 1}}
+	r.CollectCounters("leaf1")
+	statistics := &PortStatistics{}
+	assert.NoError(t, json.Unmarshal(getTestObject(t, f, "leaf1/1").GetAspectBytes(portStatisticsAspect), statistics))
+	assert.Equal(t, uint64(1024), statistics.InOctets)
+	assert.Equal(t, uint64(2048), statistics.OutOctets)
+	assert.Equal(t, uint64(1), statistics.InErrors)
+	assert.False(t, statistics.Updated.IsZero())
+	assert.Equal(t, uint32(9000), attributes().MTU)
+
+	// Unchanged counters are not recorded again until the refresh interval passes
+	updates = f.updates
+	r.CollectCounters("leaf1")
+	assert.Equal(t, updates, f.updates)
+	r.lock.Lock()
+	r.statistics["leaf1/1"].Updated = time.Now().Add(-portStatisticsRefreshInterval)
+	r.lock.Unlock()
+	r.CollectCounters("leaf1")
+	assert.Equal(t, updates+1, f.updates)
+	sb.counters["leaf1"][1].InOctets = 4096
+	r.CollectCounters("leaf1")
+	assert.Equal(t, updates+2, f.updates)
+
+	// Unreachable and released devices are skipped
+	updates = f.updates
+	sb.failing["leaf1"] = errors.NewUnavailable("unreachable")
+	r.CollectCounters("leaf1")
+	assert.Equal(t, updates, f.updates)
+	delete(sb.failing, "leaf1")
+	r.ReleaseDevice("leaf1")
+	r.CollectCounters("leaf1")
+	assert.Equal(t, updates, f.updates)
+}
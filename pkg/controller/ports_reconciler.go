@@ -10,6 +10,7 @@ import (
 	"github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/topo-discovery/pkg/southbound"
 	"io"
+	"sync"
 )
 
 // PortReconciler provides state and context required for port discovery and reconciliation
@@ -18,6 +19,21 @@ type PortReconciler struct {
 	portDiscovery southbound.PortDiscovery
 	topoClient    topo.TopoClient
 	ctx           context.Context
+	events        *EventBroker
+
+	// Devices which could not be reached during their last discovery, and those whose ports have been discovered
+	lock        sync.Mutex
+	unreachable map[topo.ID]bool
+	discovered  map[topo.ID]bool
+
+	// Statistics last recorded for each port
+	statistics map[topo.ID]*PortStatistics
+
+	// Dampens status changes of flapping ports
+	dampener *dampener
+
+	// Link reconciler notified of ports going down, so that their links are marked down along with them
+	linkReconciler *LinkReconciler
 }
 
 // NewPortReconciler creates a new port reconciler context
@@ -26,36 +42,45 @@ func NewPortReconciler(ctx context.Context, topoClient topo.TopoClient) *PortRec
 		topoClient:    topoClient,
 		ctx:           ctx,
 		portDiscovery: southbound.NewGNMIPortDiscovery(),
+		unreachable:   make(map[topo.ID]bool),
+		discovered:    make(map[topo.ID]bool),
+		statistics:    make(map[topo.ID]*PortStatistics),
 	}
 }
 
-// DiscoverPorts discovers ports and reconciles their topology entity counterparts
-func (r *PortReconciler) DiscoverPorts(object *topo.Object) {
+// DiscoverPorts discovers ports and reconciles their topology entity counterparts; returns an error if the ports
+// could not be discovered
+func (r *PortReconciler) DiscoverPorts(object *topo.Object) error {
 	// Connect to the gNMI server and get list of ports
 	devicePorts, err := r.portDiscovery.GetPorts(object, r)
+	r.updateReachability(object, err)
 	if err != nil {
 		log.Warnf("Unable to get ports from device %s", object.ID)
-		return
+		return err
 	}
+	r.lock.Lock()
+	r.discovered[object.ID] = true
+	r.lock.Unlock()
 
 	// Get device port entities from topology
 	topoPorts, err := r.getPorts(object)
 	if err != nil {
 		log.Warnf("Unable to get existing ports for device %s", object.ID)
-		return
+		return err
 	}
 
 	// For each gNMI port
+	attributes := r.portDiscovery.GetPortAttributes(object.ID)
 	usedPortIDs := make(map[topo.ID]topo.ID, 0)
-	for _, p := range devicePorts {
+	for name, p := range devicePorts {
 		portID := topo.ID(fmt.Sprintf("%s/%d", object.ID, p.Number))
 		topoPort, ok := topoPorts[portID]
 		if !ok {
 			// port object not found, create one with port aspect and a switch->port 'has' relation
-			r.createPort(object, portID, p)
+			r.createPort(object, portID, p, attributes[name])
 		} else {
 			// update port object with port aspect if needed
-			r.updatePortIfNeeded(topoPort, p)
+			r.updatePortIfNeeded(object, topoPort, p, attributes[name])
 		}
 		usedPortIDs[portID] = portID
 	}
@@ -63,11 +88,48 @@ func (r *PortReconciler) DiscoverPorts(object *topo.Object) {
 	// Remove any ports if needed
 	for _, port := range topoPorts {
 		if _, ok := usedPortIDs[port.ID]; !ok {
-			r.deletePort(port.ID)
+			r.deletePort(object, port.ID)
+		}
+	}
+	return nil
+}
+
+// ReleaseDevice releases the port discovery context of the specified device
+func (r *PortReconciler) ReleaseDevice(id topo.ID) {
+	r.portDiscovery.ReleaseDevice(id)
+
+	r.dampener.forget(id)
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.unreachable, id)
+	delete(r.discovered, id)
+	for portID := range r.statistics {
+		if portDeviceID(portID) == id {
+			delete(r.statistics, portID)
 		}
 	}
 }
 
+// Tracks whether the Stratum device is reachable and emits an event when it becomes unreachable
+func (r *PortReconciler) updateReachability(object *topo.Object, err error) {
+	if object.GetAspect(&topo.StratumAgents{}) != nil {
+		return // Not a Stratum device
+	}
+	r.lock.Lock()
+	wasUnreachable := r.unreachable[object.ID]
+	if err != nil {
+		r.unreachable[object.ID] = true
+	} else {
+		delete(r.unreachable, object.ID)
+	}
+	r.lock.Unlock()
+
+	if err != nil && !wasUnreachable {
+		r.events.Publish(&Event{Type: DeviceUnreachable, ObjectID: object.ID, DeviceID: object.ID, Message: err.Error()})
+	}
+}
+
 // HandlePortStatus handles port status change
 func (r *PortReconciler) HandlePortStatus(object *topo.Object, port *topo.Port) {
 	log.Infof("Updating port status for %s/%s to %s", object.ID, port.DisplayName, port.Status)
@@ -79,7 +141,7 @@ func (r *PortReconciler) HandlePortStatus(object *topo.Object, port *topo.Port)
 		log.Warnf("Unable to get port %s of device %s: %+v", portID, object.ID, err)
 		return
 	}
-	r.updatePortIfNeeded(resp.Object, port)
+	r.updatePortIfNeeded(object, resp.Object, port, nil)
 }
 
 func (r *PortReconciler) getPorts(object *topo.Object) (map[topo.ID]*topo.Object, error) {
@@ -108,13 +170,17 @@ func (r *PortReconciler) getPorts(object *topo.Object) (map[topo.ID]*topo.Object
 	}
 }
 
-func (r *PortReconciler) createPort(object *topo.Object, portID topo.ID, port *topo.Port) {
+func (r *PortReconciler) createPort(object *topo.Object, portID topo.ID, port *topo.Port,
+	attrs *southbound.PortAttributes) {
 	portObject, err := topo.NewEntity(portID, topo.PortKind).WithAspects(port)
 	portObject.Labels = object.Labels // Copy the parent device labels
 	if err != nil {
 		log.Warnf("Unable to allocate port entity %s: %+v", portID, err)
 		return
 	}
+	if _, err = syncPortAttributes(portObject, attrs); err != nil {
+		log.Warnf("Unable to set attributes of port entity %s: %+v", portID, err)
+	}
 	if _, err = r.topoClient.Create(r.ctx, &topo.CreateRequest{Object: portObject}); err != nil {
 		log.Warnf("Unable to create port entity %s: %+v", portID, err)
 		return
@@ -124,35 +190,66 @@ func (r *PortReconciler) createPort(object *topo.Object, portID topo.ID, port *t
 		log.Warnf("Unable to create switch-port relation %s: %+v", hasRelation.ID, err)
 		return
 	}
+	r.dampener.observe(portReconcilerName, portID, port.Status, portID)
 	log.Infof("Created port %s: %+v", portID, port)
+	countOperation(portReconcilerName, createOperation)
+	r.events.Publish(&Event{Type: PortCreated, ObjectID: portID, DeviceID: object.ID, Message: port.Status})
 }
 
-func (r *PortReconciler) updatePortIfNeeded(topoPort *topo.Object, port *topo.Port) {
+// Updates the port entity if its port aspect or attributes changed; nil attributes leave those as they are
+func (r *PortReconciler) updatePortIfNeeded(object *topo.Object, topoPort *topo.Object, port *topo.Port,
+	attrs *southbound.PortAttributes) {
 	topoPortAspect := &topo.Port{}
 	if err := topoPort.GetAspect(topoPortAspect); err != nil {
 		log.Warnf("Unable to get port aspect for %s: %+v", topoPort.ID, err)
 		return
 	}
 
-	if portStateChanged(topoPortAspect, port) {
-		if err := topoPort.SetAspect(port); err != nil {
-			log.Warnf("Unable to update port aspect for %s: %+v", topoPort.ID, err)
-			return
-		}
-		if _, err := r.topoClient.Update(r.ctx, &topo.UpdateRequest{Object: topoPort}); err != nil {
-			log.Warnf("Unable to update port entity %s: %+v", topoPort.ID, err)
-			return
-		}
-		log.Infof("Updated port %s: %+v", topoPort.ID, port)
+	// Leave flapping ports be until they settle down
+	if r.dampener.observe(portReconcilerName, topoPort.ID, port.Status, topoPort.ID) {
+		markFlapping(r.ctx, r.topoClient, portReconcilerName, topoPort, nil)
+		return
+	}
+
+	changed := portStateChanged(topoPortAspect, port)
+	attrsChanged, err := syncPortAttributes(topoPort, attrs)
+	if err != nil {
+		log.Warnf("Unable to update attributes of port %s: %+v", topoPort.ID, err)
+	}
+	if flappingCleared := syncLabels(topoPort, nil, flappingLabel); !changed && !attrsChanged && !flappingCleared {
+		return
+	}
+	if err := topoPort.SetAspect(port); err != nil {
+		log.Warnf("Unable to update port aspect for %s: %+v", topoPort.ID, err)
+		return
+	}
+	if _, err := r.topoClient.Update(r.ctx, &topo.UpdateRequest{Object: topoPort}); err != nil {
+		log.Warnf("Unable to update port entity %s: %+v", topoPort.ID, err)
+		return
+	}
+	log.Infof("Updated port %s: %+v", topoPort.ID, port)
+	countOperation(portReconcilerName, updateOperation)
+
+	// Links cannot be up while their ports are not; update them before announcing the port change
+	if port.Status != topoPortAspect.Status && port.Status != statusUp && r.linkReconciler != nil {
+		r.linkReconciler.MarkPortLinksDown(object, topoPort)
+	}
+	if changed {
+		r.events.Publish(&Event{Type: PortUpdated, ObjectID: topoPort.ID, DeviceID: object.ID, Message: port.Status})
 	}
 }
 
-func (r *PortReconciler) deletePort(portID topo.ID) {
+func (r *PortReconciler) deletePort(object *topo.Object, portID topo.ID) {
 	if _, err := r.topoClient.Delete(r.ctx, &topo.DeleteRequest{ID: portID}); err != nil {
 		log.Warnf("Unable to delete port entity %s: %+v", portID, err)
 		return
 	}
+	r.lock.Lock()
+	delete(r.statistics, portID)
+	r.lock.Unlock()
 	log.Infof("Deleted port %s", portID)
+	countOperation(portReconcilerName, deleteOperation)
+	r.events.Publish(&Event{Type: PortDeleted, ObjectID: portID, DeviceID: object.ID})
 }
 
 func portStateChanged(a *topo.Port, b *topo.Port) bool {
@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"io"
+	"time"
+)
+
+// Phase identifies one of the discovery passes run for a device
+type Phase string
+
+const (
+	// PortsPhase is the port discovery pass
+	PortsPhase Phase = "ports"
+	// LinksPhase is the ingress link discovery pass
+	LinksPhase Phase = "links"
+	// HostsPhase is the host discovery pass
+	HostsPhase Phase = "hosts"
+)
+
+// PhaseResult describes the outcome of a single discovery pass
+type PhaseResult struct {
+	Phase    Phase
+	Duration time.Duration
+	Error    string
+}
+
+// DeviceResult describes the outcome of all discovery passes run for a device
+type DeviceResult struct {
+	DeviceID topo.ID
+	Phases   []*PhaseResult
+	// Set if the discovery passes could not be run at all
+	Error string
+}
+
+// Failed returns true if the discovery could not be run or if any of its passes failed
+func (r *DeviceResult) Failed() bool {
+	if len(r.Error) > 0 {
+		return true
+	}
+	for _, phase := range r.Phases {
+		if len(phase.Error) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// Runs the discovery pass and records its outcome
+func (r *DeviceResult) addPhase(phase Phase, pass func(object *topo.Object) error, object *topo.Object) {
+	start := time.Now()
+	result := &PhaseResult{Phase: phase}
+	if err := pass(object); err != nil {
+		result.Error = err.Error()
+	}
+	result.Duration = time.Since(start)
+	r.Phases = append(r.Phases, result)
+}
+
+// Rediscover immediately runs the port, link and host discovery passes for the specified device, or for all realm
+// devices if no device ID is given, optionally forcing their gNMI connections to be re-established first.
+// The returned channel yields the result for each device as its discovery passes complete, and is closed once all
+// of them have completed or the context is done.
+func (c *Controller) Rediscover(ctx context.Context, deviceID topo.ID, reconnect bool) (<-chan *DeviceResult, error) {
+	if c.getState() != Monitoring {
+		return nil, errors.NewUnavailable(controllerNotReady)
+	}
+
+	var objects []*topo.Object
+	if len(deviceID) > 0 {
+		object, err := c.getObject(ctx, deviceID)
+		if err != nil {
+			return nil, err
+		}
+		if object == nil || object.GetEntity() == nil {
+			return nil, errors.NewNotFound("device %s not found", deviceID)
+		}
+		if c.realmOptions != nil && object.Labels[c.realmOptions.Label] != c.realmOptions.Value {
+			return nil, errors.NewInvalid("device %s is not in the %s=%s realm", deviceID, c.realmOptions.Label, c.realmOptions.Value)
+		}
+		if !c.owns(deviceID) {
+			return nil, errors.NewInvalid("%s %s", deviceID, c.notOwned(deviceID))
+		}
+		objects = []*topo.Object{object}
+	} else {
+		var err error
+		if objects, err = c.realmObjects(ctx); err != nil {
+			return nil, err
+		}
+		objects = c.ownedObjects(objects)
+	}
+
+	// Results are buffered for all devices so that workers never block on a departed requester
+	results := make(chan *DeviceResult, len(objects))
+	out := make(chan *DeviceResult)
+	for _, object := range objects {
+		c.realmQueue.add(&discoveryJob{object: object, reconnect: reconnect, results: []chan<- *DeviceResult{results}})
+	}
+	go func() {
+		defer close(out)
+		for range objects {
+			select {
+			case result := <-results:
+				select {
+				case out <- result:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	log.Infof("Rediscovering %d devices; reconnect: %t", len(objects), reconnect)
+	return out, nil
+}
+
+// Returns all objects in our realm with StratumAgents or LocalAgents aspects
+func (c *Controller) realmObjects(ctx context.Context) ([]*topo.Object, error) {
+	stream, err := c.topoClient.Query(ctx, &topo.QueryRequest{Filters: queryFilter(c.realmOptions)})
+	if err != nil {
+		return nil, errors.FromGRPC(err)
+	}
+	objects := make([]*topo.Object, 0)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return objects, nil
+		} else if err != nil {
+			return nil, errors.FromGRPC(err)
+		}
+		objects = append(objects, resp.Object)
+	}
+}
+
+// Returns only the objects owned by this instance
+func (c *Controller) ownedObjects(objects []*topo.Object) []*topo.Object {
+	owned := make([]*topo.Object, 0, len(objects))
+	for _, object := range objects {
+		if c.owns(object.ID) {
+			owned = append(owned, object)
+		}
+	}
+	return owned
+}
+
+// Drops the southbound gNMI connections of the specified device, forcing them to be re-established on the next
+// discovery pass; unlike releaseDevice, this retains the device agent bindings. Must only be called by the worker
+// of the device, so that the connections are not pulled from under its discovery passes.
+func (c *Controller) disconnectDevice(id topo.ID) {
+	c.portReconciler.portDiscovery.ReleaseDevice(id)
+	c.linkReconciler.linkDiscovery.ReleaseDevice(id)
+	c.hostReconciler.hostDiscovery.ReleaseDevice(id)
+}
@@ -0,0 +1,71 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	api "github.com/onosproject/onos-api/go/onos/discovery"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRediscover(t *testing.T) {
+	f := newFakeTopo()
+	sb := newFakeSouthbound()
+	c := newRunningTestController(f, sb)
+	defer c.realmQueue.shutDown()
+	ctx := context.TODO()
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+	for _, id := range []string{"leaf1", "leaf2"} {
+		assert.NoError(t, c.AddSwitch(ctx, &api.AddSwitchRequest{ID: id, PodID: "pod1", RackID: "rack1",
+			ManagementInfo: &api.ManagementInfo{GNMIEndpoint: id + ":9339", Realm: "pod1"}}))
+	}
+	sb.ports["leaf1"] = map[string]*topo.Port{"1": {Number: 1, Status: statusUp}}
+	sb.failing["leaf2"] = errors.NewUnavailable("unreachable")
+
+	results, err := c.Rediscover(ctx, "leaf1", true)
+	assert.NoError(t, err)
+	result := <-results
+	assert.Equal(t, topo.ID("leaf1"), result.DeviceID)
+	assert.False(t, result.Failed())
+	assert.Len(t, result.Phases, 3)
+	assert.Equal(t, PortsPhase, result.Phases[0].Phase)
+	assert.True(t, f.has("leaf1/1"))
+	assert.Equal(t, 3, sb.released["leaf1"])
+	_, ok := <-results
+	assert.False(t, ok)
+
+	results, err = c.Rediscover(ctx, "", false)
+	assert.NoError(t, err)
+	failed := 0
+	count := 0
+	for result := range results {
+		count++
+		if result.Failed() {
+			failed++
+			assert.Equal(t, topo.ID("leaf2"), result.DeviceID)
+			assert.Equal(t, "unreachable", result.Phases[0].Error)
+		}
+	}
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 1, failed)
+
+	_, err = c.Rediscover(ctx, "leaf3", false)
+	assert.True(t, errors.IsNotFound(err))
+
+	status := c.GetStatus()
+	assert.Equal(t, "Monitoring", status.State.String())
+	assert.Len(t, status.Devices, 2)
+	assert.Equal(t, topo.ID("leaf1"), status.Devices[0].DeviceID)
+	assert.Equal(t, "leaf1", status.Devices[0].AgentID)
+	assert.False(t, status.Devices[0].LastPortDiscovery.IsZero())
+	assert.False(t, status.Devices[0].LastHostDiscovery.IsZero())
+	assert.Empty(t, status.Devices[0].LastError)
+	assert.True(t, status.Devices[1].LastPortDiscovery.IsZero())
+	assert.Equal(t, "ports: unreachable; links: unreachable; hosts: unreachable", status.Devices[1].LastError)
+}
@@ -0,0 +1,91 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/sharding"
+	"io"
+)
+
+// Rebalance sets the hash ring deciding which realm and neighbor realm devices this instance discovers; devices
+// no longer owned are released and newly owned devices are discovered. A nil ring makes the instance own all
+// devices. If the controller is not yet started, the ring applies once it is.
+func (c *Controller) Rebalance(ring *sharding.Ring) {
+	c.lock.Lock()
+	c.ring = ring
+	state := c.state
+	linkReconciler := c.linkReconciler
+	c.lock.Unlock()
+	if linkReconciler != nil {
+		linkReconciler.SetAgentIDSharing(ring != nil)
+	}
+
+	if ring != nil {
+		log.Infof("Rebalancing devices across instances %v", ring.Members())
+	}
+	if state == Monitoring {
+		// Sweep the realms so that the workers pick up newly owned devices and release those owned by others
+		go func() {
+			_ = c.runFullDiscoverySweep()
+			if c.hasNeighborRealmOptions() {
+				if err := c.sweepNeighborRealm(); err != nil {
+					log.Warnf("Unable to query onos-topo: %+v", err)
+				}
+			}
+		}()
+	}
+}
+
+// Returns the current hash ring, or nil if sharding is disabled
+func (c *Controller) getRing() *sharding.Ring {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.ring
+}
+
+// Returns true if this instance owns the specified device
+func (c *Controller) owns(id topo.ID) bool {
+	ring := c.getRing()
+	return ring == nil || ring.Owns(string(id))
+}
+
+// Returns true if the device should be queued for discovery; this is the case for devices owned by this instance
+// and for devices still holding discovery state from before they moved to another instance
+func (c *Controller) shouldDiscover(id topo.ID) bool {
+	return c.owns(id) || c.status.has(id)
+}
+
+// Returns the error reported for devices owned by another instance
+func (c *Controller) notOwned(id topo.ID) string {
+	ring := c.getRing()
+	if ring == nil {
+		return ""
+	}
+	owner := ring.Owner(string(id))
+	if len(owner) == 0 {
+		return "shard membership has not been learned yet"
+	}
+	return fmt.Sprintf("device is owned by instance %s", owner)
+}
+
+// Enqueues all objects in the neighbor realm for registration of their agent IDs
+func (c *Controller) sweepNeighborRealm() error {
+	stream, err := c.topoClient.Query(c.ctx, &topo.QueryRequest{Filters: queryFilter(c.neighborRealmOptions)})
+	if err != nil {
+		return err
+	}
+	for c.getState() != Stopped {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		c.neighborRealmQueue.add(&discoveryJob{object: resp.Object})
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	api "github.com/onosproject/onos-api/go/onos/discovery"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/topo-discovery/pkg/sharding"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// Returns the first of the candidate names produced by the generator which the ring assigns to the given owner
+func findOwned(ring *sharding.Ring, owner string, name func(i int) string) string {
+	for i := 0; ; i++ {
+		if candidate := name(i); ring.Owner(candidate) == owner {
+			return candidate
+		}
+	}
+}
+
+func TestSharding(t *testing.T) {
+	f := newFakeTopo()
+	sbA, sbB := newFakeSouthbound(), newFakeSouthbound()
+	a, b := newRunningTestController(f, sbA), newRunningTestController(f, sbB)
+	defer a.realmQueue.shutDown()
+	defer b.realmQueue.shutDown()
+	ctx := context.TODO()
+
+	members := []string{"a", "b"}
+	ringA, ringB := sharding.NewRing("a", members), sharding.NewRing("b", members)
+	leafA := findOwned(ringA, "a", func(i int) string { return fmt.Sprintf("leaf%d", i) })
+	leafB := findOwned(ringA, "b", func(i int) string { return fmt.Sprintf("leaf%d", i) })
+
+	assert.NoError(t, a.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, a.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+	for _, id := range []string{leafA, leafB} {
+		assert.NoError(t, a.AddSwitch(ctx, &api.AddSwitchRequest{ID: id, PodID: "pod1", RackID: "rack1",
+			ManagementInfo: &api.ManagementInfo{GNMIEndpoint: id + ":9339", Realm: "pod1"}}))
+	}
+	a.Rebalance(ringA)
+	b.Rebalance(ringB)
+
+	// Each instance discovers only its own share of the devices
+	rediscover := func(c *Controller) map[topo.ID]*DeviceResult {
+		results, err := c.Rediscover(ctx, "", false)
+		assert.NoError(t, err)
+		byDevice := make(map[topo.ID]*DeviceResult)
+		for result := range results {
+			byDevice[result.DeviceID] = result
+		}
+		return byDevice
+	}
+	resultsB := rediscover(b)
+	assert.Len(t, resultsB, 1)
+	assert.False(t, resultsB[topo.ID(leafB)].Failed())
+
+	_, err := a.Rediscover(ctx, topo.ID(leafB), false)
+	assert.True(t, errors.IsInvalid(err))
+
+	// Links to devices discovered by the other instance are resolved via the agent ID recorded in onos-topo
+	sbA.links[topo.ID(leafA)] = &southbound.LinkReport{AgentID: leafA, Links: map[uint32]*southbound.Link{
+		1: {IngressDevice: leafA, IngressPort: 1, EgressDevice: leafB, EgressPort: 2}}}
+	resultsA := rediscover(a)
+	assert.Len(t, resultsA, 1)
+	assert.False(t, resultsA[topo.ID(leafA)].Failed())
+	assert.True(t, f.has(topo.ID(fmt.Sprintf("%s/2-%s/1", leafB, leafA))))
+	assert.Equal(t, leafB, a.linkReconciler.AgentID(topo.ID(leafB)))
+
+	// Once another instance joins and takes over a device, its discovery state is released
+	joined := ""
+	for i := 0; len(joined) == 0; i++ {
+		candidate := fmt.Sprintf("c%d", i)
+		if sharding.NewRing("a", []string{"a", "b", candidate}).Owner(leafA) == candidate {
+			joined = candidate
+		}
+	}
+	released := sbA.released[topo.ID(leafA)]
+	a.Rebalance(sharding.NewRing("a", []string{"a", "b", joined}))
+	assert.Eventually(t, func() bool {
+		sbA.lock.Lock()
+		defer sbA.lock.Unlock()
+		return sbA.released[topo.ID(leafA)] > released
+	}, 5*time.Second, 10*time.Millisecond)
+	assert.Eventually(t, func() bool { return !a.status.has(topo.ID(leafA)) }, time.Second, 10*time.Millisecond)
+
+	// Without sharding, the instance discovers all devices again
+	a.Rebalance(nil)
+	assert.Len(t, rediscover(a), 2)
+}
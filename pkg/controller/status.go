@@ -0,0 +1,188 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"fmt"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// String returns the name of the controller state
+func (s State) String() string {
+	switch s {
+	case Disconnected:
+		return "Disconnected"
+	case Connected:
+		return "Connected"
+	case Initialized:
+		return "Initialized"
+	case Monitoring:
+		return "Monitoring"
+	case Stopped:
+		return "Stopped"
+	}
+	return fmt.Sprintf("State(%d)", int(s))
+}
+
+// Status describes the operational status of the controller
+type Status struct {
+	State             State
+	LastSweep         time.Time
+	LastSweepDuration time.Duration
+	LastSweepError    string
+
+	RealmQueueDepth         int
+	NeighborRealmQueueDepth int
+	WorkingOn               int
+
+	Devices []*DeviceStatus
+	Flaps   []*FlapStatus
+}
+
+// FlapStatus describes the flap history of a single link or port which changed its status at least once
+type FlapStatus struct {
+	ID         topo.ID
+	Kind       string
+	Status     string
+	Flaps      uint64
+	Penalty    float64
+	Suppressed bool
+}
+
+// DeviceStatus describes the discovery status of a single device
+type DeviceStatus struct {
+	DeviceID          topo.ID
+	LastPortDiscovery time.Time
+	LastLinkDiscovery time.Time
+	LastHostDiscovery time.Time
+	LastError         string
+	LastErrorTime     time.Time
+	AgentID           string
+
+	// Indicate whether the port status, link and host monitor subscription streams are active
+	PortMonitor bool
+	LinkMonitor bool
+	HostMonitor bool
+}
+
+// Tracks the sweep and per-device discovery records
+type statusTracker struct {
+	lock              sync.RWMutex
+	lastSweep         time.Time
+	lastSweepDuration time.Duration
+	lastSweepError    string
+	devices           map[topo.ID]*DeviceStatus
+}
+
+func newStatusTracker() *statusTracker {
+	return &statusTracker{devices: make(map[topo.ID]*DeviceStatus)}
+}
+
+// Records the outcome of a full discovery sweep
+func (t *statusTracker) recordSweep(start time.Time, err error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	t.lastSweep = start
+	t.lastSweepDuration = time.Since(start)
+	t.lastSweepError = ""
+	if err != nil {
+		t.lastSweepError = err.Error()
+	}
+}
+
+// Records the outcome of the discovery passes for a device
+func (t *statusTracker) recordResult(result *DeviceResult) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	ds, ok := t.devices[result.DeviceID]
+	if !ok {
+		ds = &DeviceStatus{DeviceID: result.DeviceID}
+		t.devices[result.DeviceID] = ds
+	}
+	now := time.Now()
+	failures := make([]string, 0)
+	if len(result.Error) > 0 {
+		failures = append(failures, result.Error)
+	}
+	for _, phase := range result.Phases {
+		if len(phase.Error) > 0 {
+			failures = append(failures, fmt.Sprintf("%s: %s", phase.Phase, phase.Error))
+			continue
+		}
+		switch phase.Phase {
+		case PortsPhase:
+			ds.LastPortDiscovery = now
+		case LinksPhase:
+			ds.LastLinkDiscovery = now
+		case HostsPhase:
+			ds.LastHostDiscovery = now
+		}
+	}
+	if len(failures) > 0 {
+		ds.LastError = strings.Join(failures, "; ")
+		ds.LastErrorTime = now
+	}
+}
+
+// Forgets the records of the specified device
+func (t *statusTracker) remove(id topo.ID) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	delete(t.devices, id)
+}
+
+// Returns true if there are records of the specified device
+func (t *statusTracker) has(id topo.ID) bool {
+	t.lock.RLock()
+	defer t.lock.RUnlock()
+	_, ok := t.devices[id]
+	return ok
+}
+
+// GetStatus returns the controller state, the last full discovery sweep, queue depths and the discovery status
+// of each device worked on so far, along with the flap history of links and ports
+func (c *Controller) GetStatus() *Status {
+	c.lock.RLock()
+	status := &Status{
+		State: c.state,
+	}
+	realmQueue, neighborRealmQueue := c.realmQueue, c.neighborRealmQueue
+	portReconciler, linkReconciler, hostReconciler := c.portReconciler, c.linkReconciler, c.hostReconciler
+	c.lock.RUnlock()
+	status.RealmQueueDepth = realmQueue.len()
+	status.NeighborRealmQueueDepth = neighborRealmQueue.len()
+	status.WorkingOn = realmQueue.processing() + neighborRealmQueue.processing()
+
+	c.status.lock.RLock()
+	status.LastSweep = c.status.lastSweep
+	status.LastSweepDuration = c.status.lastSweepDuration
+	status.LastSweepError = c.status.lastSweepError
+	status.Devices = make([]*DeviceStatus, 0, len(c.status.devices))
+	for _, ds := range c.status.devices {
+		dsCopy := *ds
+		status.Devices = append(status.Devices, &dsCopy)
+	}
+	c.status.lock.RUnlock()
+
+	for _, ds := range status.Devices {
+		if portReconciler != nil {
+			ds.PortMonitor = portReconciler.portDiscovery.IsMonitoring(ds.DeviceID)
+		}
+		if linkReconciler != nil {
+			ds.AgentID = linkReconciler.AgentID(ds.DeviceID)
+			ds.LinkMonitor = linkReconciler.linkDiscovery.IsMonitoring(ds.DeviceID)
+		}
+		if hostReconciler != nil {
+			ds.HostMonitor = hostReconciler.hostDiscovery.IsMonitoring(ds.DeviceID)
+		}
+	}
+	sort.Slice(status.Devices, func(i, j int) bool { return status.Devices[i].DeviceID < status.Devices[j].DeviceID })
+	status.Flaps = c.dampener.flapStatus()
+	return status
+}
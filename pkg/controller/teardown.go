@@ -0,0 +1,126 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"io"
+	"strings"
+)
+
+// SetPurgeRemovedDevices enables or disables removal of the ports discovered for devices whose entities have been
+// removed from onos-topo, along with the links and hosts attached to those ports
+func (c *Controller) SetPurgeRemovedDevices(purge bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.purgeRemovedDevices = purge
+}
+
+const deviceHasBeenRemoved = "device has been removed"
+
+// Queues teardown of the discovery state held for a realm device whose entity has been, or is being, removed from
+// onos-topo; safe to call from any goroutine
+func (c *Controller) deviceRemoved(object *topo.Object) {
+	log.Infof("Device %s has been removed; tearing down its discovery state", object.ID)
+
+	// Mark the device as removed so that workers skip any of its discovery jobs still lingering in the queue;
+	// the teardown job is coalesced with any ongoing work on the device, so it runs only once that is finished
+	c.lock.Lock()
+	c.removed[object.ID] = true
+	realmQueue := c.realmQueue
+	c.lock.Unlock()
+	if realmQueue != nil {
+		realmQueue.add(&discoveryJob{object: object, removal: true})
+	}
+}
+
+// Tears down the discovery state held for the removed device and, if enabled, purges the device ports along with
+// their links and hosts
+func (c *Controller) tearDownDevice(object *topo.Object) {
+	c.lock.RLock()
+	purge := c.purgeRemovedDevices
+	c.lock.RUnlock()
+
+	c.releaseDevice(object.ID)
+	if purge {
+		report, err := c.purgeDevicePorts(c.ctx, object.ID)
+		if err != nil {
+			log.Warnf("Unable to purge ports of removed device %s: %+v", object.ID, err)
+			return
+		}
+		log.Infof("Purged %d objects of removed device %s", len(report.Objects), object.ID)
+	}
+}
+
+// Queues purging of the agent ID binding of a neighbor realm device whose entity has been removed from onos-topo
+func (c *Controller) neighborRemoved(object *topo.Object) {
+	log.Infof("Neighbor %s has been removed; purging its link agent bindings", object.ID)
+	c.neighborRealmQueue.add(&discoveryJob{object: object, removal: true})
+}
+
+// Clears the removed mark of the device, since its entity has been created again
+func (c *Controller) deviceExists(id topo.ID) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	delete(c.removed, id)
+}
+
+// Returns true if the entity of the device has been removed from onos-topo
+func (c *Controller) isRemoved(id topo.ID) bool {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.removed[id]
+}
+
+// Returns the filters selecting the entities of the given kind in the realm; ports and links carry the labels of
+// their devices
+func (c *Controller) realmFilters(kind string) *topo.Filters {
+	filters := &topo.Filters{
+		KindFilter: &topo.Filter{Filter: &topo.Filter_Equal_{Equal_: &topo.EqualFilter{Value: kind}}},
+	}
+	if c.realmOptions != nil && len(c.realmOptions.Label) > 0 {
+		filters.LabelFilters = []*topo.Filter{{Key: c.realmOptions.Label,
+			Filter: &topo.Filter_Equal_{Equal_: &topo.EqualFilter{Value: c.realmOptions.Value}}}}
+	}
+	return filters
+}
+
+// Removes the port entities of the specified device, along with the links and hosts attached to them
+func (c *Controller) purgeDevicePorts(ctx context.Context, id topo.ID) (*RemovalReport, error) {
+	stream, err := c.topoClient.Query(ctx, &topo.QueryRequest{Filters: c.realmFilters(topo.PortKind)})
+	if err != nil {
+		return nil, errors.FromGRPC(err)
+	}
+
+	// Port IDs are composed of the device ID and the port number
+	prefix := string(id) + "/"
+	ports := make([]*topo.Object, 0)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, errors.FromGRPC(err)
+		}
+		if strings.HasPrefix(string(resp.Object.ID), prefix) {
+			ports = append(ports, resp.Object)
+		}
+	}
+
+	report := &RemovalReport{}
+	visited := make(map[topo.ID]bool)
+	for _, port := range ports {
+		if visited[port.ID] {
+			continue
+		}
+		if err = c.removeEntity(ctx, port, report, visited); err != nil {
+			return report, err
+		}
+		c.events.Publish(&Event{Type: PortDeleted, ObjectID: port.ID, DeviceID: id})
+	}
+	return report, nil
+}
@@ -0,0 +1,122 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	api "github.com/onosproject/onos-api/go/onos/discovery"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDeviceRemoved(t *testing.T) {
+	f := newFakeTopo()
+	sb := newFakeSouthbound()
+	c := newRunningTestController(f, sb)
+	defer c.realmQueue.shutDown()
+	ctx := context.TODO()
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+	for _, id := range []string{"leaf1", "spine1"} {
+		assert.NoError(t, c.AddSwitch(ctx, &api.AddSwitchRequest{ID: id, PodID: "pod1", RackID: "rack1",
+			ManagementInfo: &api.ManagementInfo{GNMIEndpoint: id + ":9339", Realm: "pod1"}}))
+	}
+	sb.ports["leaf1"] = map[string]*topo.Port{"1": {Number: 1, Status: statusUp}}
+	sb.ports["spine1"] = map[string]*topo.Port{"1": {Number: 1, Status: statusUp}}
+
+	results, err := c.Rediscover(ctx, "", false)
+	assert.NoError(t, err)
+	for range results {
+	}
+	assert.Equal(t, "leaf1", c.linkReconciler.AgentID("leaf1"))
+
+	// Stitch a link between the switch ports, a host on the leaf port and a pending link from the leaf
+	create := func(object *topo.Object) {
+		_, err := f.Create(ctx, &topo.CreateRequest{Object: object})
+		assert.NoError(t, err)
+	}
+	create(topo.NewEntity("spine1/1-leaf1/1", topo.LinkKind))
+	create(topo.NewRelation("spine1/1", "spine1/1-leaf1/1", topo.OriginatesKind))
+	create(topo.NewRelation("leaf1/1", "spine1/1-leaf1/1", topo.TerminatesKind))
+	create(topo.NewEntity("host1", topo.HostKind))
+	create(topo.NewRelation("leaf1/1", "host1", topo.ConnectionKind))
+	c.linkReconciler.addToPendingLinks(&southbound.Link{IngressDevice: "leaf1", EgressDevice: "spine2"})
+
+	resp, err := f.Get(ctx, &topo.GetRequest{ID: "leaf1"})
+	assert.NoError(t, err)
+	leaf1 := resp.Object
+	_, err = f.Delete(ctx, &topo.DeleteRequest{ID: "leaf1"})
+	assert.NoError(t, err)
+
+	// Without purging, only the discovery state is torn down
+	released := sb.released["leaf1"]
+	c.deviceRemoved(leaf1)
+	waitForIdle(t, c.realmQueue)
+	assert.Equal(t, released+3, sb.released["leaf1"])
+	assert.Empty(t, c.linkReconciler.AgentID("leaf1"))
+	assert.Empty(t, c.linkReconciler.pendingLinks)
+	assert.True(t, f.has("leaf1/1"))
+	for _, ds := range c.GetStatus().Devices {
+		assert.NotEqual(t, topo.ID("leaf1"), ds.DeviceID)
+	}
+
+	// Lingering jobs for the removed device are skipped
+	jobResults := make(chan *DeviceResult, 1)
+	c.realmQueue.add(&discoveryJob{object: leaf1, results: []chan<- *DeviceResult{jobResults}})
+	result := <-jobResults
+	assert.True(t, result.Failed())
+	assert.Len(t, result.Phases, 0)
+
+	// With purging, the ports are removed along with their links and hosts
+	c.SetPurgeRemovedDevices(true)
+	c.deviceRemoved(leaf1)
+	waitForIdle(t, c.realmQueue)
+	assert.False(t, f.has("leaf1/1"))
+	assert.False(t, f.has("spine1/1-leaf1/1"))
+	assert.False(t, f.has("host1"))
+	assert.True(t, f.has("spine1/1"))
+	assert.Equal(t, "spine1", c.linkReconciler.AgentID("spine1"))
+
+	// A sweep still returning the device, having started before its removal, leaves it removed
+	create(leaf1)
+	released = sb.released["leaf1"]
+	assert.NoError(t, c.sweepRealm())
+	waitForIdle(t, c.realmQueue)
+	assert.True(t, c.isRemoved("leaf1"))
+	assert.Equal(t, released, sb.released["leaf1"])
+
+	// Once the device reappears, it is discovered again
+	c.deviceExists("leaf1")
+	c.realmQueue.add(&discoveryJob{object: leaf1, results: []chan<- *DeviceResult{jobResults}})
+	result = <-jobResults
+	assert.False(t, result.Failed())
+}
+
+func TestRemoveSwitchTearsDown(t *testing.T) {
+	f := newFakeTopo()
+	sb := newFakeSouthbound()
+	c := newRunningTestController(f, sb)
+	defer c.realmQueue.shutDown()
+	ctx := context.TODO()
+	assert.NoError(t, c.AddPod(ctx, &api.AddPodRequest{ID: "pod1"}))
+	assert.NoError(t, c.AddRack(ctx, &api.AddRackRequest{ID: "rack1", PodID: "pod1"}))
+	assert.NoError(t, c.AddSwitch(ctx, &api.AddSwitchRequest{ID: "leaf1", PodID: "pod1", RackID: "rack1",
+		ManagementInfo: &api.ManagementInfo{GNMIEndpoint: "leaf1:9339", Realm: "pod1"}}))
+	results, err := c.Rediscover(ctx, "leaf1", false)
+	assert.NoError(t, err)
+	for range results {
+	}
+
+	// The discovery state of removed devices is torn down by a discovery worker rather than by the caller
+	_, err = c.RemoveSwitch(ctx, "leaf1")
+	assert.NoError(t, err)
+	waitForIdle(t, c.realmQueue)
+	sb.lock.Lock()
+	defer sb.lock.Unlock()
+	assert.Equal(t, 3, sb.released["leaf1"])
+	assert.True(t, c.isRemoved("leaf1"))
+}
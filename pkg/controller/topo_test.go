@@ -0,0 +1,374 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"github.com/gogo/protobuf/types"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-net-lib/pkg/realm"
+	"github.com/onosproject/topo-discovery/pkg/southbound"
+	"google.golang.org/grpc"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Simple in-memory stand-in for the onos-topo client used for testing the controller logic
+type fakeTopo struct {
+	topo.TopoClient
+	lock    sync.RWMutex
+	objects map[topo.ID]*topo.Object
+
+	gets    int
+	creates int
+	updates int
+	deletes int
+
+	// If positive, the n-th subsequent create call will fail
+	failCreateAfter int
+}
+
+func newFakeTopo() *fakeTopo {
+	return &fakeTopo{objects: make(map[topo.ID]*topo.Object)}
+}
+
+// Returns a copy of the object, detached from the stored one
+func copyObject(object *topo.Object) *topo.Object {
+	c := *object
+	c.Labels = make(map[string]string, len(object.Labels))
+	for k, v := range object.Labels {
+		c.Labels[k] = v
+	}
+	if object.Aspects != nil {
+		c.Aspects = make(map[string]*types.Any, len(object.Aspects))
+		for k, v := range object.Aspects {
+			c.Aspects[k] = v
+		}
+	}
+	if entity := object.GetEntity(); entity != nil {
+		e := *entity
+		e.SrcRelationIDs = append([]topo.ID{}, entity.SrcRelationIDs...)
+		e.TgtRelationIDs = append([]topo.ID{}, entity.TgtRelationIDs...)
+		c.Obj = &topo.Object_Entity{Entity: &e}
+	}
+	return &c
+}
+
+func (f *fakeTopo) Create(ctx context.Context, in *topo.CreateRequest, opts ...grpc.CallOption) (*topo.CreateResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.failCreateAfter > 0 {
+		f.failCreateAfter--
+		if f.failCreateAfter == 0 {
+			return nil, errors.Status(errors.NewUnavailable("injected failure")).Err()
+		}
+	}
+
+	object := copyObject(in.Object)
+	if relation := object.GetRelation(); relation != nil && object.ID == "" {
+		object.ID = topo.ID(fmt.Sprintf("%s-%s-%s", relation.SrcEntityID, relation.KindID, relation.TgtEntityID))
+	}
+	if _, ok := f.objects[object.ID]; ok {
+		return nil, errors.Status(errors.NewAlreadyExists("%s already exists", object.ID)).Err()
+	}
+	if relation := object.GetRelation(); relation != nil {
+		if src, ok := f.objects[relation.SrcEntityID]; ok {
+			src.GetEntity().SrcRelationIDs = append(src.GetEntity().SrcRelationIDs, object.ID)
+		}
+		if tgt, ok := f.objects[relation.TgtEntityID]; ok {
+			tgt.GetEntity().TgtRelationIDs = append(tgt.GetEntity().TgtRelationIDs, object.ID)
+		}
+	}
+	f.objects[object.ID] = object
+	f.creates++
+	return &topo.CreateResponse{Object: copyObject(object)}, nil
+}
+
+func (f *fakeTopo) Get(ctx context.Context, in *topo.GetRequest, opts ...grpc.CallOption) (*topo.GetResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.gets++
+	object, ok := f.objects[in.ID]
+	if !ok {
+		return nil, errors.Status(errors.NewNotFound("%s not found", in.ID)).Err()
+	}
+	return &topo.GetResponse{Object: copyObject(object)}, nil
+}
+
+func (f *fakeTopo) Update(ctx context.Context, in *topo.UpdateRequest, opts ...grpc.CallOption) (*topo.UpdateResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	existing, ok := f.objects[in.Object.ID]
+	if !ok {
+		return nil, errors.Status(errors.NewNotFound("%s not found", in.Object.ID)).Err()
+	}
+	object := copyObject(in.Object)
+	if entity := existing.GetEntity(); entity != nil {
+		object.Obj = existing.Obj
+	}
+	f.objects[object.ID] = object
+	f.updates++
+	return &topo.UpdateResponse{Object: copyObject(object)}, nil
+}
+
+func (f *fakeTopo) Delete(ctx context.Context, in *topo.DeleteRequest, opts ...grpc.CallOption) (*topo.DeleteResponse, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	object, ok := f.objects[in.ID]
+	if !ok {
+		return nil, errors.Status(errors.NewNotFound("%s not found", in.ID)).Err()
+	}
+	if relation := object.GetRelation(); relation != nil {
+		if src, ok := f.objects[relation.SrcEntityID]; ok {
+			src.GetEntity().SrcRelationIDs = without(src.GetEntity().SrcRelationIDs, in.ID)
+		}
+		if tgt, ok := f.objects[relation.TgtEntityID]; ok {
+			tgt.GetEntity().TgtRelationIDs = without(tgt.GetEntity().TgtRelationIDs, in.ID)
+		}
+	}
+	delete(f.objects, in.ID)
+	f.deletes++
+	return &topo.DeleteResponse{}, nil
+}
+
+// Query returns either the entities of the kind matching the kind and label filters, the targets of the relations matching
+// the relation filter, the entities matching the label filters alone, or, emulating the realm query filter, all
+// entities with StratumAgents or LocalAgents aspects
+func (f *fakeTopo) Query(ctx context.Context, in *topo.QueryRequest, opts ...grpc.CallOption) (topo.Topo_QueryClient, error) {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	stream := &fakeQueryStream{}
+	var rf *topo.RelationFilter
+	var kf *topo.Filter
+	var lfs []*topo.Filter
+	if in.Filters != nil {
+		rf = in.Filters.RelationFilter
+		kf = in.Filters.KindFilter
+		if kf == nil && rf == nil && len(in.Filters.WithAspects) == 0 {
+			lfs = in.Filters.LabelFilters
+		}
+	}
+	for _, object := range f.objects {
+		if len(lfs) > 0 {
+			if object.GetEntity() != nil && matchesLabels(object, lfs) {
+				stream.objects = append(stream.objects, copyObject(object))
+			}
+			continue
+		}
+		if kf != nil {
+			if relation := object.GetRelation(); relation != nil && string(relation.KindID) == kf.GetEqual_().Value {
+				stream.objects = append(stream.objects, copyObject(object))
+			} else if entity := object.GetEntity(); entity != nil && string(entity.KindID) == kf.GetEqual_().Value &&
+				matchesLabels(object, in.Filters.LabelFilters) && hasAspects(object, in.Filters.WithAspects) {
+				stream.objects = append(stream.objects, copyObject(object))
+			}
+			continue
+		}
+		if rf == nil {
+			if object.GetEntity() != nil && (object.GetAspectBytes("onos.topo.StratumAgents") != nil ||
+				object.GetAspectBytes("onos.topo.LocalAgents") != nil) {
+				stream.objects = append(stream.objects, copyObject(object))
+			}
+			continue
+		}
+		relation := object.GetRelation()
+		if relation == nil || string(relation.SrcEntityID) != rf.SrcId || string(relation.KindID) != rf.RelationKind {
+			continue
+		}
+		if target, ok := f.objects[relation.TgtEntityID]; ok && string(target.GetEntity().KindID) == rf.TargetKind &&
+			hasAspects(target, in.Filters.WithAspects) {
+			stream.objects = append(stream.objects, copyObject(target))
+		}
+	}
+	sort.Slice(stream.objects, func(i, j int) bool { return stream.objects[i].ID < stream.objects[j].ID })
+	return stream, nil
+}
+
+// Returns true if the object has all the given aspects, as onos-topo only returns such objects
+func hasAspects(object *topo.Object, aspects []string) bool {
+	for _, aspect := range aspects {
+		if object.GetAspectBytes(aspect) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Returns true if the object labels match all the given label filters
+func matchesLabels(object *topo.Object, filters []*topo.Filter) bool {
+	for _, filter := range filters {
+		if object.Labels[filter.Key] != filter.GetEqual_().Value {
+			return false
+		}
+	}
+	return true
+}
+
+type fakeQueryStream struct {
+	grpc.ClientStream
+	objects []*topo.Object
+}
+
+func (s *fakeQueryStream) Recv() (*topo.QueryResponse, error) {
+	if len(s.objects) == 0 {
+		return nil, io.EOF
+	}
+	object := s.objects[0]
+	s.objects = s.objects[1:]
+	return &topo.QueryResponse{Object: object}, nil
+}
+
+func without(ids []topo.ID, id topo.ID) []topo.ID {
+	result := make([]topo.ID, 0, len(ids))
+	for _, i := range ids {
+		if i != id {
+			result = append(result, i)
+		}
+	}
+	return result
+}
+
+// Returns true if the fake topo holds an object with the given ID
+func (f *fakeTopo) has(id topo.ID) bool {
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	_, ok := f.objects[id]
+	return ok
+}
+
+// Creates a controller in monitoring state backed by the given fake topo
+func newTestController(f *fakeTopo) *Controller {
+	ctx := context.TODO()
+	c := &Controller{
+		state:          Monitoring,
+		config:         DefaultConfig(),
+		realmOptions:   &realm.Options{Label: "pod", Value: "pod1"},
+		topoClient:     f,
+		ctx:            ctx,
+		removed:        make(map[topo.ID]bool),
+		portReconciler: NewPortReconciler(ctx, f),
+		linkReconciler: NewLinkReconciler(ctx, f),
+		hostReconciler: NewHostReconciler(ctx, f),
+		events:         NewEventBroker(),
+		status:         newStatusTracker(),
+		dampener:       newDampener(DefaultConfig()),
+	}
+	c.portReconciler.events = c.events
+	c.linkReconciler.events = c.events
+	c.hostReconciler.events = c.events
+	c.hostReconciler.realm = c.realmOptions.Value
+	c.hostReconciler.linkReconciler = c.linkReconciler
+	c.portReconciler.dampener = c.dampener
+	c.linkReconciler.dampener = c.dampener
+	c.portReconciler.linkReconciler = c.linkReconciler
+	return c
+}
+
+// Simple stand-in for the southbound port, link and host discovery used for testing the controller logic
+type fakeSouthbound struct {
+	lock     sync.Mutex
+	ports    map[topo.ID]map[string]*topo.Port
+	attrs    map[topo.ID]map[string]*southbound.PortAttributes
+	counters map[topo.ID]map[uint32]*southbound.PortCounters
+	links    map[topo.ID]*southbound.LinkReport
+	hosts    map[topo.ID]*southbound.HostReport
+	failing  map[topo.ID]error
+	released map[topo.ID]int
+
+	releasedAll int
+}
+
+func newFakeSouthbound() *fakeSouthbound {
+	return &fakeSouthbound{
+		ports:    make(map[topo.ID]map[string]*topo.Port),
+		attrs:    make(map[topo.ID]map[string]*southbound.PortAttributes),
+		counters: make(map[topo.ID]map[uint32]*southbound.PortCounters),
+		links:    make(map[topo.ID]*southbound.LinkReport),
+		hosts:    make(map[topo.ID]*southbound.HostReport),
+		failing:  make(map[topo.ID]error),
+		released: make(map[topo.ID]int),
+	}
+}
+
+func (s *fakeSouthbound) GetPorts(object *topo.Object, listener southbound.PortStatusListener) (map[string]*topo.Port, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err, ok := s.failing[object.ID]; ok {
+		return nil, err
+	}
+	return s.ports[object.ID], nil
+}
+
+func (s *fakeSouthbound) GetPortAttributes(id topo.ID) map[string]*southbound.PortAttributes {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return s.attrs[id]
+}
+
+func (s *fakeSouthbound) GetPortCounters(id topo.ID) (map[uint32]*southbound.PortCounters, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err, ok := s.failing[id]; ok {
+		return nil, err
+	}
+	return s.counters[id], nil
+}
+
+func (s *fakeSouthbound) GetIngressLinks(object *topo.Object, listener southbound.IngressLinkListener) (*southbound.LinkReport, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err, ok := s.failing[object.ID]; ok {
+		return nil, err
+	}
+	if report, ok := s.links[object.ID]; ok {
+		return report, nil
+	}
+	return &southbound.LinkReport{AgentID: string(object.ID)}, nil
+}
+
+func (s *fakeSouthbound) GetHosts(object *topo.Object, listener southbound.HostListener) (*southbound.HostReport, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if err, ok := s.failing[object.ID]; ok {
+		return nil, err
+	}
+	if report, ok := s.hosts[object.ID]; ok {
+		return report, nil
+	}
+	return &southbound.HostReport{AgentID: string(object.ID)}, nil
+}
+
+func (s *fakeSouthbound) ReleaseDevice(id topo.ID) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.released[id]++
+}
+
+func (s *fakeSouthbound) ReleaseAll() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.releasedAll++
+}
+
+// Creates a controller backed by the given fake topo and fake southbound, with its discovery workers running
+func newRunningTestController(f *fakeTopo, sb *fakeSouthbound) *Controller {
+	c := newTestController(f)
+	c.portReconciler.portDiscovery = sb
+	c.linkReconciler.linkDiscovery = sb
+	c.hostReconciler.hostDiscovery = sb
+	c.realmQueue = newWorkQueue(realmQueueName, queueDepth)
+	c.workers.Add(4)
+	for i := 0; i < 4; i++ {
+		go c.discover(i, c.realmQueue)
+	}
+	return c
+}
+
+func (s *fakeSouthbound) IsMonitoring(id topo.ID) bool {
+	return false
+}
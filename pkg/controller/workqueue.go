@@ -0,0 +1,339 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"sync"
+	"time"
+)
+
+const (
+	minDiscoveryInterval = 1 * time.Second
+	initialBackoff       = 1 * time.Second
+	maxBackoff           = 2 * time.Minute
+)
+
+// Discovery job for a realm object; the outcome of the discovery passes is sent on all the results channels.
+// Removal jobs tear down the discovery state of the object instead. Reconnect jobs drop the gNMI connections of
+// the object before running the discovery passes. Counter jobs only collect the interface counters of the ports
+// of the object, which need carry no more than its ID.
+type discoveryJob struct {
+	object    *topo.Object
+	removal   bool
+	reconnect bool
+	counters  bool
+	results   []chan<- *DeviceResult
+}
+
+// Returns true if the job should run without waiting for the rate limit or failure backoff of its object to expire
+func (j *discoveryJob) urgent() bool {
+	return j.removal || len(j.results) > 0
+}
+
+// Merges the newer job into this one; the newer object and intent win, any requested reconnect is retained and
+// all requesters get the result. Pending removals are never replaced by discovery jobs though, so that the teardown
+// is not lost; a device created anew is discovered by the next sweep. Counter jobs give way to any other job, their
+// counters being collected next time.
+func (j *discoveryJob) merge(newer *discoveryJob) *discoveryJob {
+	if newer.counters {
+		return j
+	} else if j.counters {
+		return newer
+	} else if j.removal && !newer.removal {
+		return &discoveryJob{object: j.object, removal: true, results: append(j.results, newer.results...)}
+	}
+	return &discoveryJob{object: newer.object, removal: newer.removal, reconnect: j.reconnect || newer.reconnect,
+		results: append(j.results, newer.results...)}
+}
+
+// Sends the result to all requesters of the job
+func (j *discoveryJob) reply(result *DeviceResult) {
+	for _, results := range j.results {
+		results <- result
+	}
+}
+
+// Tracks the pending job and the processing history of a single object
+type workEntry struct {
+	job        *discoveryJob // pending job, if any
+	queued     bool          // the pending job is in the ready list
+	processing bool          // a job is being worked on
+	timer      *time.Timer   // the pending job is waiting for its rate limit or backoff to expire
+	lastStart  time.Time
+	failures   int
+	retryAt    time.Time
+}
+
+// Keyed, de-duplicating work queue. Each object is worked on by at most one worker at a time; jobs added for an
+// object which is already queued or being worked on are coalesced into a single pending job, which runs once the
+// current one finishes. Successive jobs for an object are rate limited, and failed ones are retried with
+// exponential backoff.
+type workQueue struct {
+	name     string
+	capacity int
+
+	lock     sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	entries  map[topo.ID]*workEntry
+	ready    []topo.ID
+	pending  int
+	shutdown bool
+
+	minInterval    time.Duration
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+}
+
+// Creates a work queue which holds at most the given number of pending jobs for distinct objects
+func newWorkQueue(name string, capacity int) *workQueue {
+	q := &workQueue{
+		name:           name,
+		capacity:       capacity,
+		entries:        make(map[topo.ID]*workEntry),
+		minInterval:    minDiscoveryInterval,
+		initialBackoff: initialBackoff,
+		maxBackoff:     maxBackoff,
+	}
+	q.notEmpty = sync.NewCond(&q.lock)
+	q.notFull = sync.NewCond(&q.lock)
+	return q
+}
+
+// Sets the rate limit and failure backoff timings from the given configuration
+func (q *workQueue) setTimings(cfg Config) {
+	if q == nil {
+		return
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	q.minInterval = cfg.MinDiscoveryInterval
+	q.initialBackoff = cfg.InitialBackoff
+	q.maxBackoff = cfg.MaxBackoff
+}
+
+// Adds the job to the queue, coalescing it with any pending job for the same object; blocks while the queue is
+// at capacity, unless the object already has a pending job. Jobs added after shutdown are answered as not ready.
+func (q *workQueue) add(job *discoveryJob) {
+	if !q.enqueue(job) {
+		job.reply(&DeviceResult{DeviceID: job.object.ID, Error: controllerNotReady})
+	}
+}
+
+// Adds the job to the queue as per add; returns false if the queue has been shut down
+func (q *workQueue) enqueue(job *discoveryJob) bool {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	id := job.object.ID
+	for !q.shutdown && q.pending >= q.capacity && !q.hasPending(id) {
+		q.notFull.Wait()
+	}
+	if q.shutdown {
+		return false
+	}
+
+	e, ok := q.entries[id]
+	if !ok {
+		e = &workEntry{}
+		q.entries[id] = e
+	}
+	if e.job != nil {
+		e.job = e.job.merge(job)
+		coalesced.Inc(q.name)
+	} else {
+		e.job = job
+		q.pending++
+		if e.processing {
+			coalesced.Inc(q.name)
+		}
+	}
+	if !e.queued && !e.processing {
+		q.schedule(id, e)
+	}
+	return true
+}
+
+// Returns true if the object has a pending job; must be called with the lock held
+func (q *workQueue) hasPending(id topo.ID) bool {
+	e, ok := q.entries[id]
+	return ok && e.job != nil
+}
+
+// Places the pending job of the object in the ready list, or arms a timer to do so once its rate limit or
+// failure backoff expires; must be called with the lock held
+func (q *workQueue) schedule(id topo.ID, e *workEntry) {
+	delay := time.Duration(0)
+	if !e.job.urgent() {
+		notBefore := e.lastStart.Add(q.minInterval)
+		if e.retryAt.After(notBefore) {
+			notBefore = e.retryAt
+		}
+		delay = time.Until(notBefore)
+	}
+	if delay <= 0 {
+		if e.timer != nil {
+			e.timer.Stop()
+			e.timer = nil
+		}
+		e.queued = true
+		q.ready = append(q.ready, id)
+		q.notEmpty.Signal()
+		return
+	}
+	if e.timer == nil {
+		e.timer = time.AfterFunc(delay, func() { q.promote(id) })
+	}
+}
+
+// Moves the pending job of the object to the ready list once its delay has expired
+func (q *workQueue) promote(id topo.ID) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	e, ok := q.entries[id]
+	if !ok || q.shutdown {
+		return
+	}
+	e.timer = nil
+	if e.job != nil && !e.queued && !e.processing {
+		e.queued = true
+		q.ready = append(q.ready, id)
+		q.notEmpty.Signal()
+	}
+}
+
+// Blocks until a job is ready and marks its object as being worked on; returns false once the queue is shut down
+func (q *workQueue) get() (*discoveryJob, bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	for len(q.ready) == 0 && !q.shutdown {
+		q.notEmpty.Wait()
+	}
+	if q.shutdown {
+		return nil, false
+	}
+	id := q.ready[0]
+	q.ready = q.ready[1:]
+	e := q.entries[id]
+	job := e.job
+	e.job = nil
+	e.queued = false
+	e.processing = true
+	e.lastStart = time.Now()
+	q.pending--
+	q.notFull.Signal()
+	return job, true
+}
+
+// Marks the work on the object as finished, recording whether it failed, and schedules any job which was added
+// for the object in the meantime
+func (q *workQueue) done(id topo.ID, failed bool) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	e, ok := q.entries[id]
+	if !ok {
+		return
+	}
+	e.processing = false
+	if failed {
+		e.failures++
+		e.retryAt = time.Now().Add(q.backoff(e.failures))
+	} else {
+		e.failures = 0
+		e.retryAt = time.Time{}
+	}
+	if e.job != nil && !q.shutdown {
+		q.schedule(id, e)
+	}
+}
+
+// Marks the work on the object as finished without recording its outcome, and schedules any job which was added
+// for the object in the meantime
+func (q *workQueue) release(id topo.ID) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	e, ok := q.entries[id]
+	if !ok {
+		return
+	}
+	e.processing = false
+	if e.job != nil && !q.shutdown {
+		q.schedule(id, e)
+	}
+}
+
+// Returns the backoff following the given number of consecutive failures
+func (q *workQueue) backoff(failures int) time.Duration {
+	backoff := q.initialBackoff
+	for i := 1; i < failures && backoff < q.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > q.maxBackoff {
+		return q.maxBackoff
+	}
+	return backoff
+}
+
+// Forgets the processing history of the object, unless it has pending or ongoing work
+func (q *workQueue) forget(id topo.ID) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	if e, ok := q.entries[id]; ok && e.job == nil && !e.processing {
+		delete(q.entries, id)
+	}
+}
+
+// Shuts the queue down, answering all pending jobs as not ready and releasing all blocked workers and producers
+func (q *workQueue) shutDown() {
+	q.lock.Lock()
+	q.shutdown = true
+	jobs := make([]*discoveryJob, 0)
+	for _, e := range q.entries {
+		if e.timer != nil {
+			e.timer.Stop()
+			e.timer = nil
+		}
+		if e.job != nil {
+			jobs = append(jobs, e.job)
+			e.job = nil
+		}
+	}
+	q.ready = nil
+	q.pending = 0
+	q.notEmpty.Broadcast()
+	q.notFull.Broadcast()
+	q.lock.Unlock()
+
+	// Requesters may be slow to receive, so they are answered without holding the lock
+	for _, job := range jobs {
+		job.reply(&DeviceResult{DeviceID: job.object.ID, Error: controllerNotReady})
+	}
+}
+
+// Returns the number of objects with pending jobs, including those waiting for their delay to expire
+func (q *workQueue) len() int {
+	if q == nil {
+		return 0
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	return q.pending
+}
+
+// Returns the number of objects being worked on
+func (q *workQueue) processing() int {
+	if q == nil {
+		return 0
+	}
+	q.lock.Lock()
+	defer q.lock.Unlock()
+	count := 0
+	for _, e := range q.entries {
+		if e.processing {
+			count++
+		}
+	}
+	return count
+}
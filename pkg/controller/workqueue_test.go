@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package controller
+
+import (
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+// Waits until the queue has neither pending nor ongoing work
+func waitForIdle(t *testing.T, q *workQueue) {
+	assert.Eventually(t, func() bool { return q.len() == 0 && q.processing() == 0 }, 5*time.Second, 10*time.Millisecond)
+}
+
+func newTestJob(id topo.ID, results ...chan<- *DeviceResult) *discoveryJob {
+	return &discoveryJob{object: &topo.Object{ID: id}, results: results}
+}
+
+func TestWorkQueueCoalescing(t *testing.T) {
+	q := newWorkQueue("test", 8)
+	q.minInterval = 0
+	defer q.shutDown()
+
+	// Jobs for an object that is already queued are merged, with the newest object winning
+	first := make(chan *DeviceResult, 1)
+	second := make(chan *DeviceResult, 1)
+	q.add(newTestJob("dev1", first))
+	q.add(&discoveryJob{object: &topo.Object{ID: "dev1", Revision: 2}, results: []chan<- *DeviceResult{second}})
+	q.add(newTestJob("dev2"))
+	assert.Equal(t, 2, q.len())
+
+	job, ok := q.get()
+	assert.True(t, ok)
+	assert.Equal(t, topo.Revision(2), job.object.Revision)
+	assert.Len(t, job.results, 2)
+	assert.False(t, job.reconnect)
+	job.reply(&DeviceResult{DeviceID: "dev1"})
+	assert.Equal(t, topo.ID("dev1"), (<-first).DeviceID)
+	assert.Equal(t, topo.ID("dev1"), (<-second).DeviceID)
+
+	// Jobs for an object being worked on are held back and re-run once the work is done
+	q.add(newTestJob("dev1"))
+	job, ok = q.get()
+	assert.True(t, ok)
+	assert.Equal(t, topo.ID("dev2"), job.object.ID)
+	q.done("dev2", false)
+	assert.Equal(t, 1, q.len())
+	assert.Equal(t, 1, q.processing())
+	q.done("dev1", false)
+	job, ok = q.get()
+	assert.True(t, ok)
+	assert.Equal(t, topo.ID("dev1"), job.object.ID)
+	q.done("dev1", false)
+	assert.Equal(t, 0, q.len())
+	assert.Equal(t, 0, q.processing())
+
+	// A requested reconnect is retained when merged with later jobs
+	q.add(&discoveryJob{object: &topo.Object{ID: "dev1"}, reconnect: true})
+	q.add(newTestJob("dev1"))
+	job, ok = q.get()
+	assert.True(t, ok)
+	assert.True(t, job.reconnect)
+	q.done("dev1", false)
+
+	// Counter jobs give way to other jobs, whichever comes first, and leave the failure history be
+	q.add(newTestJob("dev1"))
+	q.add(&discoveryJob{object: &topo.Object{ID: "dev1"}, counters: true})
+	job, ok = q.get()
+	assert.True(t, ok)
+	assert.False(t, job.counters)
+	q.done("dev1", true)
+	q.add(&discoveryJob{object: &topo.Object{ID: "dev1"}, counters: true})
+	q.add(newTestJob("dev1"))
+	job, ok = q.get()
+	assert.True(t, ok)
+	assert.False(t, job.counters)
+	q.release("dev1")
+	assert.Equal(t, 1, q.entries["dev1"].failures)
+}
+
+func TestWorkQueueRemovalNotReplaced(t *testing.T) {
+	q := newWorkQueue("test", 8)
+	defer q.shutDown()
+
+	// A discovery job added after a pending removal, e.g. by a sweep started before the removal, keeps the removal
+	results := make(chan *DeviceResult, 1)
+	q.add(&discoveryJob{object: &topo.Object{ID: "dev1"}, removal: true})
+	q.add(&discoveryJob{object: &topo.Object{ID: "dev1", Revision: 2}, results: []chan<- *DeviceResult{results}})
+	job, ok := q.get()
+	assert.True(t, ok)
+	assert.True(t, job.removal)
+	assert.Len(t, job.results, 1)
+	q.done("dev1", false)
+	assert.Equal(t, 0, q.len())
+}
+
+func TestWorkQueueRateLimitAndBackoff(t *testing.T) {
+	q := newWorkQueue("test", 8)
+	q.minInterval = 50 * time.Millisecond
+	q.initialBackoff = 200 * time.Millisecond
+	q.maxBackoff = 400 * time.Millisecond
+	defer q.shutDown()
+
+	assert.Equal(t, 200*time.Millisecond, q.backoff(1))
+	assert.Equal(t, 400*time.Millisecond, q.backoff(2))
+	assert.Equal(t, 400*time.Millisecond, q.backoff(10))
+
+	q.add(newTestJob("dev1"))
+	job, _ := q.get()
+	q.done(job.object.ID, false)
+
+	// Successive runs are rate limited
+	start := time.Now()
+	q.add(newTestJob("dev1"))
+	job, _ = q.get()
+	assert.GreaterOrEqual(t, time.Since(start), 40*time.Millisecond)
+	q.done(job.object.ID, true)
+
+	// Failed runs are retried with backoff
+	start = time.Now()
+	q.add(newTestJob("dev1"))
+	job, _ = q.get()
+	assert.GreaterOrEqual(t, time.Since(start), 190*time.Millisecond)
+	q.done(job.object.ID, false)
+
+	// Urgent jobs bypass both
+	q.add(newTestJob("dev1"))
+	job, _ = q.get()
+	q.done(job.object.ID, true)
+	results := make(chan *DeviceResult, 1)
+	start = time.Now()
+	q.add(newTestJob("dev1", results))
+	job, _ = q.get()
+	assert.Less(t, time.Since(start), 40*time.Millisecond)
+	q.done(job.object.ID, false)
+}
+
+func TestWorkQueueShutDown(t *testing.T) {
+	q := newWorkQueue("test", 1)
+	results := make(chan *DeviceResult, 2)
+	q.add(newTestJob("dev1", results))
+
+	// Producers block while the queue is at capacity
+	added := make(chan bool)
+	go func() {
+		q.add(newTestJob("dev2", results))
+		added <- true
+	}()
+	select {
+	case <-added:
+		t.Fatal("add should block while the queue is full")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	q.shutDown()
+	<-added
+	assert.Equal(t, controllerNotReady, (<-results).Error)
+	assert.Equal(t, controllerNotReady, (<-results).Error)
+	_, ok := q.get()
+	assert.False(t, ok)
+
+	// Requesters are answered without holding the lock, so that slow ones do not stall the queue
+	q = newWorkQueue("test", 1)
+	slow := make(chan *DeviceResult)
+	q.add(newTestJob("dev1", slow))
+	go q.shutDown()
+	assert.Eventually(t, func() bool { return q.len() == 0 }, time.Second, 10*time.Millisecond)
+	go q.add(newTestJob("dev2", slow))
+	assert.Equal(t, 0, q.processing())
+	assert.Equal(t, controllerNotReady, (<-slow).Error)
+	assert.Equal(t, controllerNotReady, (<-slow).Error)
+}
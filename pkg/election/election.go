@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package election implements lease-based leader election among topology discovery replicas
+package election
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"sync"
+	"time"
+)
+
+var log = logging.GetLogger("election")
+
+// Lease describes the current holder of the leadership lease and when the lease expires unless renewed
+type Lease struct {
+	Holder  string    `json:"holder"`
+	Expires time.Time `json:"expires"`
+	// Opaque version of the stored lease, used to detect concurrent updates
+	Revision uint64 `json:"revision"`
+}
+
+// Expired returns true if the lease has expired as of the given time
+func (l *Lease) Expired(now time.Time) bool {
+	return !now.Before(l.Expires)
+}
+
+// LeaseStore persists the leadership lease shared by all replicas
+type LeaseStore interface {
+	// Get returns the current lease, or nil if there is none
+	Get(ctx context.Context) (*Lease, error)
+	// Create stores the lease, failing with an already-exists error if there is a lease already
+	Create(ctx context.Context, lease *Lease) error
+	// Update replaces the lease, failing with a conflict error if the stored lease no longer has the revision of
+	// the given lease
+	Update(ctx context.Context, lease *Lease) error
+}
+
+// Callbacks are invoked as the replica gains or loses leadership
+type Callbacks struct {
+	OnStartedLeading func()
+	OnStoppedLeading func()
+}
+
+// Elector competes for the leadership lease on behalf of a replica and tracks whether the replica is the leader
+type Elector struct {
+	id            string
+	store         LeaseStore
+	leaseDuration time.Duration
+	renewInterval time.Duration
+	callbacks     Callbacks
+
+	lock     sync.RWMutex
+	leading  bool
+	deadline time.Time
+	stepDown *time.Timer
+
+	// Serializes leadership transitions along with their callbacks
+	transitions sync.Mutex
+}
+
+// NewElector creates an elector for the replica with the given ID. The lease is renewed every third of its
+// duration, so a standby replica takes over at most the lease duration plus the renew interval after the leader
+// stops renewing it. A leader which fails to renew its lease steps down half a renew interval before the lease
+// expires, so that it never leads alongside the replica taking over.
+func NewElector(id string, store LeaseStore, leaseDuration time.Duration, callbacks Callbacks) *Elector {
+	return &Elector{
+		id:            id,
+		store:         store,
+		leaseDuration: leaseDuration,
+		renewInterval: leaseDuration / 3,
+		callbacks:     callbacks,
+	}
+}
+
+// ID returns the ID of the replica
+func (e *Elector) ID() string {
+	return e.id
+}
+
+// IsLeader returns true if the replica currently holds the leadership lease
+func (e *Elector) IsLeader() bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+	return e.leading
+}
+
+// Run competes for the leadership lease until the context is done, at which point leadership is relinquished
+func (e *Elector) Run(ctx context.Context) {
+	log.Infof("Replica %s is joining leader election", e.id)
+	ticker := time.NewTicker(e.renewInterval)
+	defer ticker.Stop()
+	for {
+		e.tryAcquireOrRenew(ctx)
+		select {
+		case <-ctx.Done():
+			e.release()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Attempts to acquire the lease if it is free or expired, or to renew it if held by this replica
+func (e *Elector) tryAcquireOrRenew(ctx context.Context) {
+	now := time.Now()
+	lease, err := e.store.Get(ctx)
+	if err == nil {
+		newLease := &Lease{Holder: e.id, Expires: now.Add(e.leaseDuration)}
+		switch {
+		case lease == nil:
+			err = e.store.Create(ctx, newLease)
+		case lease.Holder == e.id || lease.Expired(now):
+			if lease.Holder != e.id {
+				log.Infof("Lease of %s has expired; taking over", lease.Holder)
+			}
+			newLease.Revision = lease.Revision
+			err = e.store.Update(ctx, newLease)
+		default:
+			// Somebody else holds the lease
+			e.setLeading(false, time.Time{})
+			return
+		}
+		if err == nil {
+			e.setLeading(true, newLease.Expires.Add(-e.renewInterval/2))
+			return
+		}
+	}
+
+	if errors.IsAlreadyExists(err) || errors.IsConflict(err) {
+		// Lost the race to another replica
+		e.setLeading(false, time.Time{})
+		return
+	}
+
+	// If the lease store is not reachable, keep leading only until the step-down deadline ahead of our lease expiry
+	log.Warnf("Unable to acquire or renew leadership lease: %+v", err)
+	e.stepDownIfDue()
+}
+
+// Relinquishes leadership, expiring the lease right away so that a standby replica can take over without delay
+func (e *Elector) release() {
+	if e.IsLeader() {
+		ctx, cancel := context.WithTimeout(context.Background(), e.renewInterval)
+		defer cancel()
+		if lease, err := e.store.Get(ctx); err == nil && lease != nil && lease.Holder == e.id {
+			lease.Expires = time.Now()
+			if err = e.store.Update(ctx, lease); err != nil {
+				log.Warnf("Unable to release leadership lease: %+v", err)
+			}
+		}
+	}
+	e.setLeading(false, time.Time{})
+}
+
+// Records the leadership state and invokes the callbacks on transitions; while leading, arms a timer which steps
+// down once the given deadline passes without the lease having been renewed
+func (e *Elector) setLeading(leading bool, deadline time.Time) {
+	e.transitions.Lock()
+	defer e.transitions.Unlock()
+	e.lock.Lock()
+	wasLeading := e.leading
+	e.leading = leading
+	e.deadline = deadline
+	if e.stepDown != nil {
+		e.stepDown.Stop()
+		e.stepDown = nil
+	}
+	if leading {
+		e.stepDown = time.AfterFunc(time.Until(deadline), e.stepDownIfDue)
+	}
+	e.lock.Unlock()
+	e.notify(leading, wasLeading)
+}
+
+// Steps down if leading past the deadline, i.e. if the lease has not been renewed in time
+func (e *Elector) stepDownIfDue() {
+	e.transitions.Lock()
+	defer e.transitions.Unlock()
+	e.lock.Lock()
+	if !e.leading || time.Now().Before(e.deadline) {
+		e.lock.Unlock()
+		return
+	}
+	e.leading = false
+	e.deadline = time.Time{}
+	e.stepDown = nil
+	e.lock.Unlock()
+	log.Warnf("Leadership lease of %s has not been renewed in time; stepping down", e.id)
+	e.notify(false, true)
+}
+
+// Invokes the callbacks on leadership transitions; must be called with the transitions lock held
+func (e *Elector) notify(leading bool, wasLeading bool) {
+	if leading && !wasLeading {
+		log.Infof("Replica %s is now the leader", e.id)
+		if e.callbacks.OnStartedLeading != nil {
+			e.callbacks.OnStartedLeading()
+		}
+	} else if !leading && wasLeading {
+		log.Infof("Replica %s is no longer the leader", e.id)
+		if e.callbacks.OnStoppedLeading != nil {
+			e.callbacks.OnStoppedLeading()
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package election
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestFileLeaseStore(t *testing.T) {
+	ctx := context.TODO()
+	store := NewFileLeaseStore(filepath.Join(t.TempDir(), "lease.json"))
+
+	lease, err := store.Get(ctx)
+	assert.NoError(t, err)
+	assert.Nil(t, lease)
+
+	expires := time.Now().Add(time.Minute)
+	assert.NoError(t, store.Create(ctx, &Lease{Holder: "a", Expires: expires}))
+	assert.True(t, errors.IsAlreadyExists(store.Create(ctx, &Lease{Holder: "b", Expires: expires})))
+
+	lease, err = store.Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", lease.Holder)
+	assert.True(t, expires.Equal(lease.Expires))
+	assert.Equal(t, uint64(1), lease.Revision)
+
+	// Updates based on a stale revision are rejected
+	assert.NoError(t, store.Update(ctx, &Lease{Holder: "a", Expires: expires, Revision: 1}))
+	assert.True(t, errors.IsConflict(store.Update(ctx, &Lease{Holder: "b", Expires: expires, Revision: 1})))
+	lease, err = store.Get(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", lease.Holder)
+	assert.Equal(t, uint64(2), lease.Revision)
+}
+
+// Lease store which can be partitioned away from its replica
+type partitionedStore struct {
+	LeaseStore
+	partitioned atomic.Bool
+}
+
+func (s *partitionedStore) Get(ctx context.Context) (*Lease, error) {
+	if s.partitioned.Load() {
+		return nil, errors.NewUnavailable("partitioned")
+	}
+	return s.LeaseStore.Get(ctx)
+}
+
+// Elector tracking the number of leadership transitions and whether it ever started leading alongside its peer
+type testElector struct {
+	*Elector
+	started    atomic.Int32
+	stopped    atomic.Int32
+	peer       *testElector
+	overlapped atomic.Bool
+}
+
+func newTestElector(id string, store LeaseStore, leaseDuration time.Duration) *testElector {
+	e := &testElector{}
+	e.Elector = NewElector(id, store, leaseDuration, Callbacks{
+		OnStartedLeading: func() {
+			e.started.Add(1)
+			if e.peer != nil && e.peer.IsLeader() {
+				e.overlapped.Store(true)
+			}
+		},
+		OnStoppedLeading: func() { e.stopped.Add(1) },
+	})
+	return e
+}
+
+func TestElector(t *testing.T) {
+	const leaseDuration = 300 * time.Millisecond
+	// Standby replicas take over within the lease duration plus the renew interval; allow for some slack
+	const takeover = leaseDuration + leaseDuration/3 + 200*time.Millisecond
+
+	path := filepath.Join(t.TempDir(), "lease.json")
+	storeA := &partitionedStore{LeaseStore: NewFileLeaseStore(path)}
+	a := newTestElector("a", storeA, leaseDuration)
+	b := newTestElector("b", NewFileLeaseStore(path), leaseDuration)
+	b.peer = a
+
+	ctxA, cancelA := context.WithCancel(context.Background())
+	defer cancelA()
+	go a.Run(ctxA)
+	assert.Eventually(t, a.IsLeader, time.Second, 10*time.Millisecond)
+
+	ctxB, cancelB := context.WithCancel(context.Background())
+	go b.Run(ctxB)
+
+	// The standby does not take over while the leader keeps renewing its lease
+	time.Sleep(2 * leaseDuration)
+	assert.True(t, a.IsLeader())
+	assert.False(t, b.IsLeader())
+
+	// Once the leader can no longer renew, it steps down ahead of its lease expiry and the standby takes over after
+	// the lease expires
+	storeA.partitioned.Store(true)
+	assert.Eventually(t, func() bool { return !a.IsLeader() }, takeover, 10*time.Millisecond)
+	assert.Eventually(t, b.IsLeader, takeover, 10*time.Millisecond)
+	assert.False(t, b.overlapped.Load())
+	assert.Equal(t, int32(1), a.started.Load())
+	assert.Equal(t, int32(1), a.stopped.Load())
+
+	// The healed replica stays on standby
+	storeA.partitioned.Store(false)
+	time.Sleep(leaseDuration)
+	assert.False(t, a.IsLeader())
+	assert.True(t, b.IsLeader())
+
+	// A leader which stops gracefully releases the lease right away
+	start := time.Now()
+	cancelB()
+	assert.Eventually(t, a.IsLeader, takeover, 10*time.Millisecond)
+	assert.Less(t, time.Since(start), leaseDuration)
+	assert.Equal(t, int32(1), b.stopped.Load())
+}
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package election
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	lockRetryPause = 10 * time.Millisecond
+	// Lock files older than this are left behind by crashed processes
+	staleLockAge = 10 * time.Second
+)
+
+// Implementation of LeaseStore keeping the lease in a local JSON file; a stand-in for onos-topo for testing and
+// for replicas sharing a single host. Updates are serialized via an exclusively created lock file.
+type fileLeaseStore struct {
+	path string
+	lock sync.Mutex
+}
+
+// NewFileLeaseStore creates a lease store backed by the specified file
+func NewFileLeaseStore(path string) LeaseStore {
+	return &fileLeaseStore{path: path}
+}
+
+func (s *fileLeaseStore) Get(ctx context.Context) (*Lease, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	lease := &Lease{}
+	if err = json.Unmarshal(data, lease); err != nil {
+		return nil, errors.NewInvalid("lease file %s is corrupt: %v", s.path, err)
+	}
+	return lease, nil
+}
+
+func (s *fileLeaseStore) Create(ctx context.Context, lease *Lease) error {
+	return s.update(ctx, func(current *Lease) error {
+		if current != nil {
+			return errors.NewAlreadyExists("lease is held by %s", current.Holder)
+		}
+		return nil
+	}, lease)
+}
+
+func (s *fileLeaseStore) Update(ctx context.Context, lease *Lease) error {
+	return s.update(ctx, func(current *Lease) error {
+		if current == nil {
+			return errors.NewNotFound("lease does not exist")
+		}
+		if current.Revision != lease.Revision {
+			return errors.NewConflict("lease has been updated by %s", current.Holder)
+		}
+		return nil
+	}, lease)
+}
+
+// Writes the lease with the next revision if the check of the current lease passes, while holding the lock file
+func (s *fileLeaseStore) update(ctx context.Context, check func(current *Lease) error, lease *Lease) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	unlock, err := s.lockFile(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	current, err := s.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if err = check(current); err != nil {
+		return err
+	}
+	next := *lease
+	next.Revision = 1
+	if current != nil {
+		next.Revision = current.Revision + 1
+	}
+	data, err := json.Marshal(&next)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so that readers never see a partially written lease
+	tmp := filepath.Join(filepath.Dir(s.path), "."+filepath.Base(s.path)+".tmp")
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp, s.path); err != nil {
+		return err
+	}
+	lease.Revision = next.Revision
+	return nil
+}
+
+// Acquires the lock file, waiting for other processes to release it; returns a function which releases it
+func (s *fileLeaseStore) lockFile(ctx context.Context) (func(), error) {
+	lockPath := s.path + ".lock"
+	for {
+		f, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		if info, err := os.Stat(lockPath); err == nil && time.Since(info.ModTime()) > staleLockAge {
+			log.Warnf("Removing stale lease lock file %s", lockPath)
+			_ = os.Remove(lockPath)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, errors.NewTimeout("unable to lock lease file %s", s.path)
+		case <-time.After(lockRetryPause):
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package election
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"time"
+)
+
+const (
+	leaseKind    = "lease"
+	holderLabel  = "holder"
+	expiresLabel = "expires"
+)
+
+// Implementation of LeaseStore keeping the lease as the labels of an entity in onos-topo; concurrent updates are
+// detected via the entity revision
+type topoLeaseStore struct {
+	client topo.TopoClient
+	id     topo.ID
+}
+
+// NewTopoLeaseStore creates a lease store backed by the onos-topo entity with the given ID
+func NewTopoLeaseStore(client topo.TopoClient, id topo.ID) LeaseStore {
+	return &topoLeaseStore{client: client, id: id}
+}
+
+func (s *topoLeaseStore) Get(ctx context.Context) (*Lease, error) {
+	resp, err := s.client.Get(ctx, &topo.GetRequest{ID: s.id})
+	if err != nil {
+		if err = errors.FromGRPC(err); errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	lease := &Lease{Holder: resp.Object.Labels[holderLabel], Revision: uint64(resp.Object.Revision)}
+	if lease.Expires, err = time.Parse(time.RFC3339Nano, resp.Object.Labels[expiresLabel]); err != nil {
+		return nil, errors.NewInvalid("lease %s has invalid expiration: %v", s.id, err)
+	}
+	return lease, nil
+}
+
+func (s *topoLeaseStore) Create(ctx context.Context, lease *Lease) error {
+	_, err := s.client.Create(ctx, &topo.CreateRequest{Object: s.object(lease)})
+	return errors.FromGRPC(err)
+}
+
+func (s *topoLeaseStore) Update(ctx context.Context, lease *Lease) error {
+	object := s.object(lease)
+	object.Revision = topo.Revision(lease.Revision)
+	_, err := s.client.Update(ctx, &topo.UpdateRequest{Object: object})
+	return errors.FromGRPC(err)
+}
+
+// Produces the lease entity
+func (s *topoLeaseStore) object(lease *Lease) *topo.Object {
+	object := topo.NewEntity(s.id, leaseKind)
+	object.Labels = map[string]string{
+		holderLabel:  lease.Holder,
+		expiresLabel: lease.Expires.UTC().Format(time.RFC3339Nano),
+	}
+	return object
+}
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	api "github.com/onosproject/onos-api/go/onos/discovery"
+)
+
+// Status represents the outcome of adding a single inventory item
+type Status string
+
+const (
+	// OK indicates that the item was added or already existed in the requested form
+	OK Status = "OK"
+	// Failed indicates that adding the item failed
+	Failed Status = "FAILED"
+	// Skipped indicates that the item was not added because its parent item failed
+	Skipped Status = "SKIPPED"
+)
+
+// Result captures the outcome of adding a single inventory item
+type Result struct {
+	Kind    string `yaml:"kind" json:"kind"`
+	ID      string `yaml:"id" json:"id"`
+	Status  Status `yaml:"status" json:"status"`
+	Message string `yaml:"message,omitempty" json:"message,omitempty"`
+}
+
+// Report lists the outcomes of adding all inventory items, in the order they were processed
+type Report struct {
+	Results []*Result `yaml:"results" json:"results"`
+}
+
+// Failures returns the number of items that failed or were skipped
+func (r *Report) Failures() int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status != OK {
+			count++
+		}
+	}
+	return count
+}
+
+// Adder is an abstraction of an entity capable of adding the fabric assets
+type Adder interface {
+	AddPod(ctx context.Context, req *api.AddPodRequest) error
+	AddRack(ctx context.Context, req *api.AddRackRequest) error
+	AddSwitch(ctx context.Context, req *api.AddSwitchRequest) error
+	AddServerIPU(ctx context.Context, req *api.AddServerIPURequest) error
+}
+
+// Apply adds all inventory items using the given adder, top-down; items whose parent failed are skipped.
+// The inventory is expected to have been validated.
+func (inv *Inventory) Apply(ctx context.Context, adder Adder) *Report {
+	report := &Report{Results: make([]*Result, 0)}
+	for _, pod := range inv.Pods {
+		podOK := report.add("pod", pod.ID, true, func() error {
+			return adder.AddPod(ctx, &api.AddPodRequest{ID: pod.ID})
+		})
+		for _, rack := range pod.Racks {
+			rackOK := report.add("rack", rack.ID, podOK, func() error {
+				return adder.AddRack(ctx, &api.AddRackRequest{ID: rack.ID, PodID: pod.ID})
+			})
+			for _, device := range rack.Switches {
+				report.add("switch", device.ID, rackOK, func() error {
+					return adder.AddSwitch(ctx, &api.AddSwitchRequest{
+						ID: device.ID, PodID: pod.ID, RackID: rack.ID, ManagementInfo: device.ManagementInfo.ToAPI(),
+					})
+				})
+			}
+			for _, device := range rack.Servers {
+				report.add("server", device.ID, rackOK, func() error {
+					return adder.AddServerIPU(ctx, &api.AddServerIPURequest{
+						ID: device.ID, PodID: pod.ID, RackID: rack.ID, ManagementInfo: device.ManagementInfo.ToAPI(),
+					})
+				})
+			}
+		}
+	}
+	return report
+}
+
+// Runs the add function, if the parent was added successfully, and records the outcome; returns true on success
+func (r *Report) add(kind string, id string, parentOK bool, add func() error) bool {
+	result := &Result{Kind: kind, ID: id, Status: OK}
+	r.Results = append(r.Results, result)
+	if !parentOK {
+		result.Status = Skipped
+		result.Message = "parent was not added"
+		return false
+	}
+	if err := add(); err != nil {
+		result.Status = Failed
+		result.Message = err.Error()
+		return false
+	}
+	return true
+}
@@ -0,0 +1,196 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"fmt"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"os"
+	"sort"
+	"strings"
+)
+
+// CablingStatus indicates how a link relates to the expected cabling plan
+type CablingStatus string
+
+const (
+	// CablingOK indicates that the link is expected by the cabling plan
+	CablingOK CablingStatus = "ok"
+	// CablingMissing indicates that a link expected by the cabling plan has not been discovered or is down
+	CablingMissing CablingStatus = "missing"
+	// CablingExtra indicates that the link connects ports which are not part of the cabling plan
+	CablingExtra CablingStatus = "extra"
+	// CablingMiswired indicates that the link connects ports which the cabling plan expects to be connected elsewhere
+	CablingMiswired CablingStatus = "miswired"
+)
+
+// Cable describes an expected port-to-port connection, using the topology port IDs, i.e. <device>/<port-number>.
+// Unless marked as unidirectional, links are expected in both directions.
+type Cable struct {
+	Source         string `yaml:"src" json:"src"`
+	Target         string `yaml:"tgt" json:"tgt"`
+	Unidirectional bool   `yaml:"unidirectional,omitempty" json:"unidirectional,omitempty"`
+}
+
+// CablingPlan describes how the fabric is expected to be cabled. Its format is compatible with the links section
+// of the fabric simulator topology files, so such files can be used directly as cabling plans; their simulator
+// port numbers are translated to the SDN port numbers used by the topology port IDs.
+type CablingPlan struct {
+	Cables []*Cable `yaml:"links" json:"links"`
+
+	// Expected ingress port keyed by egress port, and vice versa
+	bySource map[string]string
+	byTarget map[string]string
+}
+
+// LoadCablingPlan reads and parses the cabling plan from the specified YAML or JSON file
+func LoadCablingPlan(path string) (*CablingPlan, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCablingPlan(data)
+}
+
+// ParseCablingPlan parses and validates the cabling plan from the given YAML or JSON document. Fields other than
+// the links are ignored, except for the ports of the devices of a fabric simulator topology, whose SDN port numbers
+// replace the simulator port numbers of the links.
+func ParseCablingPlan(data []byte) (*CablingPlan, error) {
+	plan := &CablingPlan{}
+	if err := yaml.Unmarshal(data, plan); err != nil {
+		return nil, errors.NewInvalid("unable to parse cabling plan: %v", err)
+	}
+	topology := &fabricSimTopology{}
+	if err := yaml.Unmarshal(data, topology); err != nil {
+		return nil, errors.NewInvalid("unable to parse cabling plan: %v", err)
+	}
+	portIDs := topology.portIDs()
+	for _, cable := range plan.Cables {
+		if id, ok := portIDs[cable.Source]; ok {
+			cable.Source = id
+		}
+		if id, ok := portIDs[cable.Target]; ok {
+			cable.Target = id
+		}
+	}
+	if err := plan.index(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// NewCablingPlan creates a cabling plan from the given cables, validating that no port is cabled twice
+func NewCablingPlan(cables ...*Cable) (*CablingPlan, error) {
+	plan := &CablingPlan{Cables: cables}
+	if err := plan.index(); err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+// Indexes the expected links of all cables, checking for ports used by more than one cable
+func (p *CablingPlan) index() error {
+	p.bySource = make(map[string]string)
+	p.byTarget = make(map[string]string)
+	problems := make([]string, 0)
+	expect := func(src string, tgt string) {
+		if other, ok := p.bySource[src]; ok {
+			problems = append(problems, fmt.Sprintf("port %s is already cabled to %s", src, other))
+			return
+		}
+		if other, ok := p.byTarget[tgt]; ok {
+			problems = append(problems, fmt.Sprintf("port %s is already cabled to %s", tgt, other))
+			return
+		}
+		p.bySource[src] = tgt
+		p.byTarget[tgt] = src
+	}
+	for _, cable := range p.Cables {
+		if len(cable.Source) == 0 || len(cable.Target) == 0 {
+			problems = append(problems, "cable with missing src or tgt port")
+			continue
+		}
+		expect(cable.Source, cable.Target)
+		if !cable.Unidirectional {
+			expect(cable.Target, cable.Source)
+		}
+	}
+	if len(problems) > 0 {
+		return errors.NewInvalid("invalid cabling plan: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Check returns the cabling status of a link from the given egress port to the given ingress port, along with the
+// port at which the link was expected to terminate, if it is miswired
+func (p *CablingPlan) Check(src string, tgt string) (CablingStatus, string) {
+	expectedTgt, srcCabled := p.bySource[src]
+	switch {
+	case srcCabled && expectedTgt == tgt:
+		return CablingOK, ""
+	case srcCabled:
+		return CablingMiswired, expectedTgt
+	default:
+		if _, tgtCabled := p.byTarget[tgt]; tgtCabled {
+			return CablingMiswired, ""
+		}
+		return CablingExtra, ""
+	}
+}
+
+// CablingResult describes how a single discovered or expected link relates to the cabling plan
+type CablingResult struct {
+	Source   string        `yaml:"src" json:"src"`
+	Target   string        `yaml:"tgt" json:"tgt"`
+	Status   CablingStatus `yaml:"status" json:"status"`
+	LinkID   string        `yaml:"link_id,omitempty" json:"link_id,omitempty"`
+	Expected string        `yaml:"expected,omitempty" json:"expected,omitempty"`
+}
+
+// CablingReport lists the results of verifying the discovered links against the cabling plan
+type CablingReport struct {
+	Results []*CablingResult `yaml:"results" json:"results"`
+}
+
+// Count returns the number of results with the given status
+func (r *CablingReport) Count(status CablingStatus) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Verify checks the given discovered links against the cabling plan. Expected links that have not been
+// discovered, or which are not up, are reported as missing.
+func (p *CablingPlan) Verify(links []*Link, upStatus string) *CablingReport {
+	report := &CablingReport{Results: make([]*CablingResult, 0, len(links))}
+	found := make(map[string]bool)
+	for _, link := range links {
+		status, expected := p.Check(link.Source, link.Target)
+		if status == CablingOK {
+			if link.Status != upStatus {
+				continue
+			}
+			found[link.Source] = true
+		}
+		report.Results = append(report.Results, &CablingResult{
+			Source: link.Source, Target: link.Target, Status: status, LinkID: link.ID, Expected: expected,
+		})
+	}
+	for src, tgt := range p.bySource {
+		if !found[src] {
+			report.Results = append(report.Results, &CablingResult{Source: src, Target: tgt, Status: CablingMissing})
+		}
+	}
+	sort.Slice(report.Results, func(i, j int) bool {
+		a, b := report.Results[i], report.Results[j]
+		return a.Source < b.Source || (a.Source == b.Source && a.Target < b.Target)
+	})
+	return report
+}
@@ -0,0 +1,54 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestCablingPlan(t *testing.T) {
+	// Fabric simulator topology files double as cabling plans, with ports identified by their SDN port numbers
+	plan, err := ParseCablingPlan([]byte(fabricSimYAML))
+	assert.NoError(t, err)
+	assert.Len(t, plan.Cables, 1)
+	assert.Equal(t, &Cable{Source: "spine1/201", Target: "leaf1/1"}, plan.Cables[0])
+
+	status, _ := plan.Check("spine1/201", "leaf1/1")
+	assert.Equal(t, CablingOK, status)
+	status, _ = plan.Check("leaf1/1", "spine1/201")
+	assert.Equal(t, CablingOK, status)
+	status, expected := plan.Check("spine1/201", "leaf1/2")
+	assert.Equal(t, CablingMiswired, status)
+	assert.Equal(t, "leaf1/1", expected)
+	status, _ = plan.Check("leaf1/3", "spine1/201")
+	assert.Equal(t, CablingMiswired, status)
+	status, _ = plan.Check("spine1/1", "leaf1/3")
+	assert.Equal(t, CablingExtra, status)
+	status, _ = plan.Check("leaf1/3", "spine1/3")
+	assert.Equal(t, CablingExtra, status)
+
+	_, err = NewCablingPlan(&Cable{Source: "a/1", Target: "b/1"}, &Cable{Source: "a/1", Target: "c/1"})
+	assert.True(t, errors.IsInvalid(err))
+}
+
+func TestCablingVerify(t *testing.T) {
+	plan, err := NewCablingPlan(&Cable{Source: "a/1", Target: "b/1"}, &Cable{Source: "a/2", Target: "c/1", Unidirectional: true})
+	assert.NoError(t, err)
+
+	report := plan.Verify([]*Link{
+		{ID: "a/1-b/1", Source: "a/1", Target: "b/1", Status: "UP"},
+		{ID: "b/1-a/1", Source: "b/1", Target: "a/1", Status: "DOWN"},
+		{ID: "a/2-c/2", Source: "a/2", Target: "c/2", Status: "UP"},
+		{ID: "d/1-e/1", Source: "d/1", Target: "e/1", Status: "UP"},
+	}, "UP")
+	assert.Equal(t, 1, report.Count(CablingOK))
+	assert.Equal(t, 2, report.Count(CablingMissing))
+	assert.Equal(t, 1, report.Count(CablingMiswired))
+	assert.Equal(t, 1, report.Count(CablingExtra))
+	assert.Equal(t, &CablingResult{Source: "a/2", Target: "c/2", Status: CablingMiswired, LinkID: "a/2-c/2", Expected: "c/1"},
+		report.Results[2])
+}
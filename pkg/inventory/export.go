@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"sort"
+)
+
+// Supported document formats
+const (
+	YAML = "yaml"
+	JSON = "json"
+)
+
+// Topology is a declarative snapshot of the discovered fabric topology. Its inventory portion can be imported
+// as-is to re-seed the topology, while the ports, links and hosts capture what has been discovered.
+type Topology struct {
+	Inventory `yaml:",inline"`
+	Ports     []*Port `yaml:"ports,omitempty" json:"ports,omitempty"`
+	Links     []*Link `yaml:"links,omitempty" json:"links,omitempty"`
+	Hosts     []*Host `yaml:"hosts,omitempty" json:"hosts,omitempty"`
+}
+
+// Port describes a discovered switch or IPU port
+type Port struct {
+	ID          string `yaml:"id" json:"id"`
+	Device      string `yaml:"device" json:"device"`
+	Number      uint32 `yaml:"number" json:"number"`
+	DisplayName string `yaml:"display_name,omitempty" json:"display_name,omitempty"`
+	Speed       string `yaml:"speed,omitempty" json:"speed,omitempty"`
+	Enabled     bool   `yaml:"enabled" json:"enabled"`
+	Status      string `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// Link describes a discovered unidirectional link between an egress and an ingress port
+type Link struct {
+	ID     string `yaml:"id" json:"id"`
+	Source string `yaml:"source" json:"source"`
+	Target string `yaml:"target" json:"target"`
+	Status string `yaml:"status,omitempty" json:"status,omitempty"`
+}
+
+// Host describes a discovered host and the port it is connected to, along with its primary and all its IP addresses
+type Host struct {
+	ID   string   `yaml:"id" json:"id"`
+	Port string   `yaml:"port" json:"port"`
+	MAC  string   `yaml:"mac,omitempty" json:"mac,omitempty"`
+	IP   string   `yaml:"ip,omitempty" json:"ip,omitempty"`
+	IPs  []string `yaml:"ips,omitempty" json:"ips,omitempty"`
+}
+
+// Sort orders all items of the topology by their IDs, so that the same topology always yields the same document
+func (t *Topology) Sort() {
+	sort.Slice(t.Pods, func(i, j int) bool { return t.Pods[i].ID < t.Pods[j].ID })
+	for _, pod := range t.Pods {
+		sort.Slice(pod.Racks, func(i, j int) bool { return pod.Racks[i].ID < pod.Racks[j].ID })
+		for _, rack := range pod.Racks {
+			sort.Slice(rack.Switches, func(i, j int) bool { return rack.Switches[i].ID < rack.Switches[j].ID })
+			sort.Slice(rack.Servers, func(i, j int) bool { return rack.Servers[i].ID < rack.Servers[j].ID })
+		}
+	}
+	sort.Slice(t.Ports, func(i, j int) bool { return t.Ports[i].ID < t.Ports[j].ID })
+	sort.Slice(t.Links, func(i, j int) bool { return t.Links[i].ID < t.Links[j].ID })
+	sort.Slice(t.Hosts, func(i, j int) bool { return t.Hosts[i].ID < t.Hosts[j].ID })
+}
+
+// Encode sorts the topology and renders it as a document in the given format; YAML is used if none is given
+func (t *Topology) Encode(format string) ([]byte, error) {
+	t.Sort()
+	switch format {
+	case "", YAML:
+		buf := &bytes.Buffer{}
+		encoder := yaml.NewEncoder(buf)
+		encoder.SetIndent(2)
+		if err := encoder.Encode(t); err != nil {
+			return nil, err
+		}
+		if err := encoder.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case JSON:
+		return json.MarshalIndent(t, "", "  ")
+	default:
+		return nil, errors.NewInvalid("unsupported format %q", format)
+	}
+}
@@ -0,0 +1,199 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strings"
+	"text/template"
+)
+
+// Device types used by the fabric simulator topology files
+const (
+	fabricSimSwitch = "switch"
+	fabricSimIPU    = "ipu"
+)
+
+// Subset of the fabric simulator topology file needed to derive the inventory
+type fabricSimTopology struct {
+	Devices []*fabricSimDevice `yaml:"devices"`
+}
+
+type fabricSimDevice struct {
+	ID        string           `yaml:"id"`
+	ChassisID uint64           `yaml:"chassis_id"`
+	Type      string           `yaml:"type"`
+	AgentPort int32            `yaml:"agent_port"`
+	Ports     []*fabricSimPort `yaml:"ports,omitempty"`
+}
+
+// The simulator port number along with the SDN port number, by which the port is known in the topology
+type fabricSimPort struct {
+	Number    uint32 `yaml:"number"`
+	SDNNumber uint32 `yaml:"sdn_number"`
+}
+
+// Returns the topology port IDs, i.e. <device>/<sdn-number>, keyed by the simulator port IDs, i.e.
+// <device>/<number>, for the ports with an SDN port number
+func (t *fabricSimTopology) portIDs() map[string]string {
+	ids := make(map[string]string)
+	for _, device := range t.Devices {
+		for _, port := range device.Ports {
+			if port.SDNNumber != 0 {
+				ids[fmt.Sprintf("%s/%d", device.ID, port.Number)] = fmt.Sprintf("%s/%d", device.ID, port.SDNNumber)
+			}
+		}
+	}
+	return ids
+}
+
+// FabricSimTemplate describes how to derive the inventory for devices of a fabric simulator topology.
+// All string fields of the management info are Go templates, evaluated for each device against
+// FabricSimTemplateData; e.g. "fabric-sim:{{.AgentPort}}" or "discovery-agent-{{.Index}}.discovery-agent:30000".
+type FabricSimTemplate struct {
+	Pod            string            `yaml:"pod"`
+	Rack           string            `yaml:"rack"`
+	Racks          map[string]string `yaml:"racks,omitempty"`
+	ManagementInfo *ManagementInfo   `yaml:"management_info"`
+}
+
+// FabricSimTemplateData is the per-device data available to the fabric simulator template
+type FabricSimTemplateData struct {
+	ID        string
+	Index     int
+	Type      string
+	ChassisID uint64
+	AgentPort int32
+}
+
+// LoadFabricSim reads the fabric simulator topology file and the template file and produces the inventory
+func LoadFabricSim(topologyPath string, templatePath string) (*Inventory, error) {
+	topologyData, err := os.ReadFile(topologyPath)
+	if err != nil {
+		return nil, err
+	}
+	templateData, err := os.ReadFile(templatePath)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &FabricSimTemplate{}
+	if err = yaml.Unmarshal(templateData, tmpl); err != nil {
+		return nil, errors.NewInvalid("unable to parse fabric simulator template: %v", err)
+	}
+	return FromFabricSim(topologyData, tmpl)
+}
+
+// FromFabricSim produces the inventory of the switches and IPUs in the given fabric simulator topology, using
+// the template to derive their pod, rack and management info. Devices are placed into the template rack unless
+// the template overrides their rack explicitly. IPU devices become server IPUs; the server ID is derived by
+// trimming the "-IPU" suffix from the IPU device ID, if present.
+func FromFabricSim(topologyData []byte, tmpl *FabricSimTemplate) (*Inventory, error) {
+	topology := &fabricSimTopology{}
+	if err := yaml.Unmarshal(topologyData, topology); err != nil {
+		return nil, errors.NewInvalid("unable to parse fabric simulator topology: %v", err)
+	}
+	if len(tmpl.Pod) == 0 {
+		return nil, errors.NewInvalid("fabric simulator template must specify a pod")
+	}
+	if tmpl.ManagementInfo == nil {
+		return nil, errors.NewInvalid("fabric simulator template must specify management info")
+	}
+
+	pod := &Pod{ID: tmpl.Pod, Racks: make([]*Rack, 0)}
+	racks := make(map[string]*Rack)
+	getRack := func(deviceID string) (*Rack, error) {
+		rackID, ok := tmpl.Racks[deviceID]
+		if !ok {
+			rackID = tmpl.Rack
+		}
+		if len(rackID) == 0 {
+			return nil, errors.NewInvalid("no rack specified for device %s", deviceID)
+		}
+		rack, ok := racks[rackID]
+		if !ok {
+			rack = &Rack{ID: rackID}
+			racks[rackID] = rack
+			pod.Racks = append(pod.Racks, rack)
+		}
+		return rack, nil
+	}
+
+	for index, device := range topology.Devices {
+		if device.Type != fabricSimSwitch && device.Type != fabricSimIPU {
+			continue
+		}
+		info, err := tmpl.managementInfo(&FabricSimTemplateData{
+			ID:        device.ID,
+			Index:     index,
+			Type:      device.Type,
+			ChassisID: device.ChassisID,
+			AgentPort: device.AgentPort,
+		})
+		if err != nil {
+			return nil, err
+		}
+		rack, err := getRack(device.ID)
+		if err != nil {
+			return nil, err
+		}
+		if device.Type == fabricSimSwitch {
+			rack.Switches = append(rack.Switches, &Device{ID: device.ID, ManagementInfo: info})
+		} else {
+			serverID := strings.TrimSuffix(device.ID, "-IPU")
+			rack.Servers = append(rack.Servers, &Device{ID: serverID, ManagementInfo: info})
+		}
+	}
+	return &Inventory{Pods: []*Pod{pod}}, nil
+}
+
+// Evaluates the management info template for the given device; the device ID defaults to the chassis ID
+func (t *FabricSimTemplate) managementInfo(data *FabricSimTemplateData) (*ManagementInfo, error) {
+	info := &ManagementInfo{DeviceID: t.ManagementInfo.DeviceID}
+	if info.DeviceID == 0 {
+		info.DeviceID = data.ChassisID
+	}
+	fields := []struct {
+		value  string
+		target *string
+	}{
+		{t.ManagementInfo.P4RTEndpoint, &info.P4RTEndpoint},
+		{t.ManagementInfo.GNMIEndpoint, &info.GNMIEndpoint},
+		{t.ManagementInfo.ChassisConfigID, &info.ChassisConfigID},
+		{t.ManagementInfo.PipelineConfigID, &info.PipelineConfigID},
+		{t.ManagementInfo.LinkAgentEndpoint, &info.LinkAgentEndpoint},
+		{t.ManagementInfo.HostAgentEndpoint, &info.HostAgentEndpoint},
+		{t.ManagementInfo.NATAgentEndpoint, &info.NATAgentEndpoint},
+		{t.ManagementInfo.Realm, &info.Realm},
+		{t.ManagementInfo.Role, &info.Role},
+	}
+	for _, field := range fields {
+		value, err := evaluate(field.value, data)
+		if err != nil {
+			return nil, err
+		}
+		*field.target = value
+	}
+	return info, nil
+}
+
+// Evaluates the given template text against the device data
+func evaluate(text string, data *FabricSimTemplateData) (string, error) {
+	if !strings.Contains(text, "{{") {
+		return text, nil
+	}
+	tmpl, err := template.New(data.ID).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", errors.NewInvalid("invalid template %q: %v", text, err)
+	}
+	buf := &bytes.Buffer{}
+	if err = tmpl.Execute(buf, data); err != nil {
+		return "", errors.NewInvalid("unable to evaluate template %q for %s: %v", text, data.ID, err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,85 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const fabricSimYAML = `
+devices:
+- id: spine1
+  chassis_id: 0
+  type: switch
+  agent_port: 20000
+  ports:
+  - number: 1
+    sdn_number: 201
+    speed: 100GB
+- id: leaf1
+  chassis_id: 1
+  type: switch
+  agent_port: 20001
+- id: server1-IPU
+  chassis_id: 2
+  type: ipu
+  agent_port: 20002
+hosts:
+- id: host0101
+  nics:
+  - mac: 00:ca:fe:01:01:01
+    ip: 10.1.1.1
+    port: leaf1/5
+links:
+- src: spine1/1
+  tgt: leaf1/1
+  unidirectional: false
+`
+
+func TestFromFabricSim(t *testing.T) {
+	tmpl := &FabricSimTemplate{
+		Pod:   "all",
+		Rack:  "rack-01-1",
+		Racks: map[string]string{"server1-IPU": "rack-01-2"},
+		ManagementInfo: &ManagementInfo{
+			P4RTEndpoint:      "fabric-sim:{{.AgentPort}}",
+			GNMIEndpoint:      "fabric-sim:{{.AgentPort}}",
+			LinkAgentEndpoint: "discovery-agent-{{.Index}}.discovery-agent:30000",
+			PipelineConfigID:  "fabric-{{.ID}}-pipeline",
+			Realm:             "pod-01",
+		},
+	}
+	inv, err := FromFabricSim([]byte(fabricSimYAML), tmpl)
+	assert.NoError(t, err)
+	assert.NoError(t, inv.Validate())
+
+	assert.Len(t, inv.Pods, 1)
+	assert.Equal(t, "all", inv.Pods[0].ID)
+	assert.Len(t, inv.Pods[0].Racks, 2)
+
+	rack := inv.Pods[0].Racks[0]
+	assert.Equal(t, "rack-01-1", rack.ID)
+	assert.Len(t, rack.Switches, 2)
+	assert.Len(t, rack.Servers, 0)
+	leaf1 := rack.Switches[1].ManagementInfo
+	assert.Equal(t, "leaf1", rack.Switches[1].ID)
+	assert.Equal(t, "fabric-sim:20001", leaf1.GNMIEndpoint)
+	assert.Equal(t, "fabric-sim:20001", leaf1.P4RTEndpoint)
+	assert.Equal(t, "discovery-agent-1.discovery-agent:30000", leaf1.LinkAgentEndpoint)
+	assert.Equal(t, "fabric-leaf1-pipeline", leaf1.PipelineConfigID)
+	assert.Equal(t, "pod-01", leaf1.Realm)
+	assert.Equal(t, uint64(1), leaf1.DeviceID)
+
+	rack = inv.Pods[0].Racks[1]
+	assert.Equal(t, "rack-01-2", rack.ID)
+	assert.Len(t, rack.Servers, 1)
+	assert.Equal(t, "server1", rack.Servers[0].ID)
+	assert.Equal(t, "fabric-sim:20002", rack.Servers[0].ManagementInfo.GNMIEndpoint)
+
+	tmpl.ManagementInfo.GNMIEndpoint = "fabric-sim:{{.NoSuchField}}"
+	_, err = FromFabricSim([]byte(fabricSimYAML), tmpl)
+	assert.Error(t, err)
+}
@@ -0,0 +1,169 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package inventory implements declarative description of the fabric assets used for seeding the topology
+package inventory
+
+import (
+	"bytes"
+	"fmt"
+	api "github.com/onosproject/onos-api/go/onos/discovery"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"gopkg.in/yaml.v3"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Inventory is a declarative description of the fabric pods, racks, switches and server IPUs
+type Inventory struct {
+	Pods []*Pod `yaml:"pods" json:"pods"`
+}
+
+// Pod describes a POD and the racks it contains
+type Pod struct {
+	ID    string  `yaml:"id" json:"id"`
+	Racks []*Rack `yaml:"racks,omitempty" json:"racks,omitempty"`
+}
+
+// Rack describes a rack and the switches and servers it contains
+type Rack struct {
+	ID       string    `yaml:"id" json:"id"`
+	Switches []*Device `yaml:"switches,omitempty" json:"switches,omitempty"`
+	Servers  []*Device `yaml:"servers,omitempty" json:"servers,omitempty"`
+}
+
+// Device describes a switch or a server with its IPU, along with its management info
+type Device struct {
+	ID             string          `yaml:"id" json:"id"`
+	ManagementInfo *ManagementInfo `yaml:"management_info,omitempty" json:"management_info,omitempty"`
+}
+
+// ManagementInfo describes the agent endpoints and configuration of a switch or IPU
+type ManagementInfo struct {
+	P4RTEndpoint      string `yaml:"p4rt_endpoint,omitempty" json:"p4rt_endpoint,omitempty"`
+	GNMIEndpoint      string `yaml:"gnmi_endpoint,omitempty" json:"gnmi_endpoint,omitempty"`
+	DeviceID          uint64 `yaml:"device_id,omitempty" json:"device_id,omitempty"`
+	ChassisConfigID   string `yaml:"chassis_config_id,omitempty" json:"chassis_config_id,omitempty"`
+	PipelineConfigID  string `yaml:"pipeline_config_id,omitempty" json:"pipeline_config_id,omitempty"`
+	LinkAgentEndpoint string `yaml:"link_agent_endpoint,omitempty" json:"link_agent_endpoint,omitempty"`
+	HostAgentEndpoint string `yaml:"host_agent_endpoint,omitempty" json:"host_agent_endpoint,omitempty"`
+	NATAgentEndpoint  string `yaml:"nat_agent_endpoint,omitempty" json:"nat_agent_endpoint,omitempty"`
+	Realm             string `yaml:"realm,omitempty" json:"realm,omitempty"`
+	Role              string `yaml:"role,omitempty" json:"role,omitempty"`
+}
+
+// Load reads and parses the inventory from the specified YAML or JSON file
+func Load(path string) (*Inventory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(data)
+}
+
+// Parse parses the inventory from the given YAML or JSON document; unknown fields are rejected. Exported topology
+// documents are accepted as well, in which case their discovered ports, links and hosts are ignored.
+func Parse(data []byte) (*Inventory, error) {
+	topology := &Topology{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(topology); err != nil {
+		return nil, errors.NewInvalid("unable to parse inventory: %v", err)
+	}
+	return &topology.Inventory, nil
+}
+
+// Validate checks the entire inventory and returns an error describing all problems found, if any
+func (inv *Inventory) Validate() error {
+	problems := make([]string, 0)
+	ids := make(map[string]string)
+	checkID := func(kind string, id string) {
+		if len(id) == 0 {
+			problems = append(problems, fmt.Sprintf("%s with empty ID", kind))
+			return
+		}
+		if other, ok := ids[id]; ok {
+			problems = append(problems, fmt.Sprintf("%s %s: ID already used by %s", kind, id, other))
+			return
+		}
+		ids[id] = kind
+	}
+
+	for _, pod := range inv.Pods {
+		checkID("pod", pod.ID)
+		for _, rack := range pod.Racks {
+			checkID("rack", rack.ID)
+			for _, device := range rack.Switches {
+				checkID("switch", device.ID)
+				problems = append(problems, device.ManagementInfo.validate("switch", device.ID)...)
+			}
+			for _, device := range rack.Servers {
+				checkID("server", device.ID)
+				checkID("IPU", IPUID(device.ID))
+				problems = append(problems, device.ManagementInfo.validate("server", device.ID)...)
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.NewInvalid("invalid inventory: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Returns a list of problems with the management info of the specified device
+func (m *ManagementInfo) validate(kind string, id string) []string {
+	if m == nil {
+		return []string{fmt.Sprintf("%s %s: missing management info", kind, id)}
+	}
+	problems := make([]string, 0)
+	endpoints := []struct{ name, value string }{
+		{"p4rt_endpoint", m.P4RTEndpoint},
+		{"gnmi_endpoint", m.GNMIEndpoint},
+		{"link_agent_endpoint", m.LinkAgentEndpoint},
+		{"host_agent_endpoint", m.HostAgentEndpoint},
+		{"nat_agent_endpoint", m.NATAgentEndpoint},
+	}
+	for _, ep := range endpoints {
+		if len(ep.value) > 0 && !isValidEndpoint(ep.value) {
+			problems = append(problems, fmt.Sprintf("%s %s: %s %q is not a valid host:port", kind, id, ep.name, ep.value))
+		}
+	}
+	if len(m.GNMIEndpoint) == 0 && len(m.P4RTEndpoint) == 0 {
+		problems = append(problems, fmt.Sprintf("%s %s: neither gnmi_endpoint nor p4rt_endpoint is given", kind, id))
+	}
+	return problems
+}
+
+// Returns true if the given string is in host:port form
+func isValidEndpoint(ep string) bool {
+	fields := strings.Split(ep, ":")
+	if len(fields) != 2 || len(fields[0]) == 0 {
+		return false
+	}
+	port, err := strconv.ParseUint(fields[1], 10, 16)
+	return err == nil && port > 0
+}
+
+// IPUID returns the ID of the IPU entity associated with the given server ID
+func IPUID(serverID string) string {
+	return fmt.Sprintf("%s-IPU", serverID)
+}
+
+// ToAPI converts the management info into its discovery API counterpart
+func (m *ManagementInfo) ToAPI() *api.ManagementInfo {
+	return &api.ManagementInfo{
+		P4RTEndpoint:      m.P4RTEndpoint,
+		GNMIEndpoint:      m.GNMIEndpoint,
+		DeviceID:          m.DeviceID,
+		ChassisConfigID:   m.ChassisConfigID,
+		PipelineConfigID:  m.PipelineConfigID,
+		LinkAgentEndpoint: m.LinkAgentEndpoint,
+		HostAgentEndpoint: m.HostAgentEndpoint,
+		NatAgentEndpoint:  m.NATAgentEndpoint,
+		Realm:             m.Realm,
+		Role:              m.Role,
+	}
+}
@@ -0,0 +1,123 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package inventory
+
+import (
+	"context"
+	api "github.com/onosproject/onos-api/go/onos/discovery"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const inventoryYAML = `
+pods:
+- id: pod1
+  racks:
+  - id: rack1
+    switches:
+    - id: leaf1
+      management_info:
+        gnmi_endpoint: fabric-sim:20000
+        p4rt_endpoint: fabric-sim:20000
+        link_agent_endpoint: discovery-agent-0:30000
+        pipeline_config_id: fabric-leaf
+    servers:
+    - id: server1
+      management_info:
+        gnmi_endpoint: server1-ipu:9339
+        realm: edge
+  - id: rack2
+    switches:
+    - id: leaf2
+      management_info:
+        gnmi_endpoint: fabric-sim:20001
+`
+
+const inventoryJSON = `{"pods": [{"id": "pod1", "racks": [{"id": "rack1", "switches": [
+	{"id": "leaf1", "management_info": {"gnmi_endpoint": "fabric-sim:20000"}}]}]}]}`
+
+func TestParse(t *testing.T) {
+	inv, err := Parse([]byte(inventoryYAML))
+	assert.NoError(t, err)
+	assert.Len(t, inv.Pods, 1)
+	assert.Len(t, inv.Pods[0].Racks, 2)
+	assert.Equal(t, "fabric-leaf", inv.Pods[0].Racks[0].Switches[0].ManagementInfo.PipelineConfigID)
+	assert.Equal(t, "edge", inv.Pods[0].Racks[0].Servers[0].ManagementInfo.Realm)
+	assert.NoError(t, inv.Validate())
+
+	inv, err = Parse([]byte(inventoryJSON))
+	assert.NoError(t, err)
+	assert.Equal(t, "fabric-sim:20000", inv.Pods[0].Racks[0].Switches[0].ManagementInfo.GNMIEndpoint)
+	assert.NoError(t, inv.Validate())
+
+	_, err = Parse([]byte("pods:\n- id: pod1\n  rcks: []\n"))
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	inv := &Inventory{Pods: []*Pod{{ID: "pod1", Racks: []*Rack{{
+		ID: "rack1",
+		Switches: []*Device{
+			{ID: "leaf1", ManagementInfo: &ManagementInfo{GNMIEndpoint: "leaf1"}},
+			{ID: "rack1", ManagementInfo: &ManagementInfo{GNMIEndpoint: "leaf2:9339"}},
+			{ID: "leaf3"},
+		},
+		Servers: []*Device{
+			{ID: "", ManagementInfo: &ManagementInfo{GNMIEndpoint: "server:9339"}},
+		},
+	}}}}}
+	err := inv.Validate()
+	assert.Error(t, err)
+	assert.True(t, errors.IsInvalid(err))
+	assert.Contains(t, err.Error(), "leaf1: gnmi_endpoint")
+	assert.Contains(t, err.Error(), "switch rack1: ID already used by rack")
+	assert.Contains(t, err.Error(), "leaf3: missing management info")
+	assert.Contains(t, err.Error(), "server with empty ID")
+}
+
+type testAdder struct {
+	added   []string
+	failing map[string]bool
+}
+
+func (a *testAdder) add(id string) error {
+	if a.failing[id] {
+		return errors.NewInvalid("%s failed", id)
+	}
+	a.added = append(a.added, id)
+	return nil
+}
+
+func (a *testAdder) AddPod(ctx context.Context, req *api.AddPodRequest) error {
+	return a.add(req.ID)
+}
+
+func (a *testAdder) AddRack(ctx context.Context, req *api.AddRackRequest) error {
+	return a.add(req.ID)
+}
+
+func (a *testAdder) AddSwitch(ctx context.Context, req *api.AddSwitchRequest) error {
+	return a.add(req.ID)
+}
+
+func (a *testAdder) AddServerIPU(ctx context.Context, req *api.AddServerIPURequest) error {
+	return a.add(req.ID)
+}
+
+func TestApply(t *testing.T) {
+	inv, err := Parse([]byte(inventoryYAML))
+	assert.NoError(t, err)
+
+	adder := &testAdder{failing: map[string]bool{"rack1": true}}
+	report := inv.Apply(context.TODO(), adder)
+	assert.Equal(t, []string{"pod1", "rack2", "leaf2"}, adder.added)
+	assert.Len(t, report.Results, 6)
+	assert.Equal(t, 3, report.Failures())
+	assert.Equal(t, Failed, report.Results[1].Status)
+	assert.Equal(t, Skipped, report.Results[2].Status)
+	assert.Equal(t, Skipped, report.Results[3].Status)
+	assert.Equal(t, OK, report.Results[5].Status)
+}
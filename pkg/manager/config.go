@@ -0,0 +1,47 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"github.com/onosproject/topo-discovery/pkg/controller"
+	"os"
+	"time"
+)
+
+const configPollInterval = 5 * time.Second
+
+// Loads the controller configuration file, overlaying it on the configuration given via the command flags, and
+// applies it to the controller
+func (m *Manager) loadConfig() error {
+	cfg, err := controller.LoadConfig(m.Config.ConfigPath, m.Config.ControllerConfig)
+	if err != nil {
+		return err
+	}
+	return m.controller.Configure(cfg)
+}
+
+// Polls the controller configuration file for changes and re-applies it whenever it changes; invalid
+// configurations are rejected, leaving the current configuration in effect
+func (m *Manager) watchConfig(stop <-chan struct{}) {
+	last, _ := os.Stat(m.Config.ConfigPath)
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			info, err := os.Stat(m.Config.ConfigPath)
+			if err != nil || (last != nil && info.ModTime().Equal(last.ModTime()) && info.Size() == last.Size()) {
+				continue
+			}
+			last = info
+			log.Infof("Configuration file %s has changed; reloading...", m.Config.ConfigPath)
+			if err = m.loadConfig(); err != nil {
+				log.Warnf("Unable to reload configuration file %s: %+v", m.Config.ConfigPath, err)
+			}
+		}
+	}
+}
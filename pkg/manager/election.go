@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/topo-discovery/pkg/election"
+	"google.golang.org/grpc"
+)
+
+// Leader election modes
+const (
+	// NoLeaderElection runs the controller unconditionally
+	NoLeaderElection = "none"
+	// TopoLeaderElection keeps the leadership lease in onos-topo
+	TopoLeaderElection = "topo"
+	// FileLeaderElection keeps the leadership lease in a local file
+	FileLeaderElection = "file"
+)
+
+// Starts competing for leadership; the controller runs only while this replica is the leader
+func (m *Manager) startLeaderElection(topoOpts []grpc.DialOption) error {
+	if m.Config.LeaseDuration <= 0 {
+		return errors.NewInvalid("lease duration must be positive")
+	}
+	store, err := m.leaseStore(topoOpts)
+	if err != nil {
+		return err
+	}
+	m.elector = election.NewElector(m.Config.InstanceID, store, m.Config.LeaseDuration, election.Callbacks{
+		OnStartedLeading: m.controller.Start,
+		OnStoppedLeading: m.controller.Stop,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.stopElection = cancel
+	m.electionDone = make(chan struct{})
+	go func() {
+		defer close(m.electionDone)
+		m.elector.Run(ctx)
+	}()
+	return nil
+}
+
+// Stops competing for leadership, stopping the controller if this replica is the leader
+func (m *Manager) stopLeaderElection() {
+	m.stopElection()
+	<-m.electionDone
+}
+
+// Creates the lease store for the configured leader election mode
+func (m *Manager) leaseStore(topoOpts []grpc.DialOption) (election.LeaseStore, error) {
+	switch m.Config.LeaderElection {
+	case TopoLeaderElection:
+		// Use a connection separate from the controller's, since the controller connects only once it leads
+		client, err := m.topoClient(topoOpts)
+		if err != nil {
+			return nil, err
+		}
+		return election.NewTopoLeaseStore(client, m.leaseID()), nil
+	case FileLeaderElection:
+		if len(m.Config.LeaseFile) == 0 {
+			return nil, errors.NewInvalid("lease file is required for file-based leader election")
+		}
+		return election.NewFileLeaseStore(m.Config.LeaseFile), nil
+	}
+	return nil, errors.NewInvalid("unsupported leader election mode %s", m.Config.LeaderElection)
+}
+
+// Returns the ID of the lease entity; replicas serving the same realm share the lease
+func (m *Manager) leaseID() topo.ID {
+	return topo.ID("topo-discovery-lease-" + m.realmGroup())
+}
+
+// Returns the name of the group of instances serving the same realm
+func (m *Manager) realmGroup() string {
+	if m.Config.RealmOptions != nil && len(m.Config.RealmOptions.Value) > 0 {
+		return m.Config.RealmOptions.Value
+	}
+	return "default"
+}
+
+// Returns an onos-topo client for coordination among instances, connecting to onos-topo on first use
+func (m *Manager) topoClient(topoOpts []grpc.DialOption) (topo.TopoClient, error) {
+	if m.topoConn == nil {
+		conn, err := grpc.Dial(m.Config.TopoAddress, topoOpts...)
+		if err != nil {
+			return nil, err
+		}
+		m.topoConn = conn
+	}
+	return topo.CreateTopoClient(m.topoConn), nil
+}
+
+// Returns true if leader election is enabled
+func (m *Manager) leaderElectionEnabled() bool {
+	return len(m.Config.LeaderElection) > 0 && m.Config.LeaderElection != NoLeaderElection
+}
@@ -6,13 +6,22 @@
 package manager
 
 import (
+	"context"
 	"github.com/onosproject/onos-lib-go/pkg/certs"
 	"github.com/onosproject/onos-lib-go/pkg/cli"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-lib-go/pkg/northbound"
 	"github.com/onosproject/onos-net-lib/pkg/realm"
 	"github.com/onosproject/topo-discovery/pkg/controller"
+	"github.com/onosproject/topo-discovery/pkg/election"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+	"github.com/onosproject/topo-discovery/pkg/metrics"
 	nb "github.com/onosproject/topo-discovery/pkg/northbound"
+	"github.com/onosproject/topo-discovery/pkg/sharding"
+	"google.golang.org/grpc"
+	"net/http"
+	"time"
 )
 
 var log = logging.GetLogger("manager")
@@ -22,14 +31,37 @@ type Config struct {
 	RealmOptions         *realm.Options
 	NeighborRealmOptions *realm.Options
 	TopoAddress          string
+	CablingPlanPath      string
+	PurgeRemovedDevices  bool
+	MetricsAddress       string
+	ControllerConfig     controller.Config
+	ConfigPath           string
+	LeaderElection       string
+	LeaseFile            string
+	LeaseDuration        time.Duration
+	InstanceID           string
+	Sharding             string
+	MembershipDir        string
+	MembershipTTL        time.Duration
 	ServiceFlags         *cli.ServiceEndpointFlags
 }
 
 // Manager single point of entry for the topology discovery
 type Manager struct {
 	cli.Daemon
-	Config     Config
-	controller *controller.Controller
+	Config        Config
+	controller    *controller.Controller
+	metricsServer *http.Server
+	stopWatch     chan struct{}
+
+	elector      *election.Elector
+	topoConn     *grpc.ClientConn
+	stopElection context.CancelFunc
+	electionDone chan struct{}
+
+	sharder      *sharding.Sharder
+	stopSharding context.CancelFunc
+	shardingDone chan struct{}
 }
 
 // NewManager initializes the application manager
@@ -49,17 +81,88 @@ func (m *Manager) Start() error {
 	}
 
 	m.controller = controller.NewController(m.Config.RealmOptions, m.Config.NeighborRealmOptions, m.Config.TopoAddress, opts...)
-	m.controller.Start()
+	if len(m.Config.CablingPlanPath) > 0 {
+		plan, err := inventory.LoadCablingPlan(m.Config.CablingPlanPath)
+		if err != nil {
+			return err
+		}
+		m.controller.SetCablingPlan(plan)
+	}
+	m.controller.SetPurgeRemovedDevices(m.Config.PurgeRemovedDevices)
+	if err = m.controller.Configure(m.Config.ControllerConfig); err != nil {
+		return err
+	}
+	if len(m.Config.ConfigPath) > 0 {
+		if err = m.loadConfig(); err != nil {
+			return err
+		}
+		m.stopWatch = make(chan struct{})
+		go m.watchConfig(m.stopWatch)
+	}
+	switch {
+	case m.leaderElectionEnabled() && m.shardingEnabled():
+		return errors.NewInvalid("leader election and sharding are mutually exclusive")
+	case m.leaderElectionEnabled():
+		// Only the leader replica runs the controller; standby replicas keep serving the NB API, which reports
+		// them as not ready
+		if err = m.startLeaderElection(opts); err != nil {
+			return err
+		}
+	case m.shardingEnabled():
+		if err = m.startSharding(opts); err != nil {
+			return err
+		}
+		m.controller.Start()
+	default:
+		m.controller.Start()
+	}
 
 	// Start NB server
 	s := northbound.NewServer(cli.ServerConfigFromFlags(m.Config.ServiceFlags, northbound.SecurityConfig{}))
 	s.AddService(logging.Service{})
 	s.AddService(nb.NewService(m.controller))
-	return s.StartInBackground()
+	if err = s.StartInBackground(); err != nil {
+		return err
+	}
+
+	// Start the metrics endpoint alongside the NB server
+	if len(m.Config.MetricsAddress) > 0 {
+		m.startMetricsServer()
+	}
+	return nil
+}
+
+// Serves the discovery metrics in the Prometheus text exposition format
+func (m *Manager) startMetricsServer() {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metrics.Handler())
+	m.metricsServer = &http.Server{Addr: m.Config.MetricsAddress, Handler: mux}
+	go func() {
+		log.Infof("Serving metrics on %s", m.Config.MetricsAddress)
+		if err := m.metricsServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			log.Warnf("Unable to serve metrics: %+v", err)
+		}
+	}()
 }
 
 // Stop stops the manager
 func (m *Manager) Stop() {
 	log.Info("Stopping Manager")
-	m.controller.Stop()
+	if m.stopWatch != nil {
+		close(m.stopWatch)
+	}
+	if m.elector != nil {
+		m.stopLeaderElection()
+	} else {
+		if m.sharder != nil {
+			m.leaveSharding()
+		}
+		m.controller.Stop()
+	}
+	if m.topoConn != nil {
+		_ = m.topoConn.Close()
+	}
+	if m.metricsServer != nil {
+		_ = m.metricsServer.Close()
+	}
 }
@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package manager
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/topo-discovery/pkg/sharding"
+	"google.golang.org/grpc"
+)
+
+// Sharding modes
+const (
+	// NoSharding makes the instance discover all realm devices
+	NoSharding = "none"
+	// TopoSharding keeps the memberships of the instances in onos-topo
+	TopoSharding = "topo"
+	// FileSharding keeps the memberships of the instances in a local directory
+	FileSharding = "file"
+)
+
+// Joins the group of instances sharing the realm devices; the controller discovers only the devices assigned to
+// this instance, and is rebalanced whenever instances join or leave
+func (m *Manager) startSharding(topoOpts []grpc.DialOption) error {
+	if m.Config.MembershipTTL <= 0 {
+		return errors.NewInvalid("membership time-to-live must be positive")
+	}
+	store, err := m.membershipStore(topoOpts)
+	if err != nil {
+		return err
+	}
+	m.sharder = sharding.NewSharder(m.Config.InstanceID, store, m.Config.MembershipTTL, m.controller.Rebalance)
+
+	// Learn of the other instances before starting discovery; if they cannot be learned, start without any devices,
+	// rather than contend for them with the other instances, until the periodic refresh succeeds
+	ctx, cancel := context.WithCancel(context.Background())
+	if err = m.sharder.Refresh(ctx); err != nil {
+		log.Warnf("Unable to learn shard membership; not discovering any devices until it is learned: %+v", err)
+		m.controller.Rebalance(sharding.NewEmptyRing(m.Config.InstanceID))
+	}
+	m.stopSharding = cancel
+	m.shardingDone = make(chan struct{})
+	go func() {
+		defer close(m.shardingDone)
+		m.sharder.Run(ctx)
+	}()
+	return nil
+}
+
+// Leaves the group of instances sharing the realm devices
+func (m *Manager) leaveSharding() {
+	m.stopSharding()
+	<-m.shardingDone
+}
+
+// Creates the membership store for the configured sharding mode
+func (m *Manager) membershipStore(topoOpts []grpc.DialOption) (sharding.MembershipStore, error) {
+	switch m.Config.Sharding {
+	case TopoSharding:
+		client, err := m.topoClient(topoOpts)
+		if err != nil {
+			return nil, err
+		}
+		return sharding.NewTopoMembershipStore(client, m.realmGroup()), nil
+	case FileSharding:
+		if len(m.Config.MembershipDir) == 0 {
+			return nil, errors.NewInvalid("membership directory is required for file-based sharding")
+		}
+		return sharding.NewFileMembershipStore(m.Config.MembershipDir), nil
+	}
+	return nil, errors.NewInvalid("unsupported sharding mode %s", m.Config.Sharding)
+}
+
+// Returns true if sharding is enabled
+func (m *Manager) shardingEnabled() bool {
+	return len(m.Config.Sharding) > 0 && m.Config.Sharding != NoSharding
+}
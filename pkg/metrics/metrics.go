@@ -0,0 +1,387 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package metrics provides a minimal registry of counters, gauges and histograms, exposed over HTTP using the
+// Prometheus text exposition format
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	counterType   = "counter"
+	gaugeType     = "gauge"
+	histogramType = "histogram"
+
+	// ContentType is the content type of the Prometheus text exposition format
+	ContentType = "text/plain; version=0.0.4; charset=utf-8"
+)
+
+// DefaultBuckets are the default histogram buckets, suitable for durations measured in seconds
+var DefaultBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// DefaultRegistry is the registry used by the package-level metric constructors
+var DefaultRegistry = NewRegistry()
+
+// Registry holds a set of metric families keyed by name
+type Registry struct {
+	lock     sync.RWMutex
+	families map[string]family
+}
+
+// A metric family renders itself in the text exposition format
+type family interface {
+	write(w io.Writer) error
+}
+
+// NewRegistry creates a new empty metrics registry
+func NewRegistry() *Registry {
+	return &Registry{families: make(map[string]family)}
+}
+
+// Registers the metric family under the given name, replacing any family previously registered under it
+func (r *Registry) register(name string, f family) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.families[name] = f
+}
+
+// WriteText writes all registered metric families, ordered by name, in the Prometheus text exposition format
+func (r *Registry) WriteText(w io.Writer) error {
+	r.lock.RLock()
+	names := make([]string, 0, len(r.families))
+	for name := range r.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	families := make([]family, 0, len(names))
+	for _, name := range names {
+		families = append(families, r.families[name])
+	}
+	r.lock.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	for _, f := range families {
+		if err := f.write(bw); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// ServeHTTP renders the registered metrics in response to a scrape
+func (r *Registry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("Content-Type", ContentType)
+	_ = r.WriteText(w)
+}
+
+// Handler returns the HTTP handler serving the metrics of the default registry
+func Handler() http.Handler {
+	return DefaultRegistry
+}
+
+// Common metadata and labeled series of a metric family
+type metric struct {
+	name       string
+	help       string
+	kind       string
+	labelNames []string
+
+	lock   sync.Mutex
+	series map[string]*series
+}
+
+// Values of a single labeled series
+type series struct {
+	labelValues []string
+	value       float64
+	buckets     []uint64
+	count       uint64
+}
+
+func newMetric(name string, help string, kind string, labelNames []string) *metric {
+	return &metric{name: name, help: help, kind: kind, labelNames: labelNames, series: make(map[string]*series)}
+}
+
+// Returns the series for the given label values, creating it if needed; must be called with the lock held
+func (m *metric) get(labelValues []string) *series {
+	if len(labelValues) != len(m.labelNames) {
+		panic(fmt.Sprintf("metric %s expects %d label values; got %d", m.name, len(m.labelNames), len(labelValues)))
+	}
+	key := strings.Join(labelValues, "\xff")
+	s, ok := m.series[key]
+	if !ok {
+		s = &series{labelValues: append([]string(nil), labelValues...)}
+		m.series[key] = s
+	}
+	return s
+}
+
+// Returns the series sorted by their label values; must be called with the lock held
+func (m *metric) sorted() []*series {
+	keys := make([]string, 0, len(m.series))
+	for key := range m.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	sorted := make([]*series, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, m.series[key])
+	}
+	return sorted
+}
+
+// Returns the current value of the series with the given label values
+func (m *metric) value(labelValues []string) float64 {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	return m.get(labelValues).value
+}
+
+// Writes the HELP and TYPE header lines of the family
+func (m *metric) writeHeader(w io.Writer) error {
+	_, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, escapeHelp(m.help), m.name, m.kind)
+	return err
+}
+
+// Writes the family of single-value series
+func (m *metric) write(w io.Writer) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+	if err := m.writeHeader(w); err != nil {
+		return err
+	}
+	for _, s := range m.sorted() {
+		if err := writeSample(w, m.name, m.labelNames, s.labelValues, "", "", s.value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Counter is a monotonically increasing metric, optionally partitioned by labels
+type Counter struct {
+	*metric
+}
+
+// NewCounter creates a counter with the given label names and registers it with the default registry
+func NewCounter(name string, help string, labelNames ...string) *Counter {
+	return DefaultRegistry.NewCounter(name, help, labelNames...)
+}
+
+// NewCounter creates a counter with the given label names and registers it with the registry
+func (r *Registry) NewCounter(name string, help string, labelNames ...string) *Counter {
+	c := &Counter{metric: newMetric(name, help, counterType, labelNames)}
+	r.register(name, c)
+	return c
+}
+
+// Inc increments the counter of the series with the given label values
+func (c *Counter) Inc(labelValues ...string) {
+	c.Add(1, labelValues...)
+}
+
+// Add adds the given non-negative amount to the counter of the series with the given label values
+func (c *Counter) Add(delta float64, labelValues ...string) {
+	if delta < 0 {
+		panic(fmt.Sprintf("counter %s cannot decrease", c.name))
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.get(labelValues).value += delta
+}
+
+// Value returns the counter of the series with the given label values
+func (c *Counter) Value(labelValues ...string) float64 {
+	return c.value(labelValues)
+}
+
+// Gauge is a metric that can go up and down, optionally partitioned by labels
+type Gauge struct {
+	*metric
+}
+
+// NewGauge creates a gauge with the given label names and registers it with the default registry
+func NewGauge(name string, help string, labelNames ...string) *Gauge {
+	return DefaultRegistry.NewGauge(name, help, labelNames...)
+}
+
+// NewGauge creates a gauge with the given label names and registers it with the registry
+func (r *Registry) NewGauge(name string, help string, labelNames ...string) *Gauge {
+	g := &Gauge{metric: newMetric(name, help, gaugeType, labelNames)}
+	r.register(name, g)
+	return g
+}
+
+// Set sets the gauge of the series with the given label values
+func (g *Gauge) Set(value float64, labelValues ...string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.get(labelValues).value = value
+}
+
+// Add adds the given amount, which may be negative, to the gauge of the series with the given label values
+func (g *Gauge) Add(delta float64, labelValues ...string) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	g.get(labelValues).value += delta
+}
+
+// Value returns the gauge of the series with the given label values
+func (g *Gauge) Value(labelValues ...string) float64 {
+	return g.value(labelValues)
+}
+
+// Gauge whose value is computed when scraped
+type gaugeFunc struct {
+	name    string
+	help    string
+	collect func() float64
+}
+
+// NewGaugeFunc registers with the default registry an unlabeled gauge whose value is obtained from the given
+// function when scraped, replacing any metric previously registered under the same name
+func NewGaugeFunc(name string, help string, collect func() float64) {
+	DefaultRegistry.NewGaugeFunc(name, help, collect)
+}
+
+// NewGaugeFunc registers an unlabeled gauge whose value is obtained from the given function when scraped,
+// replacing any metric previously registered under the same name
+func (r *Registry) NewGaugeFunc(name string, help string, collect func() float64) {
+	r.register(name, &gaugeFunc{name: name, help: help, collect: collect})
+}
+
+func (g *gaugeFunc) write(w io.Writer) error {
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", g.name, escapeHelp(g.help), g.name, gaugeType); err != nil {
+		return err
+	}
+	return writeSample(w, g.name, nil, nil, "", "", g.collect())
+}
+
+// Histogram samples observations into cumulative buckets, optionally partitioned by labels
+type Histogram struct {
+	*metric
+	bounds []float64
+}
+
+// NewHistogram creates a histogram with the given bucket upper bounds and label names and registers it with the
+// default registry
+func NewHistogram(name string, help string, buckets []float64, labelNames ...string) *Histogram {
+	return DefaultRegistry.NewHistogram(name, help, buckets, labelNames...)
+}
+
+// NewHistogram creates a histogram with the given bucket upper bounds and label names and registers it with the
+// registry
+func (r *Registry) NewHistogram(name string, help string, buckets []float64, labelNames ...string) *Histogram {
+	bounds := append([]float64(nil), buckets...)
+	sort.Float64s(bounds)
+	h := &Histogram{metric: newMetric(name, help, histogramType, labelNames), bounds: bounds}
+	r.register(name, h)
+	return h
+}
+
+// Observe records the given value in the series with the given label values
+func (h *Histogram) Observe(value float64, labelValues ...string) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	s := h.get(labelValues)
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(h.bounds))
+	}
+	for i, bound := range h.bounds {
+		if value <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.count++
+	s.value += value
+}
+
+// Count returns the number of observations recorded in the series with the given label values
+func (h *Histogram) Count(labelValues ...string) uint64 {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	return h.get(labelValues).count
+}
+
+func (h *Histogram) write(w io.Writer) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if err := h.writeHeader(w); err != nil {
+		return err
+	}
+	for _, s := range h.sorted() {
+		for i, bound := range h.bounds {
+			count := uint64(0)
+			if s.buckets != nil {
+				count = s.buckets[i]
+			}
+			if err := writeSample(w, h.name+"_bucket", h.labelNames, s.labelValues, "le", formatValue(bound), float64(count)); err != nil {
+				return err
+			}
+		}
+		if err := writeSample(w, h.name+"_bucket", h.labelNames, s.labelValues, "le", "+Inf", float64(s.count)); err != nil {
+			return err
+		}
+		if err := writeSample(w, h.name+"_sum", h.labelNames, s.labelValues, "", "", s.value); err != nil {
+			return err
+		}
+		if err := writeSample(w, h.name+"_count", h.labelNames, s.labelValues, "", "", float64(s.count)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Writes a single sample line, with an optional extra label, e.g. the histogram bucket bound
+func writeSample(w io.Writer, name string, labelNames []string, labelValues []string, extraName string, extraValue string, value float64) error {
+	var sb strings.Builder
+	sb.WriteString(name)
+	if len(labelNames) > 0 || len(extraName) > 0 {
+		pairs := make([]string, 0, len(labelNames)+1)
+		for i, labelName := range labelNames {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", labelName, escapeLabelValue(labelValues[i])))
+		}
+		if len(extraName) > 0 {
+			pairs = append(pairs, fmt.Sprintf("%s=\"%s\"", extraName, extraValue))
+		}
+		sb.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+	_, err := fmt.Fprintf(w, "%s %s\n", sb.String(), formatValue(value))
+	return err
+}
+
+func formatValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
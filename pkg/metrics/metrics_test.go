@@ -0,0 +1,67 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package metrics
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTextExposition(t *testing.T) {
+	r := NewRegistry()
+	c := r.NewCounter("test_operations_total", "Number of operations", "kind", "op")
+	c.Inc("port", "create")
+	c.Add(2, "port", "create")
+	c.Inc("link", "update")
+	assert.Equal(t, float64(3), c.Value("port", "create"))
+
+	g := r.NewGauge("test_active", "Active \"things\"\nper stream", "stream")
+	g.Add(1, "ports")
+	g.Add(-1, "ports")
+	g.Set(2, "links")
+
+	depth := 5
+	r.NewGaugeFunc("test_queue_depth", "Queue depth", func() float64 { return float64(depth) })
+
+	h := r.NewHistogram("test_duration_seconds", "Duration", []float64{1, 0.5})
+	h.Observe(0.2)
+	h.Observe(0.7)
+	h.Observe(3)
+	assert.Equal(t, uint64(3), h.Count())
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, r.WriteText(buf))
+	assert.Equal(t, `# HELP test_active Active "things"\nper stream
+# TYPE test_active gauge
+test_active{stream="links"} 2
+test_active{stream="ports"} 0
+# HELP test_duration_seconds Duration
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{le="0.5"} 1
+test_duration_seconds_bucket{le="1"} 2
+test_duration_seconds_bucket{le="+Inf"} 3
+test_duration_seconds_sum 3.9
+test_duration_seconds_count 3
+# HELP test_operations_total Number of operations
+# TYPE test_operations_total counter
+test_operations_total{kind="link",op="update"} 1
+test_operations_total{kind="port",op="create"} 3
+# HELP test_queue_depth Queue depth
+# TYPE test_queue_depth gauge
+test_queue_depth 5
+`, buf.String())
+
+	// Registering under the same name replaces the previous metric
+	r.NewGaugeFunc("test_queue_depth", "Queue depth", func() float64 { return 7 })
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	assert.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), "test_queue_depth 7\n")
+
+	assert.Panics(t, func() { c.Inc("port") })
+	assert.Panics(t, func() { c.Add(-1, "port", "create") })
+}
@@ -0,0 +1,412 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package admin defines the messages and the gRPC service of the administrative API of the topology discovery, as
+// described by admin.proto. The messages are maintained by hand to match the proto definitions; they are encoded
+// based on their protobuf struct tags, like any other message without generated marshalling code.
+package admin
+
+import (
+	"github.com/gogo/protobuf/proto"
+	api "github.com/onosproject/onos-api/go/onos/discovery"
+)
+
+// UpdateSwitchRequest carries the new management info of an existing switch
+type UpdateSwitchRequest struct {
+	ID             string              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ManagementInfo *api.ManagementInfo `protobuf:"bytes,2,opt,name=management_info,json=managementInfo,proto3" json:"management_info,omitempty"`
+}
+
+func (m *UpdateSwitchRequest) Reset()         { *m = UpdateSwitchRequest{} }
+func (m *UpdateSwitchRequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateSwitchRequest) ProtoMessage()    {}
+
+// UpdateSwitchResponse is the response to an UpdateSwitchRequest
+type UpdateSwitchResponse struct {
+}
+
+func (m *UpdateSwitchResponse) Reset()         { *m = UpdateSwitchResponse{} }
+func (m *UpdateSwitchResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateSwitchResponse) ProtoMessage()    {}
+
+// UpdateServerIPURequest carries the new management info of an existing server and its IPU
+type UpdateServerIPURequest struct {
+	ID             string              `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	ManagementInfo *api.ManagementInfo `protobuf:"bytes,2,opt,name=management_info,json=managementInfo,proto3" json:"management_info,omitempty"`
+}
+
+func (m *UpdateServerIPURequest) Reset()         { *m = UpdateServerIPURequest{} }
+func (m *UpdateServerIPURequest) String() string { return proto.CompactTextString(m) }
+func (*UpdateServerIPURequest) ProtoMessage()    {}
+
+// UpdateServerIPUResponse is the response to an UpdateServerIPURequest
+type UpdateServerIPUResponse struct {
+}
+
+func (m *UpdateServerIPUResponse) Reset()         { *m = UpdateServerIPUResponse{} }
+func (m *UpdateServerIPUResponse) String() string { return proto.CompactTextString(m) }
+func (*UpdateServerIPUResponse) ProtoMessage()    {}
+
+// MoveToRackRequest identifies the switch or server to be moved and the rack to move it into, along with the pod
+// of the rack; the current pod of the switch or server, if not given
+type MoveToRackRequest struct {
+	ID     string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PodID  string `protobuf:"bytes,2,opt,name=pod_id,json=podId,proto3" json:"pod_id,omitempty"`
+	RackID string `protobuf:"bytes,3,opt,name=rack_id,json=rackId,proto3" json:"rack_id,omitempty"`
+}
+
+func (m *MoveToRackRequest) Reset()         { *m = MoveToRackRequest{} }
+func (m *MoveToRackRequest) String() string { return proto.CompactTextString(m) }
+func (*MoveToRackRequest) ProtoMessage()    {}
+
+// MoveToRackResponse is the response to a MoveToRackRequest
+type MoveToRackResponse struct {
+}
+
+func (m *MoveToRackResponse) Reset()         { *m = MoveToRackResponse{} }
+func (m *MoveToRackResponse) String() string { return proto.CompactTextString(m) }
+func (*MoveToRackResponse) ProtoMessage()    {}
+
+// RemovedObject identifies a topology object removed as part of an asset removal
+type RemovedObject struct {
+	ID   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind string `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+}
+
+func (m *RemovedObject) Reset()         { *m = RemovedObject{} }
+func (m *RemovedObject) String() string { return proto.CompactTextString(m) }
+func (*RemovedObject) ProtoMessage()    {}
+
+// RemovePodRequest identifies the POD to be removed
+type RemovePodRequest struct {
+	ID string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (m *RemovePodRequest) Reset()         { *m = RemovePodRequest{} }
+func (m *RemovePodRequest) String() string { return proto.CompactTextString(m) }
+func (*RemovePodRequest) ProtoMessage()    {}
+
+// RemovePodResponse lists the objects removed along with the POD; if the removal failed partway, the
+// error is given along with the objects removed until then
+type RemovePodResponse struct {
+	Removed []*RemovedObject `protobuf:"bytes,1,rep,name=removed,proto3" json:"removed,omitempty"`
+	Error   string           `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *RemovePodResponse) Reset()         { *m = RemovePodResponse{} }
+func (m *RemovePodResponse) String() string { return proto.CompactTextString(m) }
+func (*RemovePodResponse) ProtoMessage()    {}
+
+// RemoveRackRequest identifies the rack to be removed
+type RemoveRackRequest struct {
+	ID string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (m *RemoveRackRequest) Reset()         { *m = RemoveRackRequest{} }
+func (m *RemoveRackRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveRackRequest) ProtoMessage()    {}
+
+// RemoveRackResponse lists the objects removed along with the rack; if the removal failed partway, the
+// error is given along with the objects removed until then
+type RemoveRackResponse struct {
+	Removed []*RemovedObject `protobuf:"bytes,1,rep,name=removed,proto3" json:"removed,omitempty"`
+	Error   string           `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *RemoveRackResponse) Reset()         { *m = RemoveRackResponse{} }
+func (m *RemoveRackResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveRackResponse) ProtoMessage()    {}
+
+// RemoveSwitchRequest identifies the switch to be removed
+type RemoveSwitchRequest struct {
+	ID string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (m *RemoveSwitchRequest) Reset()         { *m = RemoveSwitchRequest{} }
+func (m *RemoveSwitchRequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveSwitchRequest) ProtoMessage()    {}
+
+// RemoveSwitchResponse lists the objects removed along with the switch; if the removal failed partway, the
+// error is given along with the objects removed until then
+type RemoveSwitchResponse struct {
+	Removed []*RemovedObject `protobuf:"bytes,1,rep,name=removed,proto3" json:"removed,omitempty"`
+	Error   string           `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *RemoveSwitchResponse) Reset()         { *m = RemoveSwitchResponse{} }
+func (m *RemoveSwitchResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveSwitchResponse) ProtoMessage()    {}
+
+// RemoveServerIPURequest identifies the server and IPU to be removed
+type RemoveServerIPURequest struct {
+	ID string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+}
+
+func (m *RemoveServerIPURequest) Reset()         { *m = RemoveServerIPURequest{} }
+func (m *RemoveServerIPURequest) String() string { return proto.CompactTextString(m) }
+func (*RemoveServerIPURequest) ProtoMessage()    {}
+
+// RemoveServerIPUResponse lists the objects removed along with the server and IPU; if the removal failed partway, the
+// error is given along with the objects removed until then
+type RemoveServerIPUResponse struct {
+	Removed []*RemovedObject `protobuf:"bytes,1,rep,name=removed,proto3" json:"removed,omitempty"`
+	Error   string           `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *RemoveServerIPUResponse) Reset()         { *m = RemoveServerIPUResponse{} }
+func (m *RemoveServerIPUResponse) String() string { return proto.CompactTextString(m) }
+func (*RemoveServerIPUResponse) ProtoMessage()    {}
+
+// ExportTopologyRequest specifies the format of the exported topology document; YAML, if not given
+type ExportTopologyRequest struct {
+	Format string `protobuf:"bytes,1,opt,name=format,proto3" json:"format,omitempty"`
+}
+
+func (m *ExportTopologyRequest) Reset()         { *m = ExportTopologyRequest{} }
+func (m *ExportTopologyRequest) String() string { return proto.CompactTextString(m) }
+func (*ExportTopologyRequest) ProtoMessage()    {}
+
+// ExportTopologyResponse carries the exported topology document
+type ExportTopologyResponse struct {
+	Document []byte `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+}
+
+func (m *ExportTopologyResponse) Reset()         { *m = ExportTopologyResponse{} }
+func (m *ExportTopologyResponse) String() string { return proto.CompactTextString(m) }
+func (*ExportTopologyResponse) ProtoMessage()    {}
+
+// ImportInventoryRequest carries the YAML or JSON inventory document to be imported
+type ImportInventoryRequest struct {
+	Document []byte `protobuf:"bytes,1,opt,name=document,proto3" json:"document,omitempty"`
+}
+
+func (m *ImportInventoryRequest) Reset()         { *m = ImportInventoryRequest{} }
+func (m *ImportInventoryRequest) String() string { return proto.CompactTextString(m) }
+func (*ImportInventoryRequest) ProtoMessage()    {}
+
+// ImportResult describes the outcome of adding a single inventory item
+type ImportResult struct {
+	Kind    string `protobuf:"bytes,1,opt,name=kind,proto3" json:"kind,omitempty"`
+	ID      string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Status  string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Message string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (m *ImportResult) Reset()         { *m = ImportResult{} }
+func (m *ImportResult) String() string { return proto.CompactTextString(m) }
+func (*ImportResult) ProtoMessage()    {}
+
+// ImportInventoryResponse lists the outcomes of adding all inventory items, in the order they were processed
+type ImportInventoryResponse struct {
+	Results []*ImportResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (m *ImportInventoryResponse) Reset()         { *m = ImportInventoryResponse{} }
+func (m *ImportInventoryResponse) String() string { return proto.CompactTextString(m) }
+func (*ImportInventoryResponse) ProtoMessage()    {}
+
+// Cable describes an expected port-to-port connection, using the topology port IDs, i.e. <device>/<port-number>
+type Cable struct {
+	Source         string `protobuf:"bytes,1,opt,name=src,proto3" json:"src,omitempty"`
+	Target         string `protobuf:"bytes,2,opt,name=tgt,proto3" json:"tgt,omitempty"`
+	Unidirectional bool   `protobuf:"varint,3,opt,name=unidirectional,proto3" json:"unidirectional,omitempty"`
+}
+
+func (m *Cable) Reset()         { *m = Cable{} }
+func (m *Cable) String() string { return proto.CompactTextString(m) }
+func (*Cable) ProtoMessage()    {}
+
+// SetCablingPlanRequest carries the cables of the expected cabling plan; no cables clear the plan
+type SetCablingPlanRequest struct {
+	Cables []*Cable `protobuf:"bytes,1,rep,name=cables,proto3" json:"cables,omitempty"`
+}
+
+func (m *SetCablingPlanRequest) Reset()         { *m = SetCablingPlanRequest{} }
+func (m *SetCablingPlanRequest) String() string { return proto.CompactTextString(m) }
+func (*SetCablingPlanRequest) ProtoMessage()    {}
+
+// SetCablingPlanResponse is the response to a SetCablingPlanRequest
+type SetCablingPlanResponse struct {
+}
+
+func (m *SetCablingPlanResponse) Reset()         { *m = SetCablingPlanResponse{} }
+func (m *SetCablingPlanResponse) String() string { return proto.CompactTextString(m) }
+func (*SetCablingPlanResponse) ProtoMessage()    {}
+
+// CablingReportRequest requests verification of the discovered links against the cabling plan
+type CablingReportRequest struct {
+}
+
+func (m *CablingReportRequest) Reset()         { *m = CablingReportRequest{} }
+func (m *CablingReportRequest) String() string { return proto.CompactTextString(m) }
+func (*CablingReportRequest) ProtoMessage()    {}
+
+// CablingResult describes how a single discovered or expected link relates to the cabling plan
+type CablingResult struct {
+	Source   string `protobuf:"bytes,1,opt,name=src,proto3" json:"src,omitempty"`
+	Target   string `protobuf:"bytes,2,opt,name=tgt,proto3" json:"tgt,omitempty"`
+	Status   string `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	LinkID   string `protobuf:"bytes,4,opt,name=link_id,json=linkId,proto3" json:"link_id,omitempty"`
+	Expected string `protobuf:"bytes,5,opt,name=expected,proto3" json:"expected,omitempty"`
+}
+
+func (m *CablingResult) Reset()         { *m = CablingResult{} }
+func (m *CablingResult) String() string { return proto.CompactTextString(m) }
+func (*CablingResult) ProtoMessage()    {}
+
+// CablingReportResponse lists the results of verifying the discovered links against the cabling plan
+type CablingReportResponse struct {
+	Results []*CablingResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (m *CablingReportResponse) Reset()         { *m = CablingReportResponse{} }
+func (m *CablingReportResponse) String() string { return proto.CompactTextString(m) }
+func (*CablingReportResponse) ProtoMessage()    {}
+
+// WatchDiscoveryEventsRequest filters the watched events; empty fields match all events
+type WatchDiscoveryEventsRequest struct {
+	DeviceIDs []string `protobuf:"bytes,1,rep,name=device_ids,json=deviceIds,proto3" json:"device_ids,omitempty"`
+	Types     []string `protobuf:"bytes,2,rep,name=types,proto3" json:"types,omitempty"`
+}
+
+func (m *WatchDiscoveryEventsRequest) Reset()         { *m = WatchDiscoveryEventsRequest{} }
+func (m *WatchDiscoveryEventsRequest) String() string { return proto.CompactTextString(m) }
+func (*WatchDiscoveryEventsRequest) ProtoMessage()    {}
+
+// DiscoveryEvent describes a change made, or a condition detected, by the discovery reconcilers
+type DiscoveryEvent struct {
+	Type string `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// Time of the event in nanoseconds since the Unix epoch
+	Timestamp    int64  `protobuf:"varint,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	ObjectID     string `protobuf:"bytes,3,opt,name=object_id,json=objectId,proto3" json:"object_id,omitempty"`
+	DeviceID     string `protobuf:"bytes,4,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	PeerDeviceID string `protobuf:"bytes,5,opt,name=peer_device_id,json=peerDeviceId,proto3" json:"peer_device_id,omitempty"`
+	AgentID      string `protobuf:"bytes,6,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Message      string `protobuf:"bytes,7,opt,name=message,proto3" json:"message,omitempty"`
+}
+
+func (m *DiscoveryEvent) Reset()         { *m = DiscoveryEvent{} }
+func (m *DiscoveryEvent) String() string { return proto.CompactTextString(m) }
+func (*DiscoveryEvent) ProtoMessage()    {}
+
+// WatchDiscoveryEventsResponse carries a single discovery event
+type WatchDiscoveryEventsResponse struct {
+	Event *DiscoveryEvent `protobuf:"bytes,1,opt,name=event,proto3" json:"event,omitempty"`
+}
+
+func (m *WatchDiscoveryEventsResponse) Reset()         { *m = WatchDiscoveryEventsResponse{} }
+func (m *WatchDiscoveryEventsResponse) String() string { return proto.CompactTextString(m) }
+func (*WatchDiscoveryEventsResponse) ProtoMessage()    {}
+
+// RediscoverRequest identifies the device to be rediscovered, all realm devices if not given, and whether its
+// connections should be re-established first
+type RediscoverRequest struct {
+	DeviceID  string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Reconnect bool   `protobuf:"varint,2,opt,name=reconnect,proto3" json:"reconnect,omitempty"`
+}
+
+func (m *RediscoverRequest) Reset()         { *m = RediscoverRequest{} }
+func (m *RediscoverRequest) String() string { return proto.CompactTextString(m) }
+func (*RediscoverRequest) ProtoMessage()    {}
+
+// PhaseResult describes the outcome of a single discovery pass
+type PhaseResult struct {
+	Phase string `protobuf:"bytes,1,opt,name=phase,proto3" json:"phase,omitempty"`
+	// Duration of the pass in nanoseconds
+	Duration int64  `protobuf:"varint,2,opt,name=duration,proto3" json:"duration,omitempty"`
+	Error    string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *PhaseResult) Reset()         { *m = PhaseResult{} }
+func (m *PhaseResult) String() string { return proto.CompactTextString(m) }
+func (*PhaseResult) ProtoMessage()    {}
+
+// DeviceResult describes the outcome of all discovery passes run for a device
+type DeviceResult struct {
+	DeviceID string         `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Phases   []*PhaseResult `protobuf:"bytes,2,rep,name=phases,proto3" json:"phases,omitempty"`
+	// Set if the discovery passes could not be run at all
+	Error string `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+}
+
+func (m *DeviceResult) Reset()         { *m = DeviceResult{} }
+func (m *DeviceResult) String() string { return proto.CompactTextString(m) }
+func (*DeviceResult) ProtoMessage()    {}
+
+// RediscoverResponse lists the results for all rediscovered devices
+type RediscoverResponse struct {
+	Results []*DeviceResult `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+}
+
+func (m *RediscoverResponse) Reset()         { *m = RediscoverResponse{} }
+func (m *RediscoverResponse) String() string { return proto.CompactTextString(m) }
+func (*RediscoverResponse) ProtoMessage()    {}
+
+// RediscoverStreamResponse carries the result for a single rediscovered device
+type RediscoverStreamResponse struct {
+	Result *DeviceResult `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+}
+
+func (m *RediscoverStreamResponse) Reset()         { *m = RediscoverStreamResponse{} }
+func (m *RediscoverStreamResponse) String() string { return proto.CompactTextString(m) }
+func (*RediscoverStreamResponse) ProtoMessage()    {}
+
+// GetStatusRequest requests the operational status of the controller
+type GetStatusRequest struct {
+}
+
+func (m *GetStatusRequest) Reset()         { *m = GetStatusRequest{} }
+func (m *GetStatusRequest) String() string { return proto.CompactTextString(m) }
+func (*GetStatusRequest) ProtoMessage()    {}
+
+// DeviceStatus describes the discovery status of a single device; times are in nanoseconds since the Unix epoch,
+// 0 if never
+type DeviceStatus struct {
+	DeviceID          string `protobuf:"bytes,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	LastPortDiscovery int64  `protobuf:"varint,2,opt,name=last_port_discovery,json=lastPortDiscovery,proto3" json:"last_port_discovery,omitempty"`
+	LastLinkDiscovery int64  `protobuf:"varint,3,opt,name=last_link_discovery,json=lastLinkDiscovery,proto3" json:"last_link_discovery,omitempty"`
+	LastHostDiscovery int64  `protobuf:"varint,4,opt,name=last_host_discovery,json=lastHostDiscovery,proto3" json:"last_host_discovery,omitempty"`
+	LastError         string `protobuf:"bytes,5,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	LastErrorTime     int64  `protobuf:"varint,6,opt,name=last_error_time,json=lastErrorTime,proto3" json:"last_error_time,omitempty"`
+	AgentID           string `protobuf:"bytes,7,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	PortMonitor       bool   `protobuf:"varint,8,opt,name=port_monitor,json=portMonitor,proto3" json:"port_monitor,omitempty"`
+	LinkMonitor       bool   `protobuf:"varint,9,opt,name=link_monitor,json=linkMonitor,proto3" json:"link_monitor,omitempty"`
+	HostMonitor       bool   `protobuf:"varint,10,opt,name=host_monitor,json=hostMonitor,proto3" json:"host_monitor,omitempty"`
+}
+
+func (m *DeviceStatus) Reset()         { *m = DeviceStatus{} }
+func (m *DeviceStatus) String() string { return proto.CompactTextString(m) }
+func (*DeviceStatus) ProtoMessage()    {}
+
+// FlapStatus describes the flap history of a single link or port which changed its status at least once
+type FlapStatus struct {
+	ID         string  `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Kind       string  `protobuf:"bytes,2,opt,name=kind,proto3" json:"kind,omitempty"`
+	Status     string  `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Flaps      uint64  `protobuf:"varint,4,opt,name=flaps,proto3" json:"flaps,omitempty"`
+	Penalty    float64 `protobuf:"fixed64,5,opt,name=penalty,proto3" json:"penalty,omitempty"`
+	Suppressed bool    `protobuf:"varint,6,opt,name=suppressed,proto3" json:"suppressed,omitempty"`
+}
+
+func (m *FlapStatus) Reset()         { *m = FlapStatus{} }
+func (m *FlapStatus) String() string { return proto.CompactTextString(m) }
+func (*FlapStatus) ProtoMessage()    {}
+
+// GetStatusResponse describes the operational status of the controller; times are in nanoseconds since the Unix
+// epoch, 0 if never
+type GetStatusResponse struct {
+	State                   string          `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	LastSweep               int64           `protobuf:"varint,2,opt,name=last_sweep,json=lastSweep,proto3" json:"last_sweep,omitempty"`
+	LastSweepDuration       int64           `protobuf:"varint,3,opt,name=last_sweep_duration,json=lastSweepDuration,proto3" json:"last_sweep_duration,omitempty"`
+	LastSweepError          string          `protobuf:"bytes,4,opt,name=last_sweep_error,json=lastSweepError,proto3" json:"last_sweep_error,omitempty"`
+	RealmQueueDepth         int32           `protobuf:"varint,5,opt,name=realm_queue_depth,json=realmQueueDepth,proto3" json:"realm_queue_depth,omitempty"`
+	NeighborRealmQueueDepth int32           `protobuf:"varint,6,opt,name=neighbor_realm_queue_depth,json=neighborRealmQueueDepth,proto3" json:"neighbor_realm_queue_depth,omitempty"`
+	WorkingOn               int32           `protobuf:"varint,7,opt,name=working_on,json=workingOn,proto3" json:"working_on,omitempty"`
+	Devices                 []*DeviceStatus `protobuf:"bytes,8,rep,name=devices,proto3" json:"devices,omitempty"`
+	Flaps                   []*FlapStatus   `protobuf:"bytes,9,rep,name=flaps,proto3" json:"flaps,omitempty"`
+}
+
+func (m *GetStatusResponse) Reset()         { *m = GetStatusResponse{} }
+func (m *GetStatusResponse) String() string { return proto.CompactTextString(m) }
+func (*GetStatusResponse) ProtoMessage()    {}
@@ -0,0 +1,251 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package admin
+
+import (
+	"context"
+	"google.golang.org/grpc"
+)
+
+const serviceName = "onos.discovery.admin.DiscoveryAdminService"
+
+// DiscoveryAdminServiceServer is the server API of the DiscoveryAdminService
+type DiscoveryAdminServiceServer interface {
+	UpdateSwitch(context.Context, *UpdateSwitchRequest) (*UpdateSwitchResponse, error)
+	UpdateServerIPU(context.Context, *UpdateServerIPURequest) (*UpdateServerIPUResponse, error)
+	MoveToRack(context.Context, *MoveToRackRequest) (*MoveToRackResponse, error)
+	RemovePod(context.Context, *RemovePodRequest) (*RemovePodResponse, error)
+	RemoveRack(context.Context, *RemoveRackRequest) (*RemoveRackResponse, error)
+	RemoveSwitch(context.Context, *RemoveSwitchRequest) (*RemoveSwitchResponse, error)
+	RemoveServerIPU(context.Context, *RemoveServerIPURequest) (*RemoveServerIPUResponse, error)
+	ExportTopology(context.Context, *ExportTopologyRequest) (*ExportTopologyResponse, error)
+	ImportInventory(context.Context, *ImportInventoryRequest) (*ImportInventoryResponse, error)
+	SetCablingPlan(context.Context, *SetCablingPlanRequest) (*SetCablingPlanResponse, error)
+	CablingReport(context.Context, *CablingReportRequest) (*CablingReportResponse, error)
+	WatchDiscoveryEvents(*WatchDiscoveryEventsRequest, ServerStream[WatchDiscoveryEventsResponse]) error
+	Rediscover(context.Context, *RediscoverRequest) (*RediscoverResponse, error)
+	RediscoverStream(*RediscoverRequest, ServerStream[RediscoverStreamResponse]) error
+	GetStatus(context.Context, *GetStatusRequest) (*GetStatusResponse, error)
+}
+
+// RegisterDiscoveryAdminServiceServer registers the DiscoveryAdminService implementation with the gRPC server
+func RegisterDiscoveryAdminServiceServer(s *grpc.Server, srv DiscoveryAdminServiceServer) {
+	s.RegisterService(&serviceDesc, srv)
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*DiscoveryAdminServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		unaryMethod("UpdateSwitch", DiscoveryAdminServiceServer.UpdateSwitch),
+		unaryMethod("UpdateServerIPU", DiscoveryAdminServiceServer.UpdateServerIPU),
+		unaryMethod("MoveToRack", DiscoveryAdminServiceServer.MoveToRack),
+		unaryMethod("RemovePod", DiscoveryAdminServiceServer.RemovePod),
+		unaryMethod("RemoveRack", DiscoveryAdminServiceServer.RemoveRack),
+		unaryMethod("RemoveSwitch", DiscoveryAdminServiceServer.RemoveSwitch),
+		unaryMethod("RemoveServerIPU", DiscoveryAdminServiceServer.RemoveServerIPU),
+		unaryMethod("ExportTopology", DiscoveryAdminServiceServer.ExportTopology),
+		unaryMethod("ImportInventory", DiscoveryAdminServiceServer.ImportInventory),
+		unaryMethod("SetCablingPlan", DiscoveryAdminServiceServer.SetCablingPlan),
+		unaryMethod("CablingReport", DiscoveryAdminServiceServer.CablingReport),
+		unaryMethod("Rediscover", DiscoveryAdminServiceServer.Rediscover),
+		unaryMethod("GetStatus", DiscoveryAdminServiceServer.GetStatus),
+	},
+	Streams: []grpc.StreamDesc{
+		serverStreamingMethod("WatchDiscoveryEvents", DiscoveryAdminServiceServer.WatchDiscoveryEvents),
+		serverStreamingMethod("RediscoverStream", DiscoveryAdminServiceServer.RediscoverStream),
+	},
+	Metadata: "pkg/northbound/admin/admin.proto",
+}
+
+// Returns the full name of the service method
+func methodName(method string) string {
+	return "/" + serviceName + "/" + method
+}
+
+// Returns the descriptor of the unary method, which decodes the request and dispatches it to the given server method
+func unaryMethod[Req any, Resp any](method string,
+	call func(DiscoveryAdminServiceServer, context.Context, *Req) (*Resp, error)) grpc.MethodDesc {
+	return grpc.MethodDesc{
+		MethodName: method,
+		Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error,
+			interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+			in := new(Req)
+			if err := dec(in); err != nil {
+				return nil, err
+			}
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				return call(srv.(DiscoveryAdminServiceServer), ctx, req.(*Req))
+			}
+			if interceptor == nil {
+				return handler(ctx, in)
+			}
+			return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: methodName(method)}, handler)
+		},
+	}
+}
+
+// ServerStream is the server side of a server-streaming method, on which the responses are sent
+type ServerStream[Resp any] interface {
+	Send(*Resp) error
+	grpc.ServerStream
+}
+
+type serverStream[Resp any] struct {
+	grpc.ServerStream
+}
+
+func (s *serverStream[Resp]) Send(m *Resp) error {
+	return s.ServerStream.SendMsg(m)
+}
+
+// Returns the descriptor of the server-streaming method, which decodes the request and dispatches it, along with
+// the stream, to the given server method
+func serverStreamingMethod[Req any, Resp any](method string,
+	call func(DiscoveryAdminServiceServer, *Req, ServerStream[Resp]) error) grpc.StreamDesc {
+	return grpc.StreamDesc{
+		StreamName: method,
+		Handler: func(srv interface{}, stream grpc.ServerStream) error {
+			in := new(Req)
+			if err := stream.RecvMsg(in); err != nil {
+				return err
+			}
+			return call(srv.(DiscoveryAdminServiceServer), in, &serverStream[Resp]{ServerStream: stream})
+		},
+		ServerStreams: true,
+	}
+}
+
+// DiscoveryAdminServiceClient is the client API of the DiscoveryAdminService
+type DiscoveryAdminServiceClient interface {
+	UpdateSwitch(ctx context.Context, in *UpdateSwitchRequest, opts ...grpc.CallOption) (*UpdateSwitchResponse, error)
+	UpdateServerIPU(ctx context.Context, in *UpdateServerIPURequest, opts ...grpc.CallOption) (*UpdateServerIPUResponse, error)
+	MoveToRack(ctx context.Context, in *MoveToRackRequest, opts ...grpc.CallOption) (*MoveToRackResponse, error)
+	RemovePod(ctx context.Context, in *RemovePodRequest, opts ...grpc.CallOption) (*RemovePodResponse, error)
+	RemoveRack(ctx context.Context, in *RemoveRackRequest, opts ...grpc.CallOption) (*RemoveRackResponse, error)
+	RemoveSwitch(ctx context.Context, in *RemoveSwitchRequest, opts ...grpc.CallOption) (*RemoveSwitchResponse, error)
+	RemoveServerIPU(ctx context.Context, in *RemoveServerIPURequest, opts ...grpc.CallOption) (*RemoveServerIPUResponse, error)
+	ExportTopology(ctx context.Context, in *ExportTopologyRequest, opts ...grpc.CallOption) (*ExportTopologyResponse, error)
+	ImportInventory(ctx context.Context, in *ImportInventoryRequest, opts ...grpc.CallOption) (*ImportInventoryResponse, error)
+	SetCablingPlan(ctx context.Context, in *SetCablingPlanRequest, opts ...grpc.CallOption) (*SetCablingPlanResponse, error)
+	CablingReport(ctx context.Context, in *CablingReportRequest, opts ...grpc.CallOption) (*CablingReportResponse, error)
+	WatchDiscoveryEvents(ctx context.Context, in *WatchDiscoveryEventsRequest, opts ...grpc.CallOption) (ClientStream[WatchDiscoveryEventsResponse], error)
+	Rediscover(ctx context.Context, in *RediscoverRequest, opts ...grpc.CallOption) (*RediscoverResponse, error)
+	RediscoverStream(ctx context.Context, in *RediscoverRequest, opts ...grpc.CallOption) (ClientStream[RediscoverStreamResponse], error)
+	GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error)
+}
+
+// ClientStream is the client side of a server-streaming method, from which the responses are received
+type ClientStream[Resp any] interface {
+	Recv() (*Resp, error)
+	grpc.ClientStream
+}
+
+type clientStream[Resp any] struct {
+	grpc.ClientStream
+}
+
+func (s *clientStream[Resp]) Recv() (*Resp, error) {
+	m := new(Resp)
+	if err := s.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+type discoveryAdminServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+// NewDiscoveryAdminServiceClient returns a DiscoveryAdminService client using the given connection
+func NewDiscoveryAdminServiceClient(cc grpc.ClientConnInterface) DiscoveryAdminServiceClient {
+	return &discoveryAdminServiceClient{cc: cc}
+}
+
+// Opens a stream for the server-streaming method and sends the request on it
+func openStream[Resp any](ctx context.Context, cc grpc.ClientConnInterface, method string, in interface{},
+	opts []grpc.CallOption) (ClientStream[Resp], error) {
+	stream, err := cc.NewStream(ctx, &grpc.StreamDesc{StreamName: method, ServerStreams: true}, methodName(method), opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err = stream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err = stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return &clientStream[Resp]{ClientStream: stream}, nil
+}
+
+// Invokes the unary method, returning its response
+func invoke[Resp any](ctx context.Context, cc grpc.ClientConnInterface, method string, in interface{},
+	opts []grpc.CallOption) (*Resp, error) {
+	out := new(Resp)
+	if err := cc.Invoke(ctx, methodName(method), in, out, opts...); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *discoveryAdminServiceClient) UpdateSwitch(ctx context.Context, in *UpdateSwitchRequest, opts ...grpc.CallOption) (*UpdateSwitchResponse, error) {
+	return invoke[UpdateSwitchResponse](ctx, c.cc, "UpdateSwitch", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) UpdateServerIPU(ctx context.Context, in *UpdateServerIPURequest, opts ...grpc.CallOption) (*UpdateServerIPUResponse, error) {
+	return invoke[UpdateServerIPUResponse](ctx, c.cc, "UpdateServerIPU", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) MoveToRack(ctx context.Context, in *MoveToRackRequest, opts ...grpc.CallOption) (*MoveToRackResponse, error) {
+	return invoke[MoveToRackResponse](ctx, c.cc, "MoveToRack", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) RemovePod(ctx context.Context, in *RemovePodRequest, opts ...grpc.CallOption) (*RemovePodResponse, error) {
+	return invoke[RemovePodResponse](ctx, c.cc, "RemovePod", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) RemoveRack(ctx context.Context, in *RemoveRackRequest, opts ...grpc.CallOption) (*RemoveRackResponse, error) {
+	return invoke[RemoveRackResponse](ctx, c.cc, "RemoveRack", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) RemoveSwitch(ctx context.Context, in *RemoveSwitchRequest, opts ...grpc.CallOption) (*RemoveSwitchResponse, error) {
+	return invoke[RemoveSwitchResponse](ctx, c.cc, "RemoveSwitch", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) RemoveServerIPU(ctx context.Context, in *RemoveServerIPURequest, opts ...grpc.CallOption) (*RemoveServerIPUResponse, error) {
+	return invoke[RemoveServerIPUResponse](ctx, c.cc, "RemoveServerIPU", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) ExportTopology(ctx context.Context, in *ExportTopologyRequest, opts ...grpc.CallOption) (*ExportTopologyResponse, error) {
+	return invoke[ExportTopologyResponse](ctx, c.cc, "ExportTopology", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) ImportInventory(ctx context.Context, in *ImportInventoryRequest, opts ...grpc.CallOption) (*ImportInventoryResponse, error) {
+	return invoke[ImportInventoryResponse](ctx, c.cc, "ImportInventory", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) SetCablingPlan(ctx context.Context, in *SetCablingPlanRequest, opts ...grpc.CallOption) (*SetCablingPlanResponse, error) {
+	return invoke[SetCablingPlanResponse](ctx, c.cc, "SetCablingPlan", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) CablingReport(ctx context.Context, in *CablingReportRequest, opts ...grpc.CallOption) (*CablingReportResponse, error) {
+	return invoke[CablingReportResponse](ctx, c.cc, "CablingReport", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) WatchDiscoveryEvents(ctx context.Context, in *WatchDiscoveryEventsRequest, opts ...grpc.CallOption) (ClientStream[WatchDiscoveryEventsResponse], error) {
+	return openStream[WatchDiscoveryEventsResponse](ctx, c.cc, "WatchDiscoveryEvents", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) Rediscover(ctx context.Context, in *RediscoverRequest, opts ...grpc.CallOption) (*RediscoverResponse, error) {
+	return invoke[RediscoverResponse](ctx, c.cc, "Rediscover", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) RediscoverStream(ctx context.Context, in *RediscoverRequest, opts ...grpc.CallOption) (ClientStream[RediscoverStreamResponse], error) {
+	return openStream[RediscoverStreamResponse](ctx, c.cc, "RediscoverStream", in, opts)
+}
+
+func (c *discoveryAdminServiceClient) GetStatus(ctx context.Context, in *GetStatusRequest, opts ...grpc.CallOption) (*GetStatusResponse, error) {
+	return invoke[GetStatusResponse](ctx, c.cc, "GetStatus", in, opts)
+}
@@ -8,23 +8,49 @@ package northbound
 import (
 	"context"
 	api "github.com/onosproject/onos-api/go/onos/discovery"
+	"github.com/onosproject/onos-api/go/onos/topo"
 	"github.com/onosproject/onos-lib-go/pkg/errors"
 	"github.com/onosproject/onos-lib-go/pkg/logging"
 	"github.com/onosproject/onos-lib-go/pkg/northbound"
 	"github.com/onosproject/topo-discovery/pkg/controller"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+	"github.com/onosproject/topo-discovery/pkg/northbound/admin"
 	"google.golang.org/grpc"
+	"time"
 )
 
 var log = logging.GetLogger("northbound")
 
+// Controller is the discovery controller functionality exposed by the northbound API
+type Controller interface {
+	AddPod(ctx context.Context, req *api.AddPodRequest) error
+	AddRack(ctx context.Context, req *api.AddRackRequest) error
+	AddSwitch(ctx context.Context, req *api.AddSwitchRequest) error
+	AddServerIPU(ctx context.Context, req *api.AddServerIPURequest) error
+	UpdateSwitch(ctx context.Context, id string, info *api.ManagementInfo) error
+	UpdateServerIPU(ctx context.Context, id string, info *api.ManagementInfo) error
+	MoveToRack(ctx context.Context, id string, podID string, rackID string) error
+	RemovePod(ctx context.Context, id string) (*controller.RemovalReport, error)
+	RemoveRack(ctx context.Context, id string) (*controller.RemovalReport, error)
+	RemoveSwitch(ctx context.Context, id string) (*controller.RemovalReport, error)
+	RemoveServerIPU(ctx context.Context, id string) (*controller.RemovalReport, error)
+	ImportInventory(ctx context.Context, inv *inventory.Inventory) (*inventory.Report, error)
+	ExportTopology(ctx context.Context) (*inventory.Topology, error)
+	SetCablingPlan(plan *inventory.CablingPlan)
+	CablingReport(ctx context.Context) (*inventory.CablingReport, error)
+	WatchEvents(ctx context.Context, filter *controller.EventFilter) <-chan *controller.Event
+	Rediscover(ctx context.Context, deviceID topo.ID, reconnect bool) (<-chan *controller.DeviceResult, error)
+	GetStatus() *controller.Status
+}
+
 // Service implements the topology discovery NB gRPC
 type Service struct {
 	northbound.Service
-	controller *controller.Controller
+	controller Controller
 }
 
 // NewService allocates a Service struct with the given parameters
-func NewService(controller *controller.Controller) Service {
+func NewService(controller Controller) Service {
 	return Service{
 		controller: controller,
 	}
@@ -36,12 +62,13 @@ func (s Service) Register(r *grpc.Server) {
 		controller: s.controller,
 	}
 	api.RegisterDiscoveryServiceServer(r, server)
+	admin.RegisterDiscoveryAdminServiceServer(r, server)
 	log.Debug("Topology Discovery API services registered")
 }
 
-// Server implements the grpc topology discovery service
+// Server implements the grpc topology discovery and discovery admin services
 type Server struct {
-	controller *controller.Controller
+	controller Controller
 }
 
 // AddPod adds a new POD entity with the requisite aspects
@@ -83,3 +110,307 @@ func (s *Server) AddServerIPU(ctx context.Context, request *api.AddServerIPURequ
 	}
 	return &api.AddServerIPUResponse{}, nil
 }
+
+// UpdateSwitch updates the management info aspects and labels of an existing switch entity
+func (s *Server) UpdateSwitch(ctx context.Context, request *admin.UpdateSwitchRequest) (*admin.UpdateSwitchResponse, error) {
+	log.Infof("Updating switch %s", request.ID)
+	if err := s.controller.UpdateSwitch(ctx, request.ID, request.ManagementInfo); err != nil {
+		log.Warnf("Failed updating switch %s: %v", request.ID, err)
+		return nil, errors.Status(err).Err()
+	}
+	return &admin.UpdateSwitchResponse{}, nil
+}
+
+// UpdateServerIPU updates the management info of an existing server entity and its associated IPU entity
+func (s *Server) UpdateServerIPU(ctx context.Context, request *admin.UpdateServerIPURequest) (*admin.UpdateServerIPUResponse, error) {
+	log.Infof("Updating server IPU %s", request.ID)
+	if err := s.controller.UpdateServerIPU(ctx, request.ID, request.ManagementInfo); err != nil {
+		log.Warnf("Failed updating server IPU %s: %v", request.ID, err)
+		return nil, errors.Status(err).Err()
+	}
+	return &admin.UpdateServerIPUResponse{}, nil
+}
+
+// MoveToRack moves an existing switch or server entity into a different rack
+func (s *Server) MoveToRack(ctx context.Context, request *admin.MoveToRackRequest) (*admin.MoveToRackResponse, error) {
+	log.Infof("Moving %s to rack %s", request.ID, request.RackID)
+	if err := s.controller.MoveToRack(ctx, request.ID, request.PodID, request.RackID); err != nil {
+		log.Warnf("Failed moving %s to rack %s: %v", request.ID, request.RackID, err)
+		return nil, errors.Status(err).Err()
+	}
+	return &admin.MoveToRackResponse{}, nil
+}
+
+// RemovePod removes a POD entity along with all its racks and everything they contain
+func (s *Server) RemovePod(ctx context.Context, request *admin.RemovePodRequest) (*admin.RemovePodResponse, error) {
+	log.Infof("Removing pod %s", request.ID)
+	report, err := s.controller.RemovePod(ctx, request.ID)
+	if err != nil {
+		log.Warnf("Failed removing pod %s: %v", request.ID, err)
+		if !removedAny(report) {
+			return nil, errors.Status(err).Err()
+		}
+		return &admin.RemovePodResponse{Removed: removedObjects(report), Error: err.Error()}, nil
+	}
+	return &admin.RemovePodResponse{Removed: removedObjects(report)}, nil
+}
+
+// RemoveRack removes a rack entity along with all switches and servers it contains
+func (s *Server) RemoveRack(ctx context.Context, request *admin.RemoveRackRequest) (*admin.RemoveRackResponse, error) {
+	log.Infof("Removing rack %s", request.ID)
+	report, err := s.controller.RemoveRack(ctx, request.ID)
+	if err != nil {
+		log.Warnf("Failed removing rack %s: %v", request.ID, err)
+		if !removedAny(report) {
+			return nil, errors.Status(err).Err()
+		}
+		return &admin.RemoveRackResponse{Removed: removedObjects(report), Error: err.Error()}, nil
+	}
+	return &admin.RemoveRackResponse{Removed: removedObjects(report)}, nil
+}
+
+// RemoveSwitch removes a switch entity along with its ports and any links and hosts attached to them
+func (s *Server) RemoveSwitch(ctx context.Context, request *admin.RemoveSwitchRequest) (*admin.RemoveSwitchResponse, error) {
+	log.Infof("Removing switch %s", request.ID)
+	report, err := s.controller.RemoveSwitch(ctx, request.ID)
+	if err != nil {
+		log.Warnf("Failed removing switch %s: %v", request.ID, err)
+		if !removedAny(report) {
+			return nil, errors.Status(err).Err()
+		}
+		return &admin.RemoveSwitchResponse{Removed: removedObjects(report), Error: err.Error()}, nil
+	}
+	return &admin.RemoveSwitchResponse{Removed: removedObjects(report)}, nil
+}
+
+// RemoveServerIPU removes a server entity and its associated IPU entity, along with the IPU ports, links and hosts
+func (s *Server) RemoveServerIPU(ctx context.Context, request *admin.RemoveServerIPURequest) (*admin.RemoveServerIPUResponse, error) {
+	log.Infof("Removing server IPU %s", request.ID)
+	report, err := s.controller.RemoveServerIPU(ctx, request.ID)
+	if err != nil {
+		log.Warnf("Failed removing server IPU %s: %v", request.ID, err)
+		if !removedAny(report) {
+			return nil, errors.Status(err).Err()
+		}
+		return &admin.RemoveServerIPUResponse{Removed: removedObjects(report), Error: err.Error()}, nil
+	}
+	return &admin.RemoveServerIPUResponse{Removed: removedObjects(report)}, nil
+}
+
+// Returns true if the removal report lists any objects, i.e. if a failed removal got partway
+func removedAny(report *controller.RemovalReport) bool {
+	return report != nil && len(report.Objects) > 0
+}
+
+// Returns the objects listed in the removal report
+func removedObjects(report *controller.RemovalReport) []*admin.RemovedObject {
+	removed := make([]*admin.RemovedObject, 0, len(report.Objects))
+	for _, object := range report.Objects {
+		removed = append(removed, &admin.RemovedObject{ID: string(object.ID), Kind: string(object.Kind)})
+	}
+	return removed
+}
+
+// ExportTopology renders the realm's pods, racks, switches, server IPUs and their discovered ports, links and hosts
+// as a stable YAML or JSON document
+func (s *Server) ExportTopology(ctx context.Context, request *admin.ExportTopologyRequest) (*admin.ExportTopologyResponse, error) {
+	log.Infof("Exporting topology")
+	topology, err := s.controller.ExportTopology(ctx)
+	if err != nil {
+		log.Warnf("Failed exporting topology: %v", err)
+		return nil, errors.Status(err).Err()
+	}
+	data, err := topology.Encode(request.Format)
+	if err != nil {
+		log.Warnf("Failed encoding topology: %v", err)
+		return nil, errors.Status(err).Err()
+	}
+	return &admin.ExportTopologyResponse{Document: data}, nil
+}
+
+// ImportInventory validates the given inventory document and adds all its assets, reporting the outcome for each one
+func (s *Server) ImportInventory(ctx context.Context, request *admin.ImportInventoryRequest) (*admin.ImportInventoryResponse, error) {
+	inv, err := inventory.Parse(request.Document)
+	if err != nil {
+		log.Warnf("Failed parsing inventory: %v", err)
+		return nil, errors.Status(err).Err()
+	}
+	log.Infof("Importing inventory of %d pods", len(inv.Pods))
+	report, err := s.controller.ImportInventory(ctx, inv)
+	if err != nil {
+		log.Warnf("Failed importing inventory: %v", err)
+		return nil, errors.Status(err).Err()
+	}
+	results := make([]*admin.ImportResult, 0, len(report.Results))
+	for _, result := range report.Results {
+		results = append(results, &admin.ImportResult{Kind: result.Kind, ID: result.ID, Status: string(result.Status),
+			Message: result.Message})
+	}
+	return &admin.ImportInventoryResponse{Results: results}, nil
+}
+
+// SetCablingPlan sets the expected cabling plan against which the discovered links are checked; an empty plan
+// clears it
+func (s *Server) SetCablingPlan(ctx context.Context, request *admin.SetCablingPlanRequest) (*admin.SetCablingPlanResponse, error) {
+	if len(request.Cables) == 0 {
+		log.Infof("Clearing cabling plan")
+		s.controller.SetCablingPlan(nil)
+		return &admin.SetCablingPlanResponse{}, nil
+	}
+
+	log.Infof("Setting cabling plan with %d cables", len(request.Cables))
+	cables := make([]*inventory.Cable, 0, len(request.Cables))
+	for _, cable := range request.Cables {
+		cables = append(cables, &inventory.Cable{Source: cable.Source, Target: cable.Target,
+			Unidirectional: cable.Unidirectional})
+	}
+	plan, err := inventory.NewCablingPlan(cables...)
+	if err != nil {
+		log.Warnf("Failed setting cabling plan: %v", err)
+		return nil, errors.Status(err).Err()
+	}
+	s.controller.SetCablingPlan(plan)
+	return &admin.SetCablingPlanResponse{}, nil
+}
+
+// CablingReport verifies the discovered links against the cabling plan, reporting the missing, extra and
+// miswired links
+func (s *Server) CablingReport(ctx context.Context, request *admin.CablingReportRequest) (*admin.CablingReportResponse, error) {
+	log.Infof("Verifying cabling")
+	report, err := s.controller.CablingReport(ctx)
+	if err != nil {
+		log.Warnf("Failed verifying cabling: %v", err)
+		return nil, errors.Status(err).Err()
+	}
+	results := make([]*admin.CablingResult, 0, len(report.Results))
+	for _, result := range report.Results {
+		results = append(results, &admin.CablingResult{Source: result.Source, Target: result.Target,
+			Status: string(result.Status), LinkID: result.LinkID, Expected: result.Expected})
+	}
+	return &admin.CablingReportResponse{Results: results}, nil
+}
+
+// WatchDiscoveryEvents sends discovery events passing the given filter on the stream until the stream is closed
+func (s *Server) WatchDiscoveryEvents(request *admin.WatchDiscoveryEventsRequest,
+	stream admin.ServerStream[admin.WatchDiscoveryEventsResponse]) error {
+	filter := &controller.EventFilter{}
+	for _, id := range request.DeviceIDs {
+		filter.DeviceIDs = append(filter.DeviceIDs, topo.ID(id))
+	}
+	for _, t := range request.Types {
+		filter.Types = append(filter.Types, controller.EventType(t))
+	}
+
+	log.Infof("Starting to watch discovery events: %+v", filter)
+	for event := range s.controller.WatchEvents(stream.Context(), filter) {
+		if err := stream.Send(&admin.WatchDiscoveryEventsResponse{Event: discoveryEvent(event)}); err != nil {
+			log.Warnf("Failed sending discovery event: %v", err)
+			return errors.Status(errors.FromGRPC(err)).Err()
+		}
+	}
+	log.Infof("Stopped watching discovery events")
+	return nil
+}
+
+// Returns the API representation of the discovery event
+func discoveryEvent(event *controller.Event) *admin.DiscoveryEvent {
+	return &admin.DiscoveryEvent{
+		Type:         string(event.Type),
+		Timestamp:    event.Timestamp.UnixNano(),
+		ObjectID:     string(event.ObjectID),
+		DeviceID:     string(event.DeviceID),
+		PeerDeviceID: string(event.PeerDeviceID),
+		AgentID:      event.AgentID,
+		Message:      event.Message,
+	}
+}
+
+// Rediscover immediately runs the discovery passes for the specified device, or for all realm devices if no
+// device ID is given, optionally forcing reconnection to their agents; blocks until all passes complete
+func (s *Server) Rediscover(ctx context.Context, request *admin.RediscoverRequest) (*admin.RediscoverResponse, error) {
+	log.Infof("Rediscovering %q; reconnect: %t", request.DeviceID, request.Reconnect)
+	results, err := s.controller.Rediscover(ctx, topo.ID(request.DeviceID), request.Reconnect)
+	if err != nil {
+		log.Warnf("Failed rediscovering %q: %v", request.DeviceID, err)
+		return nil, errors.Status(err).Err()
+	}
+	list := make([]*admin.DeviceResult, 0)
+	for result := range results {
+		list = append(list, deviceResult(result))
+	}
+	return &admin.RediscoverResponse{Results: list}, nil
+}
+
+// RediscoverStream is the streaming variant of Rediscover, which sends the result for each device as soon as its
+// discovery passes complete
+func (s *Server) RediscoverStream(request *admin.RediscoverRequest,
+	stream admin.ServerStream[admin.RediscoverStreamResponse]) error {
+	log.Infof("Rediscovering %q; reconnect: %t", request.DeviceID, request.Reconnect)
+	results, err := s.controller.Rediscover(stream.Context(), topo.ID(request.DeviceID), request.Reconnect)
+	if err != nil {
+		log.Warnf("Failed rediscovering %q: %v", request.DeviceID, err)
+		return errors.Status(err).Err()
+	}
+	for result := range results {
+		if err = stream.Send(&admin.RediscoverStreamResponse{Result: deviceResult(result)}); err != nil {
+			log.Warnf("Failed sending rediscovery result: %v", err)
+			return errors.Status(errors.FromGRPC(err)).Err()
+		}
+	}
+	return nil
+}
+
+// Returns the API representation of the device discovery result
+func deviceResult(result *controller.DeviceResult) *admin.DeviceResult {
+	phases := make([]*admin.PhaseResult, 0, len(result.Phases))
+	for _, phase := range result.Phases {
+		phases = append(phases, &admin.PhaseResult{Phase: string(phase.Phase), Duration: int64(phase.Duration),
+			Error: phase.Error})
+	}
+	return &admin.DeviceResult{DeviceID: string(result.DeviceID), Phases: phases, Error: result.Error}
+}
+
+// GetStatus returns the controller state, the last full discovery sweep, queue depths, per-device discovery status and
+// the flap history of links and ports
+func (s *Server) GetStatus(ctx context.Context, request *admin.GetStatusRequest) (*admin.GetStatusResponse, error) {
+	status := s.controller.GetStatus()
+	devices := make([]*admin.DeviceStatus, 0, len(status.Devices))
+	for _, device := range status.Devices {
+		devices = append(devices, &admin.DeviceStatus{
+			DeviceID:          string(device.DeviceID),
+			LastPortDiscovery: unixNanos(device.LastPortDiscovery),
+			LastLinkDiscovery: unixNanos(device.LastLinkDiscovery),
+			LastHostDiscovery: unixNanos(device.LastHostDiscovery),
+			LastError:         device.LastError,
+			LastErrorTime:     unixNanos(device.LastErrorTime),
+			AgentID:           device.AgentID,
+			PortMonitor:       device.PortMonitor,
+			LinkMonitor:       device.LinkMonitor,
+			HostMonitor:       device.HostMonitor,
+		})
+	}
+	flaps := make([]*admin.FlapStatus, 0, len(status.Flaps))
+	for _, flap := range status.Flaps {
+		flaps = append(flaps, &admin.FlapStatus{ID: string(flap.ID), Kind: flap.Kind, Status: flap.Status,
+			Flaps: flap.Flaps, Penalty: flap.Penalty, Suppressed: flap.Suppressed})
+	}
+	return &admin.GetStatusResponse{
+		State:                   status.State.String(),
+		LastSweep:               unixNanos(status.LastSweep),
+		LastSweepDuration:       int64(status.LastSweepDuration),
+		LastSweepError:          status.LastSweepError,
+		RealmQueueDepth:         int32(status.RealmQueueDepth),
+		NeighborRealmQueueDepth: int32(status.NeighborRealmQueueDepth),
+		WorkingOn:               int32(status.WorkingOn),
+		Devices:                 devices,
+		Flaps:                   flaps,
+	}, nil
+}
+
+// Returns the time in nanoseconds since the Unix epoch; 0 for the zero time
+func unixNanos(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
@@ -0,0 +1,381 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package northbound
+
+import (
+	"context"
+	api "github.com/onosproject/onos-api/go/onos/discovery"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"github.com/onosproject/topo-discovery/pkg/controller"
+	"github.com/onosproject/topo-discovery/pkg/inventory"
+	"github.com/onosproject/topo-discovery/pkg/northbound/admin"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// Stand-in for the discovery controller, recording the operations it is asked to perform
+type fakeController struct {
+	Controller
+	removed []string
+	updated map[string]*api.ManagementInfo
+	moved   map[string]string
+	plan    *inventory.CablingPlan
+
+	events   *controller.EventBroker
+	watching chan struct{}
+}
+
+func (c *fakeController) update(id string, info *api.ManagementInfo) error {
+	if id == "missing" {
+		return errors.NewNotFound("%s not found", id)
+	}
+	if c.updated == nil {
+		c.updated = make(map[string]*api.ManagementInfo)
+	}
+	c.updated[id] = info
+	return nil
+}
+
+func (c *fakeController) UpdateSwitch(ctx context.Context, id string, info *api.ManagementInfo) error {
+	return c.update(id, info)
+}
+
+func (c *fakeController) UpdateServerIPU(ctx context.Context, id string, info *api.ManagementInfo) error {
+	return c.update(id, info)
+}
+
+func (c *fakeController) MoveToRack(ctx context.Context, id string, podID string, rackID string) error {
+	if rackID == "missing" {
+		return errors.NewNotFound("rack %s not found", rackID)
+	}
+	if c.moved == nil {
+		c.moved = make(map[string]string)
+	}
+	c.moved[id] = podID + "/" + rackID
+	return nil
+}
+
+func (c *fakeController) remove(id string, kind topo.ID) (*controller.RemovalReport, error) {
+	if id == "missing" {
+		return nil, errors.NewNotFound("%s not found", id)
+	}
+	if id == "stuck" {
+		return &controller.RemovalReport{Objects: []controller.RemovedObject{{ID: "stuck/1", Kind: topo.PortKind}}},
+			errors.NewUnavailable("onos-topo unavailable")
+	}
+	c.removed = append(c.removed, id)
+	return &controller.RemovalReport{Objects: []controller.RemovedObject{
+		{ID: topo.ID(id), Kind: kind},
+		{ID: topo.ID(id + "-contains"), Kind: topo.CONTAINS},
+	}}, nil
+}
+
+func (c *fakeController) RemovePod(ctx context.Context, id string) (*controller.RemovalReport, error) {
+	return c.remove(id, topo.PodKind)
+}
+
+func (c *fakeController) RemoveRack(ctx context.Context, id string) (*controller.RemovalReport, error) {
+	return c.remove(id, topo.RackKind)
+}
+
+func (c *fakeController) RemoveSwitch(ctx context.Context, id string) (*controller.RemovalReport, error) {
+	return c.remove(id, topo.SwitchKind)
+}
+
+func (c *fakeController) RemoveServerIPU(ctx context.Context, id string) (*controller.RemovalReport, error) {
+	return c.remove(id, topo.ServerKind)
+}
+
+func (c *fakeController) ImportInventory(ctx context.Context, inv *inventory.Inventory) (*inventory.Report, error) {
+	if err := inv.Validate(); err != nil {
+		return nil, err
+	}
+	report := &inventory.Report{}
+	for _, pod := range inv.Pods {
+		report.Results = append(report.Results, &inventory.Result{Kind: "pod", ID: pod.ID, Status: inventory.OK})
+		for _, rack := range pod.Racks {
+			report.Results = append(report.Results, &inventory.Result{Kind: "rack", ID: rack.ID,
+				Status: inventory.Failed, Message: "unreachable"})
+		}
+	}
+	return report, nil
+}
+
+func (c *fakeController) ExportTopology(ctx context.Context) (*inventory.Topology, error) {
+	return &inventory.Topology{Links: []*inventory.Link{{ID: "leaf1/1-leaf2/1", Source: "leaf1/1", Target: "leaf2/1"}}}, nil
+}
+
+func (c *fakeController) SetCablingPlan(plan *inventory.CablingPlan) {
+	c.plan = plan
+}
+
+func (c *fakeController) CablingReport(ctx context.Context) (*inventory.CablingReport, error) {
+	if c.plan == nil {
+		return nil, errors.NewNotFound("no cabling plan has been loaded")
+	}
+	return c.plan.Verify([]*inventory.Link{{ID: "leaf1/2-spine2/1", Source: "leaf1/2", Target: "spine2/1"}}, "UP"), nil
+}
+
+func (c *fakeController) WatchEvents(ctx context.Context, filter *controller.EventFilter) <-chan *controller.Event {
+	ch := c.events.Watch(ctx, filter)
+	c.watching <- struct{}{}
+	return ch
+}
+
+func (c *fakeController) Rediscover(ctx context.Context, deviceID topo.ID, reconnect bool) (<-chan *controller.DeviceResult, error) {
+	if deviceID == "missing" {
+		return nil, errors.NewNotFound("device %s not found", deviceID)
+	}
+	results := make(chan *controller.DeviceResult, 2)
+	for _, id := range []topo.ID{"leaf1", "leaf2"} {
+		if len(deviceID) == 0 || id == deviceID {
+			result := &controller.DeviceResult{DeviceID: id, Phases: []*controller.PhaseResult{
+				{Phase: controller.PortsPhase, Duration: time.Millisecond}}}
+			if reconnect {
+				result.Phases[0].Error = "reconnecting"
+			}
+			results <- result
+		}
+	}
+	close(results)
+	return results, nil
+}
+
+func (c *fakeController) GetStatus() *controller.Status {
+	return &controller.Status{State: controller.Monitoring, LastSweep: time.Unix(1700000000, 0),
+		LastSweepDuration: time.Second, RealmQueueDepth: 2, WorkingOn: 1,
+		Devices: []*controller.DeviceStatus{{DeviceID: "leaf1", AgentID: "agent1", LastError: "unreachable",
+			LastErrorTime: time.Unix(1700000001, 0), PortMonitor: true}},
+		Flaps: []*controller.FlapStatus{{ID: "leaf1/1-spine1/1", Kind: "link", Status: "down", Flaps: 3,
+			Penalty: 2500, Suppressed: true}}}
+}
+
+// Serves the northbound API backed by the given controller on an in-memory listener; returns a connection to it
+func newTestConnection(t *testing.T, c Controller) *grpc.ClientConn {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	NewService(c).Register(server)
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	assert.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestRemove(t *testing.T) {
+	c := &fakeController{}
+	client := admin.NewDiscoveryAdminServiceClient(newTestConnection(t, c))
+	ctx := context.Background()
+
+	pod, err := client.RemovePod(ctx, &admin.RemovePodRequest{ID: "pod1"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*admin.RemovedObject{{ID: "pod1", Kind: string(topo.PodKind)},
+		{ID: "pod1-contains", Kind: string(topo.CONTAINS)}}, pod.Removed)
+
+	rack, err := client.RemoveRack(ctx, &admin.RemoveRackRequest{ID: "rack1"})
+	assert.NoError(t, err)
+	assert.Equal(t, string(topo.RackKind), rack.Removed[0].Kind)
+
+	sw, err := client.RemoveSwitch(ctx, &admin.RemoveSwitchRequest{ID: "leaf1"})
+	assert.NoError(t, err)
+	assert.Equal(t, string(topo.SwitchKind), sw.Removed[0].Kind)
+
+	ipu, err := client.RemoveServerIPU(ctx, &admin.RemoveServerIPURequest{ID: "server1"})
+	assert.NoError(t, err)
+	assert.Len(t, ipu.Removed, 2)
+	assert.Equal(t, []string{"pod1", "rack1", "leaf1", "server1"}, c.removed)
+
+	// Failures are reported with the matching status code
+	_, err = client.RemoveSwitch(ctx, &admin.RemoveSwitchRequest{ID: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Removals failing partway report the objects removed until then, along with the error
+	sw, err = client.RemoveSwitch(ctx, &admin.RemoveSwitchRequest{ID: "stuck"})
+	assert.NoError(t, err)
+	assert.Equal(t, []*admin.RemovedObject{{ID: "stuck/1", Kind: string(topo.PortKind)}}, sw.Removed)
+	assert.Contains(t, sw.Error, "onos-topo unavailable")
+}
+
+func TestUpdate(t *testing.T) {
+	c := &fakeController{}
+	client := admin.NewDiscoveryAdminServiceClient(newTestConnection(t, c))
+	ctx := context.Background()
+
+	_, err := client.UpdateSwitch(ctx, &admin.UpdateSwitchRequest{ID: "leaf1",
+		ManagementInfo: &api.ManagementInfo{P4RTEndpoint: "leaf1:9559", GNMIEndpoint: "leaf1:9339", DeviceID: 1}})
+	assert.NoError(t, err)
+	assert.Equal(t, "leaf1:9559", c.updated["leaf1"].P4RTEndpoint)
+	assert.Equal(t, "leaf1:9339", c.updated["leaf1"].GNMIEndpoint)
+	assert.Equal(t, uint64(1), c.updated["leaf1"].DeviceID)
+
+	_, err = client.UpdateServerIPU(ctx, &admin.UpdateServerIPURequest{ID: "server1",
+		ManagementInfo: &api.ManagementInfo{P4RTEndpoint: "ipu1:9559"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "ipu1:9559", c.updated["server1"].P4RTEndpoint)
+
+	_, err = client.MoveToRack(ctx, &admin.MoveToRackRequest{ID: "leaf1", PodID: "pod1", RackID: "rack2"})
+	assert.NoError(t, err)
+	assert.Equal(t, "pod1/rack2", c.moved["leaf1"])
+
+	_, err = client.UpdateSwitch(ctx, &admin.UpdateSwitchRequest{ID: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+	_, err = client.MoveToRack(ctx, &admin.MoveToRackRequest{ID: "leaf1", PodID: "pod1", RackID: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestImportInventory(t *testing.T) {
+	client := admin.NewDiscoveryAdminServiceClient(newTestConnection(t, &fakeController{}))
+	ctx := context.Background()
+
+	resp, err := client.ImportInventory(ctx, &admin.ImportInventoryRequest{
+		Document: []byte(`{"pods": [{"id": "pod1", "racks": [{"id": "rack1"}]}]}`)})
+	assert.NoError(t, err)
+	assert.Equal(t, []*admin.ImportResult{{Kind: "pod", ID: "pod1", Status: "OK"},
+		{Kind: "rack", ID: "rack1", Status: "FAILED", Message: "unreachable"}}, resp.Results)
+
+	_, err = client.ImportInventory(ctx, &admin.ImportInventoryRequest{Document: []byte("pods: [{id: pod1, color: red}]")})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.ImportInventory(ctx, &admin.ImportInventoryRequest{Document: []byte("pods: [{id: ''}]")})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestExportTopology(t *testing.T) {
+	client := admin.NewDiscoveryAdminServiceClient(newTestConnection(t, &fakeController{}))
+	ctx := context.Background()
+
+	resp, err := client.ExportTopology(ctx, &admin.ExportTopologyRequest{Format: inventory.JSON})
+	assert.NoError(t, err)
+	assert.Contains(t, string(resp.Document), `"source": "leaf1/1"`)
+
+	resp, err = client.ExportTopology(ctx, &admin.ExportTopologyRequest{})
+	assert.NoError(t, err)
+	assert.Contains(t, string(resp.Document), "source: leaf1/1")
+
+	_, err = client.ExportTopology(ctx, &admin.ExportTopologyRequest{Format: "xml"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestCablingPlan(t *testing.T) {
+	c := &fakeController{}
+	client := admin.NewDiscoveryAdminServiceClient(newTestConnection(t, c))
+	ctx := context.Background()
+
+	_, err := client.CablingReport(ctx, &admin.CablingReportRequest{})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.SetCablingPlan(ctx, &admin.SetCablingPlanRequest{Cables: []*admin.Cable{
+		{Source: "spine1/1", Target: "leaf1/1"},
+		{Source: "leaf1/2", Target: "spine2/2", Unidirectional: true},
+	}})
+	assert.NoError(t, err)
+	report, err := client.CablingReport(ctx, &admin.CablingReportRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, []*admin.CablingResult{
+		{Source: "leaf1/1", Target: "spine1/1", Status: string(inventory.CablingMissing)},
+		{Source: "leaf1/2", Target: "spine2/1", Status: string(inventory.CablingMiswired), LinkID: "leaf1/2-spine2/1",
+			Expected: "spine2/2"},
+		{Source: "leaf1/2", Target: "spine2/2", Status: string(inventory.CablingMissing)},
+		{Source: "spine1/1", Target: "leaf1/1", Status: string(inventory.CablingMissing)},
+	}, report.Results)
+
+	// Invalid plans are rejected and an empty one clears the plan
+	_, err = client.SetCablingPlan(ctx, &admin.SetCablingPlanRequest{Cables: []*admin.Cable{{Source: "spine1/1"}}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.NotNil(t, c.plan)
+	_, err = client.SetCablingPlan(ctx, &admin.SetCablingPlanRequest{})
+	assert.NoError(t, err)
+	assert.Nil(t, c.plan)
+}
+
+func TestWatchDiscoveryEvents(t *testing.T) {
+	c := &fakeController{events: controller.NewEventBroker(), watching: make(chan struct{}, 1)}
+	client := admin.NewDiscoveryAdminServiceClient(newTestConnection(t, c))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.WatchDiscoveryEvents(ctx, &admin.WatchDiscoveryEventsRequest{
+		DeviceIDs: []string{"leaf1"}, Types: []string{string(controller.LinkDown)}})
+	assert.NoError(t, err)
+	<-c.watching
+
+	// Only the events passing the filter are streamed
+	timestamp := time.Unix(1700000000, 42)
+	c.events.Publish(&controller.Event{Type: controller.LinkUp, ObjectID: "leaf1/1-spine1/1", DeviceID: "spine1",
+		PeerDeviceID: "leaf1"})
+	c.events.Publish(&controller.Event{Type: controller.LinkDown, ObjectID: "leaf2/1-spine1/2", DeviceID: "spine1",
+		PeerDeviceID: "leaf2"})
+	c.events.Publish(&controller.Event{Type: controller.LinkDown, Timestamp: timestamp, ObjectID: "leaf1/1-spine1/1",
+		DeviceID: "spine1", PeerDeviceID: "leaf1", AgentID: "agent1", Message: "port down"})
+	resp, err := stream.Recv()
+	assert.NoError(t, err)
+	assert.Equal(t, &admin.DiscoveryEvent{Type: string(controller.LinkDown), Timestamp: timestamp.UnixNano(),
+		ObjectID: "leaf1/1-spine1/1", DeviceID: "spine1", PeerDeviceID: "leaf1", AgentID: "agent1",
+		Message: "port down"}, resp.Event)
+
+	cancel()
+	_, err = stream.Recv()
+	assert.Equal(t, codes.Canceled, status.Code(err))
+}
+
+func TestRediscover(t *testing.T) {
+	client := admin.NewDiscoveryAdminServiceClient(newTestConnection(t, &fakeController{}))
+	ctx := context.Background()
+
+	resp, err := client.Rediscover(ctx, &admin.RediscoverRequest{DeviceID: "leaf1", Reconnect: true})
+	assert.NoError(t, err)
+	assert.Equal(t, []*admin.DeviceResult{{DeviceID: "leaf1", Phases: []*admin.PhaseResult{
+		{Phase: string(controller.PortsPhase), Duration: int64(time.Millisecond), Error: "reconnecting"}}}}, resp.Results)
+
+	_, err = client.Rediscover(ctx, &admin.RediscoverRequest{DeviceID: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// The streaming variant sends the result of each device separately
+	stream, err := client.RediscoverStream(ctx, &admin.RediscoverRequest{})
+	assert.NoError(t, err)
+	devices := make([]string, 0)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		assert.NoError(t, err)
+		devices = append(devices, resp.Result.DeviceID)
+	}
+	assert.Equal(t, []string{"leaf1", "leaf2"}, devices)
+
+	stream, err = client.RediscoverStream(ctx, &admin.RediscoverRequest{DeviceID: "missing"})
+	assert.NoError(t, err)
+	_, err = stream.Recv()
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGetStatus(t *testing.T) {
+	client := admin.NewDiscoveryAdminServiceClient(newTestConnection(t, &fakeController{}))
+	resp, err := client.GetStatus(context.Background(), &admin.GetStatusRequest{})
+	assert.NoError(t, err)
+	assert.Equal(t, &admin.GetStatusResponse{State: "Monitoring", LastSweep: time.Unix(1700000000, 0).UnixNano(),
+		LastSweepDuration: int64(time.Second), RealmQueueDepth: 2, WorkingOn: 1,
+		Devices: []*admin.DeviceStatus{{DeviceID: "leaf1", AgentID: "agent1", LastError: "unreachable",
+			LastErrorTime: time.Unix(1700000001, 0).UnixNano(), PortMonitor: true}},
+		Flaps: []*admin.FlapStatus{{ID: "leaf1/1-spine1/1", Kind: "link", Status: "down", Flaps: 3,
+			Penalty: 2500, Suppressed: true}}}, resp)
+}
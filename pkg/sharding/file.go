@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const memberFileSuffix = ".member"
+
+// Implementation of MembershipStore keeping each membership in its own file in a local directory; a stand-in for
+// onos-topo for testing and for instances sharing a single host
+type fileMembershipStore struct {
+	dir string
+}
+
+// NewFileMembershipStore creates a membership store backed by files in the specified directory
+func NewFileMembershipStore(dir string) MembershipStore {
+	return &fileMembershipStore{dir: dir}
+}
+
+func (s *fileMembershipStore) Heartbeat(ctx context.Context, id string, expires time.Time) error {
+	data, err := json.Marshal(&Member{ID: id, Expires: expires})
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(s.dir, 0755); err != nil {
+		return err
+	}
+	// Write to a temporary file first so that readers never see a partially written membership
+	path := s.path(id)
+	tmp := filepath.Join(s.dir, "."+filepath.Base(path)+".tmp")
+	if err = os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *fileMembershipStore) Members(ctx context.Context) ([]Member, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	members := make([]Member, 0, len(entries))
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !strings.HasSuffix(entry.Name(), memberFileSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			// The member may have just left
+			continue
+		}
+		member := Member{}
+		if err = json.Unmarshal(data, &member); err != nil {
+			log.Warnf("Membership file %s is corrupt: %+v", entry.Name(), err)
+			continue
+		}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+func (s *fileMembershipStore) Leave(ctx context.Context, id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// Returns the path of the membership file of the given instance
+func (s *fileMembershipStore) path(id string) string {
+	return filepath.Join(s.dir, id+memberFileSuffix)
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding
+
+import (
+	"context"
+	"github.com/onosproject/onos-lib-go/pkg/logging"
+	"sync"
+	"time"
+)
+
+var log = logging.GetLogger("sharding")
+
+// Member describes a discovery instance taking part in sharding and when its membership expires unless renewed
+type Member struct {
+	ID      string    `json:"id"`
+	Expires time.Time `json:"expires"`
+}
+
+// MembershipStore persists the memberships of all discovery instances sharing the realm
+type MembershipStore interface {
+	// Heartbeat creates or renews the membership of the given instance until the given time
+	Heartbeat(ctx context.Context, id string, expires time.Time) error
+	// Members returns all memberships, including the expired ones
+	Members(ctx context.Context) ([]Member, error)
+	// Leave removes the membership of the given instance
+	Leave(ctx context.Context, id string) error
+}
+
+// Sharder keeps the membership of a discovery instance alive and tracks the live members, rebuilding the hash
+// ring and invoking the rebalance callback whenever instances join or leave
+type Sharder struct {
+	id        string
+	store     MembershipStore
+	ttl       time.Duration
+	interval  time.Duration
+	rebalance func(ring *Ring)
+
+	lock sync.RWMutex
+	ring *Ring
+}
+
+// NewSharder creates a sharder for the instance with the given ID. Memberships are renewed every third of their
+// time-to-live, so the devices of an instance which fails are taken over by the remaining ones within the
+// time-to-live plus the renew interval.
+func NewSharder(id string, store MembershipStore, ttl time.Duration, rebalance func(ring *Ring)) *Sharder {
+	return &Sharder{
+		id:        id,
+		store:     store,
+		ttl:       ttl,
+		interval:  ttl / 3,
+		rebalance: rebalance,
+	}
+}
+
+// Ring returns the current hash ring, or nil prior to the first successful refresh
+func (s *Sharder) Ring() *Ring {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.ring
+}
+
+// Refresh renews the membership of the instance and rebuilds the hash ring from the live members; if the
+// membership store is not reachable, the current ring is retained
+func (s *Sharder) Refresh(ctx context.Context) error {
+	now := time.Now()
+	if err := s.store.Heartbeat(ctx, s.id, now.Add(s.ttl)); err != nil {
+		return err
+	}
+	members, err := s.store.Members(ctx)
+	if err != nil {
+		return err
+	}
+	live := make([]string, 0, len(members))
+	for _, member := range members {
+		if now.Before(member.Expires) {
+			live = append(live, member.ID)
+		}
+	}
+
+	ring := NewRing(s.id, live)
+	s.lock.Lock()
+	changed := !ring.Equal(s.ring)
+	if changed {
+		s.ring = ring
+	}
+	s.lock.Unlock()
+
+	if changed {
+		log.Infof("Shard members have changed to %v", ring.Members())
+		s.rebalance(ring)
+	}
+	return nil
+}
+
+// Run keeps refreshing the membership and the hash ring until the context is done, at which point the instance
+// leaves, so that the remaining instances take over its devices right away
+func (s *Sharder) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			leaveCtx, cancel := context.WithTimeout(context.Background(), s.interval)
+			if err := s.store.Leave(leaveCtx, s.id); err != nil {
+				log.Warnf("Unable to leave shard membership: %+v", err)
+			}
+			cancel()
+			return
+		case <-ticker.C:
+			if err := s.Refresh(ctx); err != nil {
+				log.Warnf("Unable to refresh shard membership: %+v", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package sharding implements consistent hashing of realm devices across topology discovery instances
+package sharding
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+)
+
+// Number of points each member occupies on the hash ring; more points spread the keys more evenly
+const virtualNodes = 128
+
+// Ring assigns keys to members via consistent hashing, so that only the keys of a joining or leaving member move
+// when the membership changes
+type Ring struct {
+	self    string
+	members []string
+	points  []uint32
+	owners  map[uint32]string
+}
+
+// NewRing creates a hash ring of the given members as seen by the member self; self is always part of the ring
+func NewRing(self string, members []string) *Ring {
+	r := &Ring{self: self, owners: make(map[uint32]string)}
+	unique := map[string]bool{self: true}
+	for _, member := range members {
+		unique[member] = true
+	}
+	for member := range unique {
+		r.members = append(r.members, member)
+	}
+	sort.Strings(r.members)
+
+	for _, member := range r.members {
+		for i := 0; i < virtualNodes; i++ {
+			point := hash(member + "#" + strconv.Itoa(i))
+			// On the off chance of a collision, the point goes to the lowest member for all members to agree
+			if _, ok := r.owners[point]; !ok {
+				r.owners[point] = member
+				r.points = append(r.points, point)
+			}
+		}
+	}
+	sort.Slice(r.points, func(i, j int) bool { return r.points[i] < r.points[j] })
+	return r
+}
+
+// NewEmptyRing creates a ring without any members as seen by the member self, which owns no keys; it stands in
+// until the live members have been learned
+func NewEmptyRing(self string) *Ring {
+	return &Ring{self: self, owners: make(map[uint32]string)}
+}
+
+// Self returns the member on whose behalf the ring was created
+func (r *Ring) Self() string {
+	return r.self
+}
+
+// Members returns the sorted members of the ring
+func (r *Ring) Members() []string {
+	return append([]string{}, r.members...)
+}
+
+// Owner returns the member owning the given key; empty if the ring has no members
+func (r *Ring) Owner(key string) string {
+	if len(r.points) == 0 {
+		return ""
+	}
+	h := hash(key)
+	i := sort.Search(len(r.points), func(i int) bool { return r.points[i] >= h })
+	if i == len(r.points) {
+		i = 0
+	}
+	return r.owners[r.points[i]]
+}
+
+// Owns returns true if the given key is owned by the member self
+func (r *Ring) Owns(key string) bool {
+	return r.Owner(key) == r.self
+}
+
+// Equal returns true if both rings have the same members
+func (r *Ring) Equal(other *Ring) bool {
+	if r == nil || other == nil {
+		return r == other
+	}
+	if len(r.members) != len(other.members) {
+		return false
+	}
+	for i, member := range r.members {
+		if other.members[i] != member {
+			return false
+		}
+	}
+	return true
+}
+
+func hash(key string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(key))
+	return h.Sum32()
+}
@@ -0,0 +1,101 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestRing(t *testing.T) {
+	ring := NewRing("a", []string{"b", "c", "a", "b"})
+	assert.Equal(t, []string{"a", "b", "c"}, ring.Members())
+	assert.Equal(t, "a", ring.Self())
+
+	// Keys are spread across all members, and all members agree on the owners
+	other := NewRing("b", []string{"a", "c"})
+	assert.True(t, ring.Equal(other))
+	counts := make(map[string]int)
+	for i := 0; i < 3000; i++ {
+		key := fmt.Sprintf("leaf%d", i)
+		owner := ring.Owner(key)
+		assert.Equal(t, owner, other.Owner(key))
+		assert.Equal(t, owner == "a", ring.Owns(key))
+		counts[owner]++
+	}
+	for _, member := range ring.Members() {
+		assert.Greater(t, counts[member], 500, member)
+	}
+
+	// When a member joins, only keys moving to the new member change owners
+	joined := NewRing("a", []string{"b", "c", "d"})
+	assert.False(t, ring.Equal(joined))
+	moved := 0
+	for i := 0; i < 3000; i++ {
+		key := fmt.Sprintf("leaf%d", i)
+		if owner := joined.Owner(key); owner != ring.Owner(key) {
+			assert.Equal(t, "d", owner)
+			moved++
+		}
+	}
+	assert.Greater(t, moved, 0)
+	assert.Less(t, moved, 1500)
+
+	// An empty ring owns nothing and is replaced by any ring of live members
+	empty := NewEmptyRing("a")
+	assert.Empty(t, empty.Members())
+	assert.Empty(t, empty.Owner("leaf1"))
+	assert.False(t, empty.Owns("leaf1"))
+	assert.False(t, empty.Equal(NewRing("a", nil)))
+}
+
+func TestSharder(t *testing.T) {
+	ctx := context.TODO()
+	dir := t.TempDir()
+	var ringA *Ring
+	a := NewSharder("a", NewFileMembershipStore(dir), time.Minute, func(ring *Ring) { ringA = ring })
+	b := NewSharder("b", NewFileMembershipStore(dir), time.Minute, func(ring *Ring) {})
+
+	// Without other members, the instance owns everything
+	assert.NoError(t, a.Refresh(ctx))
+	assert.Equal(t, []string{"a"}, ringA.Members())
+	assert.True(t, a.Ring().Owns("leaf1"))
+
+	// Joining members are picked up on the next refresh
+	assert.NoError(t, b.Refresh(ctx))
+	assert.Equal(t, []string{"a", "b"}, b.Ring().Members())
+	assert.NoError(t, a.Refresh(ctx))
+	assert.Equal(t, []string{"a", "b"}, ringA.Members())
+
+	// Unchanged membership does not rebalance
+	previous := ringA
+	assert.NoError(t, a.Refresh(ctx))
+	assert.Same(t, previous, ringA)
+
+	// Members which leave or whose membership expires are dropped
+	store := NewFileMembershipStore(dir)
+	assert.NoError(t, store.Heartbeat(ctx, "c", time.Now().Add(-time.Second)))
+	assert.NoError(t, store.Leave(ctx, "b"))
+	assert.NoError(t, a.Refresh(ctx))
+	assert.Equal(t, []string{"a"}, ringA.Members())
+
+	// Leaving is done when the sharder stops running
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		b.Run(runCtx)
+		close(done)
+	}()
+	cancel()
+	<-done
+	members, err := store.Members(ctx)
+	assert.NoError(t, err)
+	for _, member := range members {
+		assert.NotEqual(t, "b", member.ID)
+	}
+}
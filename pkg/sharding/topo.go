@@ -0,0 +1,97 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package sharding
+
+import (
+	"context"
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/onos-lib-go/pkg/errors"
+	"io"
+	"time"
+)
+
+const (
+	memberKind   = "discovery-instance"
+	groupLabel   = "discovery-group"
+	expiresLabel = "expires"
+)
+
+// Implementation of MembershipStore keeping each membership as an entity in onos-topo, labeled with the group of
+// instances sharing the realm
+type topoMembershipStore struct {
+	client topo.TopoClient
+	group  string
+}
+
+// NewTopoMembershipStore creates a membership store backed by onos-topo for the given group of instances
+func NewTopoMembershipStore(client topo.TopoClient, group string) MembershipStore {
+	return &topoMembershipStore{client: client, group: group}
+}
+
+func (s *topoMembershipStore) Heartbeat(ctx context.Context, id string, expires time.Time) error {
+	object := topo.NewEntity(s.memberID(id), memberKind)
+	object.Labels = map[string]string{groupLabel: s.group, expiresLabel: expires.UTC().Format(time.RFC3339Nano)}
+
+	resp, err := s.client.Get(ctx, &topo.GetRequest{ID: object.ID})
+	if err != nil {
+		if err = errors.FromGRPC(err); !errors.IsNotFound(err) {
+			return err
+		}
+		_, err = s.client.Create(ctx, &topo.CreateRequest{Object: object})
+		return errors.FromGRPC(err)
+	}
+	object.Revision = resp.Object.Revision
+	_, err = s.client.Update(ctx, &topo.UpdateRequest{Object: object})
+	return errors.FromGRPC(err)
+}
+
+func (s *topoMembershipStore) Members(ctx context.Context) ([]Member, error) {
+	filters := &topo.Filters{
+		KindFilter: &topo.Filter{Filter: &topo.Filter_Equal_{Equal_: &topo.EqualFilter{Value: memberKind}}},
+		LabelFilters: []*topo.Filter{{Key: groupLabel,
+			Filter: &topo.Filter_Equal_{Equal_: &topo.EqualFilter{Value: s.group}}}},
+	}
+	stream, err := s.client.Query(ctx, &topo.QueryRequest{Filters: filters})
+	if err != nil {
+		return nil, errors.FromGRPC(err)
+	}
+	members := make([]Member, 0)
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return members, nil
+		} else if err != nil {
+			return nil, errors.FromGRPC(err)
+		}
+		expires, err := time.Parse(time.RFC3339Nano, resp.Object.Labels[expiresLabel])
+		if err != nil {
+			log.Warnf("Membership %s has invalid expiration: %+v", resp.Object.ID, err)
+			continue
+		}
+		members = append(members, Member{ID: s.instanceID(resp.Object.ID), Expires: expires})
+	}
+}
+
+func (s *topoMembershipStore) Leave(ctx context.Context, id string) error {
+	_, err := s.client.Delete(ctx, &topo.DeleteRequest{ID: s.memberID(id)})
+	if err = errors.FromGRPC(err); err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// Returns the ID of the membership entity of the given instance
+func (s *topoMembershipStore) memberID(id string) topo.ID {
+	return topo.ID(s.prefix() + id)
+}
+
+// Returns the ID of the instance from the ID of its membership entity
+func (s *topoMembershipStore) instanceID(id topo.ID) string {
+	return string(id)[len(s.prefix()):]
+}
+
+func (s *topoMembershipStore) prefix() string {
+	return "topo-discovery-" + s.group + "-"
+}
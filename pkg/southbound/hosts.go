@@ -12,15 +12,56 @@ import (
 	"github.com/onosproject/onos-net-lib/pkg/stratum"
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"io"
+	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 )
 
 // Host is a simple representation of a host network interface discovered by the ONOS lite
 type Host struct {
 	MAC        string
-	IP         string
+	IPs        []*topo.IPAddress // IPv4 and IPv6 addresses, in the order reported
 	Port       uint32
+	VLAN       uint32 // zero if untagged
 	CreateTime uint64
+	LastSeen   time.Time // zero if not reported by the agent
+}
+
+// NewIPAddress returns the IP address with its family detected from its textual form
+func NewIPAddress(ip string) *topo.IPAddress {
+	address := &topo.IPAddress{IP: ip, Type: topo.IPAddress_IPV4}
+	if parsed := net.ParseIP(ip); parsed != nil && parsed.To4() == nil {
+		address.Type = topo.IPAddress_IPV6
+	}
+	return address
+}
+
+// AddIP adds the IP address to the host, unless the host has it already
+func (h *Host) AddIP(ip string) {
+	if len(ip) == 0 {
+		return
+	}
+	for _, address := range h.IPs {
+		if address.IP == ip {
+			return
+		}
+	}
+	h.IPs = append(h.IPs, NewIPAddress(ip))
+}
+
+// PrimaryIP returns the first IPv4 address of the host, or its first address if it has no IPv4 address, or nil if
+// it has no addresses at all
+func (h *Host) PrimaryIP() *topo.IPAddress {
+	for _, address := range h.IPs {
+		if address.Type == topo.IPAddress_IPV4 {
+			return address
+		}
+	}
+	if len(h.IPs) > 0 {
+		return h.IPs[0]
+	}
+	return nil
 }
 
 // HostReport provides results of a host query against the host local agent
@@ -38,6 +79,9 @@ type HostListener interface {
 // HostDiscovery is an abstraction of an entity capable of discovering hosts
 type HostDiscovery interface {
 	GetHosts(object *topo.Object, listener HostListener) (*HostReport, error)
+	ReleaseDevice(id topo.ID)
+	ReleaseAll()
+	IsMonitoring(id topo.ID) bool
 }
 
 // Implementation of HostDiscovery via gNMI against host agent
@@ -51,11 +95,15 @@ type gNMIHostDiscovery struct {
 type hostContext struct {
 	object    *topo.Object
 	agent     *stratum.GNMI
+	endpoint  *topo.Endpoint
 	agentID   string
 	listener  HostListener
 	ctx       context.Context
 	ctxCancel context.CancelFunc
 
+	// Indicates whether the host subscription stream is active
+	monitoring atomic.Bool
+
 	lock   sync.RWMutex
 	report *HostReport
 }
@@ -80,6 +128,7 @@ func (ld *gNMIHostDiscovery) GetHosts(object *topo.Object, listener HostListener
 		Path: []*gnmi.Path{gnmiutils.ToPath("state/host[mac=...]")},
 	})
 	if err != nil {
+		countFailure(object.ID, getOperation)
 		return nil, err
 	}
 	if len(resp.Notification) == 0 {
@@ -111,34 +160,76 @@ func (ld *gNMIHostDiscovery) getHostContext(object *topo.Object, listener HostLi
 		ld.hostContexts[object.ID] = ac
 	}
 
+	localAgents := &topo.LocalAgents{}
+	if err := object.GetAspect(localAgents); err != nil {
+		log.Warnf("Object %s doesn't have onos.topo.LocalAgents aspect", object.ID)
+		return nil, err
+	}
+
+	// If the host agent endpoint has changed since we connected, drop the connection and reconnect below
+	if ac.agent != nil && endpointChanged(ac.endpoint, localAgents.HostAgentEndpoint) {
+		log.Infof("Host local agent endpoint of %s has changed; reconnecting...", object.ID)
+		ac.disconnect()
+	}
+
 	// If we haven't established a host context yet, do so.
 	if ac.agent == nil {
-		// Connect to the host using gNMI
-		var err error
-		localAgents := &topo.LocalAgents{}
-		if err = object.GetAspect(localAgents); err != nil {
-			log.Warnf("Object %s doesn't have onos.topo.LocalAgents aspect", object.ID)
-			return nil, err
-		}
-
 		// Connect to the device's host local agent using gNMI
+		var err error
+		ac.object = object
 		ac.agent, err = stratum.NewGNMI(string(object.ID), localAgents.HostAgentEndpoint, true)
 		if err != nil {
 			log.Warnf("Unable to connect to Stratum host local agent gNMI %s: %+v", object.ID, err)
+			countFailure(object.ID, connectOperation)
 			return nil, err
 		}
+		ac.endpoint = localAgents.HostAgentEndpoint
 
 		// Get the agent ID
 		if ac.agentID, err = getAgentID(ac.agent); err != nil {
 			log.Warnf("Unable to retrieve agent ID for %s: %+v", ac.object.ID, err)
+			countFailure(object.ID, getOperation)
 			return nil, err
 		}
 	}
 	return ac, nil
 }
 
+// ReleaseDevice stops the host monitor of the specified device and disconnects from its host agent
+func (ld *gNMIHostDiscovery) ReleaseDevice(id topo.ID) {
+	ld.lock.Lock()
+	hc, ok := ld.hostContexts[id]
+	delete(ld.hostContexts, id)
+	ld.lock.Unlock()
+
+	if ok {
+		hc.disconnect()
+	}
+}
+
+// ReleaseAll stops all host monitors and disconnects from all host agents
+func (ld *gNMIHostDiscovery) ReleaseAll() {
+	ld.lock.Lock()
+	contexts := ld.hostContexts
+	ld.hostContexts = make(map[topo.ID]*hostContext, len(contexts))
+	ld.lock.Unlock()
+
+	for _, hc := range contexts {
+		hc.disconnect()
+	}
+}
+
+// IsMonitoring returns true if the host monitor of the specified device has an active subscription stream
+func (ld *gNMIHostDiscovery) IsMonitoring(id topo.ID) bool {
+	ld.lock.RLock()
+	defer ld.lock.RUnlock()
+	hc, ok := ld.hostContexts[id]
+	return ok && hc.monitoring.Load()
+}
+
 func (hc *hostContext) processHostNotification(notification *gnmi.Notification, hosts map[string]*Host) {
 	var host *Host
+	addressed := make(map[string]bool) // hosts whose addresses are carried by the notification
 	for _, update := range notification.Update {
 		if update.Path.Elem[1].Name == "host" { // FIXME elsewhere: this is needed to only pick-up host updates
 			mac := getMacAddress(update.Path)
@@ -147,10 +238,22 @@ func (hc *hostContext) processHostNotification(notification *gnmi.Notification,
 			switch update.Path.Elem[last].Name {
 			case "port":
 				host.Port = uint32(update.Val.GetIntVal())
-			case "ip-address":
-				host.IP = update.Val.GetStringVal()
+			case "vlan":
+				host.VLAN = uint32(update.Val.GetIntVal())
+			case "ip-address", "ip-addresses":
+				// Addresses may come as a single leaf per address or as a leaf-list; either replaces the known ones
+				if !addressed[mac] {
+					host.IPs = nil
+					addressed[mac] = true
+				}
+				host.AddIP(update.Val.GetStringVal())
+				for _, element := range update.Val.GetLeaflistVal().GetElement() {
+					host.AddIP(element.GetStringVal())
+				}
 			case "create-time":
 				host.CreateTime = update.Val.GetUintVal()
+			case "last-seen":
+				host.LastSeen = time.Unix(0, int64(update.Val.GetUintVal()))
 			}
 		}
 	}
@@ -192,6 +295,16 @@ func (hc *hostContext) stopMonitor() {
 	}
 }
 
+// Stops the host monitor and disconnects from the host agent
+func (hc *hostContext) disconnect() {
+	hc.stopMonitor()
+	if hc.agent != nil {
+		hc.agent.Disconnect()
+		hc.agent = nil
+	}
+	hc.agentID = ""
+}
+
 // Issues subscribe request for host state updates and monitors the stream for update notifications
 func (hc *hostContext) monitorHostChanges() {
 	log.Infof("Host monitor started")
@@ -199,6 +312,7 @@ func (hc *hostContext) monitorHostChanges() {
 	stream, err := hc.agent.Client.Subscribe(hc.ctx)
 	if err != nil {
 		log.Warn("Unable to subscribe for host changes: %+v", err)
+		countFailure(hc.object.ID, subscribeOperation)
 		return
 	}
 
@@ -208,14 +322,17 @@ func (hc *hostContext) monitorHostChanges() {
 			Subscribe: &gnmi.SubscriptionList{Subscription: subscriptions},
 		}}); err != nil {
 		log.Warn("Unable to send subscription request for host changes: %+v", err)
+		countFailure(hc.object.ID, subscribeOperation)
 		return
 	}
+	defer monitorStarted(&hc.monitoring, hostsStream)()
 
 	for {
 		resp, err := stream.Recv()
 		if err != nil {
 			if err != io.EOF {
 				log.Warn("Unable to read subscription response for host changes: %+v", err)
+				countFailure(hc.object.ID, subscribeOperation)
 			}
 			log.Infof("Host monitor stopped")
 			return
@@ -238,8 +355,17 @@ func (hc *hostContext) processHostResponse(resp *gnmi.SubscribeResponse) {
 		}
 	}
 
-	// Handle additions
+	// Handle additions and changes; updates may carry only the changed leaves, so start from the reported hosts
 	hosts := make(map[string]*Host, 0)
+	for _, update := range resp.GetUpdate().GetUpdate() {
+		if len(update.Path.Elem) > 1 && update.Path.Elem[1].Name == "host" {
+			mac := getMacAddress(update.Path)
+			if host, ok := hc.report.Hosts[mac]; ok && hosts[mac] == nil {
+				known := *host
+				hosts[mac] = &known
+			}
+		}
+	}
 	hc.processHostNotification(resp.GetUpdate(), hosts)
 	for _, host := range hosts {
 		hc.report.Hosts[host.MAC] = host // update the most recent report with this new host
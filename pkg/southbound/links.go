@@ -14,6 +14,7 @@ import (
 	"io"
 	"strconv"
 	"sync"
+	"sync/atomic"
 )
 
 // Link holds information about an ingress link
@@ -40,6 +41,9 @@ type IngressLinkListener interface {
 // IngressLinkDiscovery is an abstraction of an entity capable of discovering ingress links
 type IngressLinkDiscovery interface {
 	GetIngressLinks(object *topo.Object, listener IngressLinkListener) (*LinkReport, error)
+	ReleaseDevice(id topo.ID)
+	ReleaseAll()
+	IsMonitoring(id topo.ID) bool
 }
 
 // Implementation of IngressLinkDiscovery via gNMI against link agent
@@ -53,11 +57,15 @@ type gNMILinkDiscovery struct {
 type agentContext struct {
 	object    *topo.Object
 	agent     *stratum.GNMI
+	endpoint  *topo.Endpoint
 	agentID   string
 	listener  IngressLinkListener
 	ctx       context.Context
 	ctxCancel context.CancelFunc
 
+	// Indicates whether the link subscription stream is active
+	monitoring atomic.Bool
+
 	lock   sync.RWMutex
 	report *LinkReport
 }
@@ -84,6 +92,7 @@ func (ld *gNMILinkDiscovery) GetIngressLinks(object *topo.Object, listener Ingre
 			Path: []*gnmi.Path{gnmiutils.ToPath("state/link[port=...]")},
 		})
 		if err != nil {
+			countFailure(object.ID, getOperation)
 			return nil, err
 		}
 		if len(resp.Notification) == 0 {
@@ -115,22 +124,30 @@ func (ld *gNMILinkDiscovery) getAgentContext(object *topo.Object, listener Ingre
 		ld.agentContexts[object.ID] = ac
 	}
 
+	localAgents := &topo.LocalAgents{}
+	if err := object.GetAspect(localAgents); err != nil {
+		log.Warnf("Object %s doesn't have onos.topo.LocalAgents aspect", object.ID)
+		return nil, err
+	}
+
+	// If the link agent endpoint has changed since we connected, drop the connection and reconnect below
+	if ac.agent != nil && endpointChanged(ac.endpoint, localAgents.LinkAgentEndpoint) {
+		log.Infof("Link local agent endpoint of %s has changed; reconnecting...", object.ID)
+		ac.disconnect()
+	}
+
 	// If we haven't established an agent context yet, do so.
 	if ac.agent == nil {
-		// Connect to the agent using gNMI
-		var err error
-		localAgents := &topo.LocalAgents{}
-		if err = object.GetAspect(localAgents); err != nil {
-			log.Warnf("Object %s doesn't have onos.topo.LocalAgents aspect", object.ID)
-			return nil, err
-		}
-
 		// Connect to the device's link local agent using gNMI
+		var err error
+		ac.object = object
 		ac.agent, err = stratum.NewGNMI(string(object.ID), localAgents.LinkAgentEndpoint, true)
 		if err != nil {
 			log.Warnf("Unable to connect to Stratum link local agent gNMI %s: %+v", object.ID, err)
+			countFailure(object.ID, connectOperation)
 			return nil, err
 		}
+		ac.endpoint = localAgents.LinkAgentEndpoint
 	}
 
 	if ac.agentID == "" {
@@ -138,12 +155,45 @@ func (ld *gNMILinkDiscovery) getAgentContext(object *topo.Object, listener Ingre
 		var err error
 		if ac.agentID, err = getAgentID(ac.agent); err != nil {
 			log.Warnf("Unable to retrieve agent ID for %s: %+v", ac.object.ID, err)
+			countFailure(object.ID, getOperation)
 			return nil, err
 		}
 	}
 	return ac, nil
 }
 
+// ReleaseDevice stops the link monitor of the specified device and disconnects from its link agent
+func (ld *gNMILinkDiscovery) ReleaseDevice(id topo.ID) {
+	ld.lock.Lock()
+	ac, ok := ld.agentContexts[id]
+	delete(ld.agentContexts, id)
+	ld.lock.Unlock()
+
+	if ok {
+		ac.disconnect()
+	}
+}
+
+// ReleaseAll stops all link monitors and disconnects from all link agents
+func (ld *gNMILinkDiscovery) ReleaseAll() {
+	ld.lock.Lock()
+	contexts := ld.agentContexts
+	ld.agentContexts = make(map[topo.ID]*agentContext, len(contexts))
+	ld.lock.Unlock()
+
+	for _, ac := range contexts {
+		ac.disconnect()
+	}
+}
+
+// IsMonitoring returns true if the link monitor of the specified device has an active subscription stream
+func (ld *gNMILinkDiscovery) IsMonitoring(id topo.ID) bool {
+	ld.lock.RLock()
+	defer ld.lock.RUnlock()
+	ac, ok := ld.agentContexts[id]
+	return ok && ac.monitoring.Load()
+}
+
 func getAgentID(device *stratum.GNMI) (string, error) {
 	resp, err := device.Client.Get(device.Context, &gnmi.GetRequest{
 		Path: []*gnmi.Path{gnmiutils.ToPath("state/agent-id")},
@@ -227,6 +277,16 @@ func (ac *agentContext) stopMonitor() {
 	}
 }
 
+// Stops the link monitor and disconnects from the link agent; the agent ID will be re-discovered on reconnect
+func (ac *agentContext) disconnect() {
+	ac.stopMonitor()
+	if ac.agent != nil {
+		ac.agent.Disconnect()
+		ac.agent = nil
+	}
+	ac.agentID = ""
+}
+
 // Issues subscribe request for port state updates and monitors the stream for update notifications
 func (ac *agentContext) monitorLinkChanges() {
 	log.Infof("Link monitor started")
@@ -234,6 +294,7 @@ func (ac *agentContext) monitorLinkChanges() {
 	stream, err := ac.agent.Client.Subscribe(ac.ctx)
 	if err != nil {
 		log.Warn("Unable to subscribe for link changes: %+v", err)
+		countFailure(ac.object.ID, subscribeOperation)
 		return
 	}
 
@@ -243,14 +304,17 @@ func (ac *agentContext) monitorLinkChanges() {
 			Subscribe: &gnmi.SubscriptionList{Subscription: subscriptions},
 		}}); err != nil {
 		log.Warn("Unable to send subscription request for link changes: %+v", err)
+		countFailure(ac.object.ID, subscribeOperation)
 		return
 	}
+	defer monitorStarted(&ac.monitoring, linksStream)()
 
 	for {
 		resp, err := stream.Recv()
 		if err != nil {
 			if err != io.EOF {
 				log.Warn("Unable to read subscription response for link changes: %+v", err)
+				countFailure(ac.object.ID, subscribeOperation)
 			}
 			log.Infof("Link monitor stopped")
 			return
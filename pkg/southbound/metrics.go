@@ -0,0 +1,43 @@
+// SPDX-FileCopyrightText: 2023-present Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package southbound
+
+import (
+	"github.com/onosproject/onos-api/go/onos/topo"
+	"github.com/onosproject/topo-discovery/pkg/metrics"
+	"sync/atomic"
+)
+
+const (
+	connectOperation   = "connect"
+	getOperation       = "get"
+	subscribeOperation = "subscribe"
+
+	portsStream = "ports"
+	linksStream = "links"
+	hostsStream = "hosts"
+)
+
+var (
+	gnmiFailures = metrics.NewCounter("topo_discovery_gnmi_failures_total",
+		"Number of failed gNMI connection attempts, get requests and subscriptions, by device", "device", "operation")
+	activeMonitors = metrics.NewGauge("topo_discovery_active_monitors",
+		"Number of active gNMI subscription streams monitoring port status, link and host changes", "stream")
+)
+
+// Counts a failed gNMI operation against the specified device
+func countFailure(id topo.ID, operation string) {
+	gnmiFailures.Inc(string(id), operation)
+}
+
+// Marks the monitor subscription stream as active; returns a function that marks it as inactive again
+func monitorStarted(monitoring *atomic.Bool, stream string) func() {
+	monitoring.Store(true)
+	activeMonitors.Add(1, stream)
+	return func() {
+		monitoring.Store(false)
+		activeMonitors.Add(-1, stream)
+	}
+}
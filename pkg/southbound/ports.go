@@ -16,6 +16,7 @@ import (
 	"github.com/openconfig/gnmi/proto/gnmi"
 	"io"
 	"sync"
+	"sync/atomic"
 )
 
 var log = logging.GetLogger("southbound")
@@ -28,6 +29,32 @@ type PortStatusListener interface {
 // PortDiscovery is an abstraction of an entity capable of discovering device ports
 type PortDiscovery interface {
 	GetPorts(object *topo.Object, listener PortStatusListener) (map[string]*topo.Port, error)
+	GetPortAttributes(id topo.ID) map[string]*PortAttributes
+	GetPortCounters(id topo.ID) (map[uint32]*PortCounters, error)
+	ReleaseDevice(id topo.ID)
+	ReleaseAll()
+	IsMonitoring(id topo.ID) bool
+}
+
+// PortAttributes holds the port attributes which do not fit the onos.topo.Port aspect, i.e. the MTU, hardware MAC,
+// description, admin status, negotiated speed and duplex mode of the port
+type PortAttributes struct {
+	MTU             uint32 `json:"mtu,omitempty"`
+	MAC             string `json:"mac,omitempty"`
+	Description     string `json:"description,omitempty"`
+	AdminStatus     string `json:"admin_status,omitempty"`
+	NegotiatedSpeed string `json:"negotiated_speed,omitempty"`
+	Duplex          string `json:"duplex,omitempty"`
+}
+
+// PortCounters holds the interface counters of a port
+type PortCounters struct {
+	InOctets    uint64 `json:"in_octets"`
+	OutOctets   uint64 `json:"out_octets"`
+	InErrors    uint64 `json:"in_errors"`
+	OutErrors   uint64 `json:"out_errors"`
+	InDiscards  uint64 `json:"in_discards"`
+	OutDiscards uint64 `json:"out_discards"`
 }
 
 // Implementation of PortDiscovery using gNMI against Stratum device agent
@@ -41,10 +68,15 @@ type gNMIPortDiscovery struct {
 type deviceContext struct {
 	object    *topo.Object
 	device    *stratum.GNMI
+	endpoint  *topo.Endpoint
 	listener  PortStatusListener
 	ctx       context.Context
 	ctxCancel context.CancelFunc
-	ports     map[string]*topo.Port
+
+	// Indicates whether the port status subscription stream is active
+	monitoring atomic.Bool
+	ports      map[string]*topo.Port
+	attributes map[string]*PortAttributes
 }
 
 // NewGNMIPortDiscovery returns new port discovery based on gNMI
@@ -64,10 +96,10 @@ func (pd *gNMIPortDiscovery) GetPorts(object *topo.Object, listener PortStatusLi
 		Path: []*gnmi.Path{
 			gnmiutils.ToPath("interfaces/interface[name=...]/state"),
 			gnmiutils.ToPath("interfaces/interface[name=...]/config"),
-			gnmiutils.ToPath("interfaces/interface[name=...]/ethernet/config"),
 		},
 	})
 	if err != nil {
+		countFailure(object.ID, getOperation)
 		return nil, err
 	}
 	if len(resp.Notification) == 0 {
@@ -75,9 +107,11 @@ func (pd *gNMIPortDiscovery) GetPorts(object *topo.Object, listener PortStatusLi
 	}
 
 	ports := make(map[string]*topo.Port)
+	attributes := make(map[string]*PortAttributes)
 	for _, notification := range resp.Notification {
 		for _, update := range notification.Update {
 			port := getPort(ports, update.Path.Elem[1].Key["name"])
+			attrs := getPortAttributes(attributes, update.Path.Elem[1].Key["name"])
 			last := len(update.Path.Elem) - 1
 			switch update.Path.Elem[last].Name {
 			case "ifindex":
@@ -88,17 +122,25 @@ func (pd *gNMIPortDiscovery) GetPorts(object *topo.Object, listener PortStatusLi
 				port.Status = update.Val.GetStringVal()
 			case "last-change":
 				port.LastChange = update.Val.GetUintVal()
-			case "port-speed":
-				port.Speed = update.Val.GetStringVal()
 			case "enabled":
 				port.Enabled = update.Val.GetBoolVal()
+			case "mtu":
+				attrs.MTU = uint32(update.Val.GetUintVal())
+			case "description":
+				attrs.Description = update.Val.GetStringVal()
+			case "admin-status":
+				attrs.AdminStatus = update.Val.GetStringVal()
 			}
 		}
 	}
+	dc.getEthernetAttributes(ports, attributes)
 
 	// Trigger monitor restart only if port numbers change; record new ports map every time though
+	pd.lock.Lock()
 	restart := len(dc.ports) != len(ports)
 	dc.ports = ports
+	dc.attributes = attributes
+	pd.lock.Unlock()
 
 	// Once ports are discovered kick off a port-status monitor, if necessary
 	dc.startMonitor(restart)
@@ -106,6 +148,123 @@ func (pd *gNMIPortDiscovery) GetPorts(object *topo.Object, listener PortStatusLi
 	return ports, nil
 }
 
+// Adds the speed and ethernet attributes of the ports obtained via gNMI get request on
+// /interfaces/interface[name=...]/ethernet query; as not all devices support these paths, the ports are discovered
+// without these attributes if they cannot be obtained
+func (dc *deviceContext) getEthernetAttributes(ports map[string]*topo.Port, attributes map[string]*PortAttributes) {
+	resp, err := dc.device.Client.Get(dc.device.Context, &gnmi.GetRequest{
+		Path: []*gnmi.Path{
+			gnmiutils.ToPath("interfaces/interface[name=...]/ethernet/config"),
+			gnmiutils.ToPath("interfaces/interface[name=...]/ethernet/state"),
+		},
+	})
+	if err != nil {
+		log.Debugf("Unable to get ethernet attributes of ports of %s: %+v", dc.object.ID, err)
+		return
+	}
+
+	for _, notification := range resp.Notification {
+		for _, update := range notification.Update {
+			name := update.Path.Elem[1].Key["name"]
+			port, ok := ports[name]
+			if !ok {
+				continue
+			}
+			attrs := getPortAttributes(attributes, name)
+			switch update.Path.Elem[len(update.Path.Elem)-1].Name {
+			case "port-speed":
+				port.Speed = update.Val.GetStringVal()
+			case "hw-mac-address":
+				attrs.MAC = update.Val.GetStringVal()
+			case "mac-address":
+				// Configured MAC is used only if the hardware MAC is not reported
+				if attrs.MAC == "" {
+					attrs.MAC = update.Val.GetStringVal()
+				}
+			case "negotiated-port-speed":
+				attrs.NegotiatedSpeed = update.Val.GetStringVal()
+			case "negotiated-duplex-mode":
+				attrs.Duplex = update.Val.GetStringVal()
+			case "duplex-mode":
+				// Configured duplex mode is used only if the negotiated one is not reported
+				if attrs.Duplex == "" {
+					attrs.Duplex = update.Val.GetStringVal()
+				}
+			}
+		}
+	}
+}
+
+// GetPortAttributes returns the attributes of the ports of the specified device, keyed by port name, as obtained by
+// its last GetPorts call
+func (pd *gNMIPortDiscovery) GetPortAttributes(id topo.ID) map[string]*PortAttributes {
+	pd.lock.RLock()
+	defer pd.lock.RUnlock()
+	if dc, ok := pd.deviceContexts[id]; ok {
+		return dc.attributes
+	}
+	return nil
+}
+
+// GetPortCounters returns the interface counters of the ports of the specified device, keyed by port number,
+// obtained via gNMI get request on /interfaces/interface[name=...]/state/counters query; the ports must have been
+// discovered via GetPorts first
+func (pd *gNMIPortDiscovery) GetPortCounters(id topo.ID) (map[uint32]*PortCounters, error) {
+	pd.lock.RLock()
+	dc, ok := pd.deviceContexts[id]
+	var device *stratum.GNMI
+	numbers := make(map[string]uint32)
+	if ok {
+		device = dc.device
+		for name, port := range dc.ports {
+			numbers[name] = port.Number
+		}
+	}
+	pd.lock.RUnlock()
+	if device == nil {
+		return nil, errors.NewNotFound("ports of device %s have not been discovered", id)
+	}
+
+	resp, err := device.Client.Get(device.Context, &gnmi.GetRequest{
+		Path: []*gnmi.Path{gnmiutils.ToPath("interfaces/interface[name=...]/state/counters")},
+	})
+	if err != nil {
+		countFailure(id, getOperation)
+		return nil, err
+	}
+
+	counters := make(map[uint32]*PortCounters)
+	for _, notification := range resp.Notification {
+		for _, update := range notification.Update {
+			number, ok := numbers[update.Path.Elem[1].Key["name"]]
+			if !ok {
+				continue
+			}
+			portCounters, ok := counters[number]
+			if !ok {
+				portCounters = &PortCounters{}
+				counters[number] = portCounters
+			}
+			value := update.Val.GetUintVal()
+			switch update.Path.Elem[len(update.Path.Elem)-1].Name {
+			case "in-octets":
+				portCounters.InOctets = value
+			case "out-octets":
+				portCounters.OutOctets = value
+			case "in-errors":
+				portCounters.InErrors = value
+			case "out-errors":
+				portCounters.OutErrors = value
+			case "in-discards":
+				portCounters.InDiscards = value
+			case "out-discards":
+				portCounters.OutDiscards = value
+			}
+		}
+	}
+	return counters, nil
+}
+
 func (pd *gNMIPortDiscovery) getDeviceContext(object *topo.Object, listener PortStatusListener) (*deviceContext, error) {
 	pd.lock.Lock()
 	defer pd.lock.Unlock()
@@ -115,19 +274,75 @@ func (pd *gNMIPortDiscovery) getDeviceContext(object *topo.Object, listener Port
 		pd.deviceContexts[object.ID] = dc
 	}
 
+	// If the gNMI endpoint has changed since we connected, drop the connection and reconnect below
+	endpoint := gnmiEndpoint(object)
+	if dc.device != nil && endpointChanged(dc.endpoint, endpoint) {
+		log.Infof("Stratum device gNMI endpoint of %s has changed; reconnecting...", object.ID)
+		dc.disconnect()
+	}
+
 	// If we haven't established a device context yet, do so.
 	if dc.device == nil {
 		// Connect to the device using gNMI
 		var err error
+		dc.object = object
 		dc.device, err = stratum.NewStratumGNMI(object, true)
 		if err != nil {
 			log.Warnf("Unable to connect to Stratum device gNMI %s: %+v", object.ID, err)
+			countFailure(object.ID, connectOperation)
 			return nil, err
 		}
+		dc.endpoint = endpoint
 	}
 	return dc, nil
 }
 
+// Returns the gNMI endpoint from the object's onos.topo.StratumAgents aspect, if available
+func gnmiEndpoint(object *topo.Object) *topo.Endpoint {
+	stratumAgents := &topo.StratumAgents{}
+	if err := object.GetAspect(stratumAgents); err != nil {
+		return nil
+	}
+	return stratumAgents.GNMIEndpoint
+}
+
+// Returns true if the two endpoints differ in address or port
+func endpointChanged(a *topo.Endpoint, b *topo.Endpoint) bool {
+	return a.GetAddress() != b.GetAddress() || a.GetPort() != b.GetPort()
+}
+
+// ReleaseDevice stops the port status monitor of the specified device and disconnects from it
+func (pd *gNMIPortDiscovery) ReleaseDevice(id topo.ID) {
+	pd.lock.Lock()
+	dc, ok := pd.deviceContexts[id]
+	delete(pd.deviceContexts, id)
+	pd.lock.Unlock()
+
+	if ok {
+		dc.disconnect()
+	}
+}
+
+// ReleaseAll stops all port status monitors and disconnects from all devices
+func (pd *gNMIPortDiscovery) ReleaseAll() {
+	pd.lock.Lock()
+	contexts := pd.deviceContexts
+	pd.deviceContexts = make(map[topo.ID]*deviceContext, len(contexts))
+	pd.lock.Unlock()
+
+	for _, dc := range contexts {
+		dc.disconnect()
+	}
+}
+
+// IsMonitoring returns true if the port status monitor of the specified device has an active subscription stream
+func (pd *gNMIPortDiscovery) IsMonitoring(id topo.ID) bool {
+	pd.lock.RLock()
+	defer pd.lock.RUnlock()
+	dc, ok := pd.deviceContexts[id]
+	return ok && dc.monitoring.Load()
+}
+
 func getPort(ports map[string]*topo.Port, id string) *topo.Port {
 	port, ok := ports[id]
 	if !ok {
@@ -137,6 +352,15 @@ func getPort(ports map[string]*topo.Port, id string) *topo.Port {
 	return port
 }
 
+func getPortAttributes(attributes map[string]*PortAttributes, id string) *PortAttributes {
+	attrs, ok := attributes[id]
+	if !ok {
+		attrs = &PortAttributes{}
+		attributes[id] = attrs
+	}
+	return attrs
+}
+
 // Starts the port monitor if not already started or if restart is requested
 func (dc *deviceContext) startMonitor(restart bool) {
 	if dc.ctxCancel == nil || restart {
@@ -155,6 +379,17 @@ func (dc *deviceContext) stopMonitor() {
 	}
 }
 
+// Stops the port monitor and disconnects from the device
+func (dc *deviceContext) disconnect() {
+	dc.stopMonitor()
+	if dc.device != nil {
+		dc.device.Disconnect()
+		dc.device = nil
+	}
+	dc.ports = nil
+	dc.attributes = nil
+}
+
 // Issues subscribe request for port state updates and monitors the stream for update notifications
 func (dc *deviceContext) monitorPortStatus() {
 	log.Infof("Port status monitor started")
@@ -162,6 +397,7 @@ func (dc *deviceContext) monitorPortStatus() {
 	stream, err := dc.device.Client.Subscribe(dc.ctx)
 	if err != nil {
 		log.Warn("Unable to subscribe for port state updates: %+v", err)
+		countFailure(dc.object.ID, subscribeOperation)
 		return
 	}
 
@@ -176,14 +412,17 @@ func (dc *deviceContext) monitorPortStatus() {
 			Subscribe: &gnmi.SubscriptionList{Subscription: subscriptions},
 		}}); err != nil {
 		log.Warn("Unable to send subscription request for port state updates: %+v", err)
+		countFailure(dc.object.ID, subscribeOperation)
 		return
 	}
+	defer monitorStarted(&dc.monitoring, portsStream)()
 
 	for {
 		resp, err := stream.Recv()
 		if err != nil {
 			if err != io.EOF {
 				log.Warn("Unable to read subscription response for port state updates: %+v", err)
+				countFailure(dc.object.ID, subscribeOperation)
 			}
 			log.Infof("Port status monitor stopped")
 			return